@@ -94,9 +94,18 @@ func main(){
 		}
 	)
 
+	mux, muxErr := route.Mux(&ctx, handlers, route.Chains(startup.SetupRateLimitStore(ctx, rtp, pool)))
+	if muxErr != nil {
+		slog.LogAttrs(ctx, slog.LevelError, "register the routes",
+			slog.String("error", muxErr.Error()),
+		)
+
+		panic(muxErr)
+	}
+
 	server := &http.Server{
 		Addr        :	fmt.Sprintf(":%d", *rtp.HttpPort),
-		Handler     :	route.Mux(&ctx, handlers),
+		Handler     :	mux,
 		BaseContext :	func(_ net.Listener) context.Context {
 					return db.NewContext(
 						context.Background(),
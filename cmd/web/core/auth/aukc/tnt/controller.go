@@ -5,11 +5,13 @@ import (
 	"fmt"
 	"log/slog"
 	"net/http"
+	"strings"
 )
 
 import (
 	"github.com/andrewah64/base-app-client/internal/common/core/session"
 	"github.com/andrewah64/base-app-client/internal/web/core/error"
+	"github.com/andrewah64/base-app-client/internal/web/core/passkey"
 	"github.com/andrewah64/base-app-client/internal/web/core/ui/data/form"
 	"github.com/andrewah64/base-app-client/internal/web/core/ui/data/page"
 	"github.com/andrewah64/base-app-client/internal/web/core/ui/html"
@@ -20,6 +22,14 @@ import (
 	"github.com/jackc/pgx/v5/pgconn"
 )
 
+import (
+	"github.com/go-webauthn/webauthn/protocol"
+)
+
+import (
+	"github.com/google/uuid"
+)
+
 func Get(rw http.ResponseWriter, r *http.Request){
 	ctx := r.Context()
 
@@ -120,8 +130,50 @@ func Patch(rw http.ResponseWriter, r *http.Request){
 	pdcId            := form.VInt  (r, "aukc-tnt-mod-pdc-id")
 	puvRegId         := form.VInt  (r, "aukc-tnt-mod-puv-reg-id")
 	puvAtnId         := form.VInt  (r, "aukc-tnt-mod-puv-atn-id")
+	papId            := form.VInt  (r, "aukc-tnt-mod-pap-id")
+	palId            := form.VInt  (r, "aukc-tnt-mod-pal-id")
 	uts              := form.VTime (r, "aukc-tnt-mod-uts")
 
+	// AAGUIDs are entered one to a line and kept in their canonical form
+	aaguidTxt := strings.Fields(form.VText(r, "aukc-tnt-mod-aaguid"))
+
+	var aukcAaguid []string = make([]string, len(aaguidTxt))
+	for i, v := range aaguidTxt {
+		id, idErr := uuid.Parse(v)
+		if idErr != nil {
+			notification.Toast(ctx, slog.Default(), rw, r, "error" , &map[string]string{"Message" : data.T("web-core-auth-aukc-tnt-mod-form.warning-input-aukc-aaguid-invalid", "aaguid", v)}, data)
+			return
+		}
+
+		aukcAaguid[i] = id.String()
+	}
+
+	optsRs, optsRsErr := Opts(&ctx, ssd.Logger, ssd.Conn, ssd.TntId)
+	if optsRsErr != nil {
+		error.IntSrv(ctx, rw, optsRsErr)
+		return
+	}
+
+	// browsers strip the attestation statement unless it is asked for, which would leave nothing to verify against FIDO metadata
+	if OptValue(optsRs, "pap", papId) == passkey.PapMds {
+		switch protocol.ConveyancePreference(OptValue(optsRs, "pka", pkaId)) {
+			case protocol.PreferDirectAttestation, protocol.PreferEnterpriseAttestation:
+			default:
+				notification.Toast(ctx, slog.Default(), rw, r, "error" , &map[string]string{"Message" : data.T("web-core-auth-aukc-tnt-mod-form.warning-input-aukc-pap-pka-invalid")}, data)
+				return
+		}
+	}
+
+	// an authenticator whose attestation isn't checked can claim any AAGUID,
+	// so a list of them would keep out only the honest ones
+	switch OptValue(optsRs, "pal", palId) {
+		case passkey.PalAllow, passkey.PalDeny:
+			if OptValue(optsRs, "pap", papId) != passkey.PapMds {
+				notification.Toast(ctx, slog.Default(), rw, r, "error" , &map[string]string{"Message" : data.T("web-core-auth-aukc-tnt-mod-form.warning-input-aukc-pal-pap-invalid")}, data)
+				return
+			}
+	}
+
 	pkgId, pkgIdErr := form.VIntArray  (r, "aukc-tnt-mod-pkg-id")
 	if pkgIdErr != nil {
 		error.IntSrv(ctx, rw, pkgIdErr)
@@ -149,6 +201,9 @@ func Patch(rw http.ResponseWriter, r *http.Request){
 		slog.Int  ("pdcId"           , pktId),
 		slog.Int  ("puvRegId"        , puvRegId),
 		slog.Int  ("puvAtnId"        , puvAtnId),
+		slog.Int  ("papId"           , papId),
+		slog.Int  ("palId"           , palId),
+		slog.Any  ("aukcAaguid"      , aukcAaguid),
 		slog.Any  ("pkgId"           , pkgId),
 		slog.Any  ("pahId"           , pahId),
 		slog.Any  ("prhId"           , prhId),
@@ -159,16 +214,22 @@ func Patch(rw http.ResponseWriter, r *http.Request){
 		"OLOCK",
 	}
 
-	patchErr := PatchAukc(&ctx, ssd.Logger, ssd.Conn, ssd.TntId, aukcAurNmMinLen, aukcAurNmMaxLen, aukcEnabled, pkaId, pktId, pdcId, puvRegId, puvAtnId, pkgId, prhId, pahId, data.User.AurNm, uts, exptErrs)
+	patchErr := PatchAukc(&ctx, ssd.Logger, ssd.Conn, ssd.TntId, aukcAurNmMinLen, aukcAurNmMaxLen, aukcEnabled, pkaId, pktId, pdcId, puvRegId, puvAtnId, papId, palId, aukcAaguid, pkgId, prhId, pahId, data.User.AurNm, uts, exptErrs)
 	if patchErr != nil{
 		var pgErr *pgconn.PgError
 
 		if errors.As(patchErr, &pgErr) {
 			switch pgErr.Code {
 				case "OLOCK":
-					currentUrl := r.Header.Get("HX-Current-URL")
+					currentUrl := html.CurrentURL(r)
 
-					rw.Header().Set("HX-Location", fmt.Sprintf(`{"path":"%v", "target":"#main", "select":"#content", "swap" : "innerHTML show:window:top", "values":{"ntf": "web-core-auth-aukc-tnt-mod-form.warning-input-aukc-olock-error", "lvl": "error"}}`, currentUrl))
+					html.Locate(rw, r, html.Location{
+						Path   : currentUrl,
+						Target : "#main",
+						Select : "#content",
+						Swap   : "innerHTML show:window:top",
+						Values : map[string]string{"ntf": "web-core-auth-aukc-tnt-mod-form.warning-input-aukc-olock-error", "lvl": "error"},
+					})
 
 				default:
 					slog.LogAttrs(ctx, slog.LevelError, "Patch::unexpected error",
@@ -181,6 +242,9 @@ func Patch(rw http.ResponseWriter, r *http.Request){
 						slog.Int   ("pdcId"            , pdcId),
 						slog.Int   ("puvRegId"         , puvRegId),
 						slog.Int   ("puvAtnId"         , puvAtnId),
+						slog.Int   ("papId"            , papId),
+						slog.Int   ("palId"            , palId),
+						slog.Any   ("aukcAaguid"       , aukcAaguid),
 						slog.Any   ("pkgId"            , pkgId),
 						slog.Any   ("prhId"            , prhId),
 						slog.Any   ("pahId"            , pahId),
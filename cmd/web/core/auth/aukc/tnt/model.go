@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"strings"
 	"time"
 )
 
@@ -63,6 +64,17 @@ func Opts (ctx *context.Context, logger *slog.Logger, conn *pgxpool.Conn, tntId
 	return &idValMap, nil
 }
 
+// OptValue returns the value of the option of kind key whose id is id.
+func OptValue (opts *map[string][]Opt, key string, id int) string {
+	for _, v := range (*opts)[key] {
+		if v.Id == id {
+			return v.Value
+		}
+	}
+
+	return ""
+}
+
 type AukcInf struct {
 	TntId            int
 	AukcAurNmMinLen  int
@@ -73,9 +85,17 @@ type AukcInf struct {
 	PdcId            int
 	PuvRegId         int
 	PuvAtnId         int
+	PapId            int
+	PalId            int
+	AukcAaguid       []string
 	Uts              time.Time
 }
 
+// AaguidTxt is the AAGUIDs of the allow or deny list, one to a line.
+func (a AukcInf) AaguidTxt() string {
+	return strings.Join(a.AukcAaguid, "\n")
+}
+
 func GetAukcInf (ctx *context.Context, logger *slog.Logger, conn *pgxpool.Conn, tntId int) ([]AukcInf, error) {
 	rs, rErr := db.DataSet[AukcInf](ctx, logger, conn,
 		func(ctx *context.Context, tx *pgx.Tx)(string, string, *pgx.Rows, error){
@@ -209,9 +229,9 @@ func GetPrhInf (ctx *context.Context, logger *slog.Logger, conn *pgxpool.Conn, t
 	return rs, rErr
 }
 
-func PatchAukc (ctx *context.Context, logger *slog.Logger, conn *pgxpool.Conn, tntId int, aukcAurNmMinLen int, aukcAurNmMaxLen int, aukcEnabled bool, pkaId int, pktId int, pdcId int, puvRegId int, puvAtnId int, pkgId []int, prhId []int, pahId []int, by string, uts time.Time, exptErrs []string) error {
+func PatchAukc (ctx *context.Context, logger *slog.Logger, conn *pgxpool.Conn, tntId int, aukcAurNmMinLen int, aukcAurNmMaxLen int, aukcEnabled bool, pkaId int, pktId int, pdcId int, puvRegId int, puvAtnId int, papId int, palId int, aukcAaguid []string, pkgId []int, prhId []int, pahId []int, by string, uts time.Time, exptErrs []string) error {
 	var (
-		sprocCall   = "call web_core_auth_aukc_tnt_mod.mod_aukc(@p_tnt_id, @p_aukc_aur_nm_min_len, @p_aukc_aur_nm_max_len, @p_aukc_enabled, @p_pka_id, @p_pkt_id, @p_pdc_id, @p_puv_reg_id, @p_puv_atn_id, @p_pkg_id, @p_prh_id, @p_pah_id, @p_by, @p_uts)"
+		sprocCall   = "call web_core_auth_aukc_tnt_mod.mod_aukc(@p_tnt_id, @p_aukc_aur_nm_min_len, @p_aukc_aur_nm_max_len, @p_aukc_enabled, @p_pka_id, @p_pkt_id, @p_pdc_id, @p_puv_reg_id, @p_puv_atn_id, @p_pap_id, @p_pal_id, @p_aukc_aaguid, @p_pkg_id, @p_prh_id, @p_pah_id, @p_by, @p_uts)"
 		sprocParams = pgx.NamedArgs{
 			"p_tnt_id"               : tntId,
 			"p_aukc_aur_nm_min_len"  : aukcAurNmMinLen,
@@ -222,6 +242,9 @@ func PatchAukc (ctx *context.Context, logger *slog.Logger, conn *pgxpool.Conn, t
 			"p_pdc_id"               : pdcId,
 			"p_puv_reg_id"           : puvRegId,
 			"p_puv_atn_id"           : puvAtnId,
+			"p_pap_id"               : papId,
+			"p_pal_id"               : palId,
+			"p_aukc_aaguid"          : aukcAaguid,
 			"p_pkg_id"               : pkgId,
 			"p_prh_id"               : prhId,
 			"p_pah_id"               : pahId,
@@ -242,6 +265,9 @@ func PatchAukc (ctx *context.Context, logger *slog.Logger, conn *pgxpool.Conn, t
 			slog.Int   ("pkaId"            , pkaId),
 			slog.Int   ("pktId"            , pktId),
 			slog.Int   ("pdcId"            , pdcId),
+			slog.Int   ("papId"            , papId),
+			slog.Int   ("palId"            , palId),
+			slog.Any   ("aukcAaguid"       , aukcAaguid),
 			slog.Any   ("pkgId"            , pkgId),
 			slog.Any   ("prhId"            , prhId),
 			slog.Any   ("pahId"            , pahId),
@@ -10,6 +10,7 @@ import (
 import (
 	"github.com/andrewah64/base-app-client/internal/common/core/session"
 	"github.com/andrewah64/base-app-client/internal/web/core/error"
+	"github.com/andrewah64/base-app-client/internal/web/core/mfa"
 	"github.com/andrewah64/base-app-client/internal/web/core/ui/data/form"
 	"github.com/andrewah64/base-app-client/internal/web/core/ui/data/page"
 	"github.com/andrewah64/base-app-client/internal/web/core/ui/html"
@@ -87,48 +88,108 @@ func Patch(rw http.ResponseWriter, r *http.Request){
 	aupcAurPwdMaxLen := form.VInt  (r, "aupc-tnt-mod-aur-pwd-max-len")
 	aupcAurPwdIncSym := form.VBool (r, "aupc-tnt-mod-aur-pwd-inc-sym")
 	aupcAurPwdIncNum := form.VBool (r, "aupc-tnt-mod-aur-pwd-inc-num")
+	aupcAurPwdIncUpr := form.VBool (r, "aupc-tnt-mod-aur-pwd-inc-upr")
+	aupcAurPwdIncLwr := form.VBool (r, "aupc-tnt-mod-aur-pwd-inc-lwr")
+	aupcAurPwdSymSet := form.VText (r, "aupc-tnt-mod-aur-pwd-sym-set")
+	aupcAurPwdBrc    := form.VBool (r, "aupc-tnt-mod-aur-pwd-brc")
+	aupcAurPwdHstNum := form.VInt  (r, "aupc-tnt-mod-aur-pwd-hst-num")
+	aupcAurPwdMaxAge := form.VInt  (r, "aupc-tnt-mod-aur-pwd-max-age")
 	aupcEnabled      := form.VBool (r, "aupc-tnt-mod-aur-pwd-enabled")
-	aupcMfaEnabled   := form.VBool (r, "aupc-tnt-mod-aur-pwd-mfa-enabled")
+	aupcMfaNm        := form.VText (r, "aupc-tnt-mod-aur-pwd-mfa-nm")
+	aupcOtpSkew      := form.VInt  (r, "aupc-tnt-mod-otp-skew")
+	aupcOtpDigits    := form.VInt  (r, "aupc-tnt-mod-otp-digits")
+	aupcOtpAlg       := form.VText (r, "aupc-tnt-mod-otp-alg")
+	aupcLckThr       := form.VInt  (r, "aupc-tnt-mod-lck-thr")
+	aupcLckMins      := form.VInt  (r, "aupc-tnt-mod-lck-mins")
+	aupcDlyMs        := form.VInt  (r, "aupc-tnt-mod-dly-ms")
+	aupcEaVrfReq     := form.VBool (r, "aupc-tnt-mod-ea-vrf-req")
 	uts              := form.VTime (r, "aupc-tnt-mod-uts")
 
 	ssd.Logger.LogAttrs(ctx, slog.LevelDebug, "Patch::get data from aupc form",
-		slog.Int  ("aupcAurNmMinLen"  , aupcAurNmMinLen),
-		slog.Int  ("aupcAurNmMaxLen"  , aupcAurNmMaxLen),
-		slog.Int  ("aupcAurPwdMinLen" , aupcAurPwdMinLen),
-		slog.Int  ("aupcAurPwdMaxLen" , aupcAurPwdMaxLen),
-		slog.Bool ("aupcAurPwdIncSym" , aupcAurPwdIncSym),
-		slog.Bool ("aupcAurPwdIncNum" , aupcAurPwdIncNum),
-		slog.Bool ("aupcEnabled"      , aupcEnabled),
-		slog.Bool ("aupcMfaEnabled"   , aupcMfaEnabled),
-		slog.Any  ("uts"              , uts),
+		slog.Int   ("aupcAurNmMinLen"  , aupcAurNmMinLen),
+		slog.Int   ("aupcAurNmMaxLen"  , aupcAurNmMaxLen),
+		slog.Int   ("aupcAurPwdMinLen" , aupcAurPwdMinLen),
+		slog.Int   ("aupcAurPwdMaxLen" , aupcAurPwdMaxLen),
+		slog.Bool  ("aupcAurPwdIncSym" , aupcAurPwdIncSym),
+		slog.Bool  ("aupcAurPwdIncNum" , aupcAurPwdIncNum),
+		slog.Bool  ("aupcAurPwdIncUpr" , aupcAurPwdIncUpr),
+		slog.Bool  ("aupcAurPwdIncLwr" , aupcAurPwdIncLwr),
+		slog.String("aupcAurPwdSymSet" , aupcAurPwdSymSet),
+		slog.Bool  ("aupcAurPwdBrc"    , aupcAurPwdBrc),
+		slog.Int   ("aupcAurPwdHstNum" , aupcAurPwdHstNum),
+		slog.Int   ("aupcAurPwdMaxAge" , aupcAurPwdMaxAge),
+		slog.Bool  ("aupcEnabled"      , aupcEnabled),
+		slog.String("aupcMfaNm"        , aupcMfaNm),
+		slog.Int   ("aupcOtpSkew"      , aupcOtpSkew),
+		slog.Int   ("aupcOtpDigits"    , aupcOtpDigits),
+		slog.String("aupcOtpAlg"       , aupcOtpAlg),
+		slog.Int   ("aupcLckThr"       , aupcLckThr),
+		slog.Int   ("aupcLckMins"      , aupcLckMins),
+		slog.Int   ("aupcDlyMs"        , aupcDlyMs),
+		slog.Bool  ("aupcEaVrfReq"     , aupcEaVrfReq),
+		slog.Any   ("uts"              , uts),
 	)
 
+	// a code is accepted for 2 * skew + 1 periods, so a guessed one lasts
+	// longer the wider it is
+	aupcOtpSkew = min(max(aupcOtpSkew, 0), mfa.MaxSkew)
+
+	otpOpts := mfa.Opts{
+		Skew   : aupcOtpSkew,
+		Digits : aupcOtpDigits,
+		Alg    : aupcOtpAlg,
+	}
+
+	if ! otpOpts.Valid() {
+		notification.Toast(ctx, ssd.Logger, rw, r, "error" , &map[string]string{"Message" : data.T("web-core-auth-aupc-tnt-mod-form.warning-input-otp-invalid")}, data)
+		return
+	}
+
 	exptErrs := []string{
 		"OLOCK",
 	}
 
-	patchErr := PatchAupc(&ctx, ssd.Logger, ssd.Conn, ssd.TntId, aupcAurNmMinLen, aupcAurNmMaxLen, aupcAurPwdMinLen, aupcAurPwdMaxLen, aupcAurPwdIncSym, aupcAurPwdIncNum, aupcEnabled, aupcMfaEnabled, data.User.AurNm, uts, exptErrs)
+	patchErr := PatchAupc(&ctx, ssd.Logger, ssd.Conn, ssd.TntId, aupcAurNmMinLen, aupcAurNmMaxLen, aupcAurPwdMinLen, aupcAurPwdMaxLen, aupcAurPwdIncSym, aupcAurPwdIncNum, aupcAurPwdIncUpr, aupcAurPwdIncLwr, aupcAurPwdSymSet, aupcAurPwdBrc, aupcAurPwdHstNum, aupcAurPwdMaxAge, aupcEnabled, aupcMfaNm, aupcOtpSkew, aupcOtpDigits, aupcOtpAlg, aupcLckThr, aupcLckMins, aupcDlyMs, aupcEaVrfReq, data.User.AurNm, uts, exptErrs)
 	if patchErr != nil{
 		var pgErr *pgconn.PgError
 
 		if errors.As(patchErr, &pgErr) {
 			switch pgErr.Code {
 				case "OLOCK":
-					currentUrl := r.Header.Get("HX-Current-URL")
+					currentUrl := html.CurrentURL(r)
 
-					rw.Header().Set("HX-Location", fmt.Sprintf(`{"path":"%v", "target":"#main", "select":"#content", "swap" : "innerHTML show:window:top", "values":{"ntf": "web-core-auth-aupc-tnt-mod-form.warning-input-aupc-olock-error", "lvl": "error"}}`, currentUrl))
+					html.Locate(rw, r, html.Location{
+						Path   : currentUrl,
+						Target : "#main",
+						Select : "#content",
+						Swap   : "innerHTML show:window:top",
+						Values : map[string]string{"ntf": "web-core-auth-aupc-tnt-mod-form.warning-input-aupc-olock-error", "lvl": "error"},
+					})
 
 				default:
 					slog.LogAttrs(ctx, slog.LevelError, "Patch::unexpected error",
-						slog.Int  ("aupcAurNmMinLen"  , aupcAurNmMinLen),
-						slog.Int  ("aupcAurNmMaxLen"  , aupcAurNmMaxLen),
-						slog.Int  ("aupcAurPwdMinLen" , aupcAurPwdMinLen),
-						slog.Int  ("aupcAurPwdMaxLen" , aupcAurPwdMaxLen),
-						slog.Bool ("aupcAurPwdIncSym" , aupcAurPwdIncSym),
-						slog.Bool ("aupcAurPwdIncNum" , aupcAurPwdIncNum),
-						slog.Bool ("aupcEnabled"      , aupcEnabled),
-						slog.Bool ("aupcMfaEnabled"   , aupcMfaEnabled),
-						slog.Any  ("uts"              , uts),
+						slog.Int   ("aupcAurNmMinLen"  , aupcAurNmMinLen),
+						slog.Int   ("aupcAurNmMaxLen"  , aupcAurNmMaxLen),
+						slog.Int   ("aupcAurPwdMinLen" , aupcAurPwdMinLen),
+						slog.Int   ("aupcAurPwdMaxLen" , aupcAurPwdMaxLen),
+						slog.Bool  ("aupcAurPwdIncSym" , aupcAurPwdIncSym),
+						slog.Bool  ("aupcAurPwdIncNum" , aupcAurPwdIncNum),
+						slog.Bool  ("aupcAurPwdIncUpr" , aupcAurPwdIncUpr),
+						slog.Bool  ("aupcAurPwdIncLwr" , aupcAurPwdIncLwr),
+						slog.String("aupcAurPwdSymSet" , aupcAurPwdSymSet),
+						slog.Bool  ("aupcAurPwdBrc"    , aupcAurPwdBrc),
+						slog.Int   ("aupcAurPwdHstNum" , aupcAurPwdHstNum),
+						slog.Int   ("aupcAurPwdMaxAge" , aupcAurPwdMaxAge),
+						slog.Bool  ("aupcEnabled"      , aupcEnabled),
+						slog.String("aupcMfaNm"        , aupcMfaNm),
+						slog.Int   ("aupcOtpSkew"      , aupcOtpSkew),
+						slog.Int   ("aupcOtpDigits"    , aupcOtpDigits),
+						slog.String("aupcOtpAlg"       , aupcOtpAlg),
+						slog.Int   ("aupcLckThr"       , aupcLckThr),
+						slog.Int   ("aupcLckMins"      , aupcLckMins),
+						slog.Int   ("aupcDlyMs"        , aupcDlyMs),
+						slog.Bool  ("aupcEaVrfReq"     , aupcEaVrfReq),
+						slog.Any   ("uts"              , uts),
 					)
 
 					notification.Toast(ctx, slog.Default(), rw, r, "error" , &map[string]string{"Message" : data.T("web-core-auth-aupc-tnt-mod-form.warning-input-aupc-unexpected-error")}, data)
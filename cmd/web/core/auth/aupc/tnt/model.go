@@ -71,8 +71,21 @@ type AupcInf struct {
 	AupcAurPwdMaxLen int
 	AupcAurPwdIncSym bool
 	AupcAurPwdIncNum bool
+	AupcAurPwdIncUpr bool
+	AupcAurPwdIncLwr bool
+	AupcAurPwdSymSet string
+	AupcAurPwdBrc    bool
+	AupcAurPwdHstNum int
+	AupcAurPwdMaxAge int
 	AupcEnabled      bool
-	AupcMfaEnabled   bool
+	AupcMfaNm        string
+	AupcOtpSkew      int
+	AupcOtpDigits    int
+	AupcOtpAlg       string
+	AupcLckThr       int
+	AupcLckMins      int
+	AupcDlyMs        int
+	AupcEaVrfReq     bool
 	Uts              time.Time
 }
 
@@ -122,9 +135,9 @@ func GetAupcUtsInf (ctx *context.Context, logger *slog.Logger, conn *pgxpool.Con
 	return rs, rErr
 }
 
-func PatchAupc (ctx *context.Context, logger *slog.Logger, conn *pgxpool.Conn, tntId int, aupcAurNmMinLen int, aupcAurNmMaxLen int, aupcAurPwdMinLen int, aupcAurPwdMaxLen int, aupcAurPwdIncSym bool, aupcAurPwdIncNum bool, aupcEnabled bool, aupcMfaEnabled bool, by string, uts time.Time, exptErrs []string) error {
+func PatchAupc (ctx *context.Context, logger *slog.Logger, conn *pgxpool.Conn, tntId int, aupcAurNmMinLen int, aupcAurNmMaxLen int, aupcAurPwdMinLen int, aupcAurPwdMaxLen int, aupcAurPwdIncSym bool, aupcAurPwdIncNum bool, aupcAurPwdIncUpr bool, aupcAurPwdIncLwr bool, aupcAurPwdSymSet string, aupcAurPwdBrc bool, aupcAurPwdHstNum int, aupcAurPwdMaxAge int, aupcEnabled bool, aupcMfaNm string, aupcOtpSkew int, aupcOtpDigits int, aupcOtpAlg string, aupcLckThr int, aupcLckMins int, aupcDlyMs int, aupcEaVrfReq bool, by string, uts time.Time, exptErrs []string) error {
 	var (
-		sprocCall   = "call web_core_auth_aupc_tnt_mod.mod_aupc(@p_tnt_id, @p_aupc_aur_nm_min_len, @p_aupc_aur_nm_max_len, @p_aupc_aur_pwd_min_len, @p_aupc_aur_pwd_max_len, @p_aupc_aur_pwd_inc_sym, @p_aupc_aur_pwd_inc_num, @p_aupc_enabled, @p_aupc_mfa_enabled, @p_by, @p_uts)"
+		sprocCall   = "call web_core_auth_aupc_tnt_mod.mod_aupc(@p_tnt_id, @p_aupc_aur_nm_min_len, @p_aupc_aur_nm_max_len, @p_aupc_aur_pwd_min_len, @p_aupc_aur_pwd_max_len, @p_aupc_aur_pwd_inc_sym, @p_aupc_aur_pwd_inc_num, @p_aupc_aur_pwd_inc_upr, @p_aupc_aur_pwd_inc_lwr, @p_aupc_aur_pwd_sym_set, @p_aupc_aur_pwd_brc, @p_aupc_aur_pwd_hst_num, @p_aupc_aur_pwd_max_age, @p_aupc_enabled, @p_aupc_mfa_nm, @p_aupc_otp_skew, @p_aupc_otp_digits, @p_aupc_otp_alg, @p_aupc_lck_thr, @p_aupc_lck_mins, @p_aupc_dly_ms, @p_aupc_ea_vrf_req, @p_by, @p_uts)"
 		sprocParams = pgx.NamedArgs{
 			"p_tnt_id"               : tntId,
 			"p_aupc_aur_nm_min_len"  : aupcAurNmMinLen,
@@ -133,8 +146,21 @@ func PatchAupc (ctx *context.Context, logger *slog.Logger, conn *pgxpool.Conn, t
 			"p_aupc_aur_pwd_max_len" : aupcAurPwdMaxLen,
 			"p_aupc_aur_pwd_inc_sym" : aupcAurPwdIncSym,
 			"p_aupc_aur_pwd_inc_num" : aupcAurPwdIncNum,
+			"p_aupc_aur_pwd_inc_upr" : aupcAurPwdIncUpr,
+			"p_aupc_aur_pwd_inc_lwr" : aupcAurPwdIncLwr,
+			"p_aupc_aur_pwd_sym_set" : aupcAurPwdSymSet,
+			"p_aupc_aur_pwd_brc"     : aupcAurPwdBrc,
+			"p_aupc_aur_pwd_hst_num" : aupcAurPwdHstNum,
+			"p_aupc_aur_pwd_max_age" : aupcAurPwdMaxAge,
 			"p_aupc_enabled"         : aupcEnabled,
-			"p_aupc_mfa_enabled"     : aupcMfaEnabled,
+			"p_aupc_mfa_nm"          : aupcMfaNm,
+			"p_aupc_otp_skew"        : aupcOtpSkew,
+			"p_aupc_otp_digits"      : aupcOtpDigits,
+			"p_aupc_otp_alg"         : aupcOtpAlg,
+			"p_aupc_lck_thr"         : aupcLckThr,
+			"p_aupc_lck_mins"        : aupcLckMins,
+			"p_aupc_dly_ms"          : aupcDlyMs,
+			"p_aupc_ea_vrf_req"      : aupcEaVrfReq,
 			"p_by"                   : by,
 			"p_uts"                  : uts,
 		}
@@ -152,8 +178,21 @@ func PatchAupc (ctx *context.Context, logger *slog.Logger, conn *pgxpool.Conn, t
 			slog.Int   ("aupcAurPwdMaxLen" , aupcAurPwdMaxLen),
 			slog.Bool  ("aupcAurPwdIncSym" , aupcAurPwdIncSym),
 			slog.Bool  ("aupcAurPwdIncNum" , aupcAurPwdIncNum),
+			slog.Bool  ("aupcAurPwdIncUpr" , aupcAurPwdIncUpr),
+			slog.Bool  ("aupcAurPwdIncLwr" , aupcAurPwdIncLwr),
+			slog.String("aupcAurPwdSymSet" , aupcAurPwdSymSet),
+			slog.Bool  ("aupcAurPwdBrc"    , aupcAurPwdBrc),
+			slog.Int   ("aupcAurPwdHstNum" , aupcAurPwdHstNum),
+			slog.Int   ("aupcAurPwdMaxAge" , aupcAurPwdMaxAge),
 			slog.Bool  ("aupcEnabled"      , aupcEnabled),
-			slog.Bool  ("aupcMfaEnabled"   , aupcMfaEnabled),
+			slog.String("aupcMfaNm"        , aupcMfaNm),
+			slog.Int   ("aupcOtpSkew"      , aupcOtpSkew),
+			slog.Int   ("aupcOtpDigits"    , aupcOtpDigits),
+			slog.String("aupcOtpAlg"       , aupcOtpAlg),
+			slog.Int   ("aupcLckThr"       , aupcLckThr),
+			slog.Int   ("aupcLckMins"      , aupcLckMins),
+			slog.Int   ("aupcDlyMs"        , aupcDlyMs),
+			slog.Bool  ("aupcEaVrfReq"     , aupcEaVrfReq),
 			slog.String("by"               , by),
 			slog.Any   ("uts"              , uts),
 		)
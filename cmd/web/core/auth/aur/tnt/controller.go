@@ -10,13 +10,14 @@ import (
 )
 
 import (
-	"github.com/andrewah64/base-app-client/internal/common/core/password"
-	"github.com/andrewah64/base-app-client/internal/common/core/session"
-	"github.com/andrewah64/base-app-client/internal/web/core/error"
-	"github.com/andrewah64/base-app-client/internal/web/core/ui/data/form"
-	"github.com/andrewah64/base-app-client/internal/web/core/ui/data/page"
-	"github.com/andrewah64/base-app-client/internal/web/core/ui/html"
-	"github.com/andrewah64/base-app-client/internal/web/core/ui/notification"
+	   "github.com/andrewah64/base-app-client/internal/common/core/password"
+	   "github.com/andrewah64/base-app-client/internal/common/core/session"
+	   "github.com/andrewah64/base-app-client/internal/web/core/error"
+	ws "github.com/andrewah64/base-app-client/internal/web/core/session"
+	   "github.com/andrewah64/base-app-client/internal/web/core/ui/data/form"
+	   "github.com/andrewah64/base-app-client/internal/web/core/ui/data/page"
+	   "github.com/andrewah64/base-app-client/internal/web/core/ui/html"
+	   "github.com/andrewah64/base-app-client/internal/web/core/ui/notification"
 )
 
 import (
@@ -59,6 +60,12 @@ func Delete (rw http.ResponseWriter, r *http.Request) {
 			return
 		}
 
+		rvkErr := ws.Revoke(&ctx, ssd.Logger, ssd.Conn, ws.Revocation{TntId: ssd.TntId, AurId: aurId})
+		if rvkErr != nil {
+			error.IntSrv(ctx, rw, rvkErr)
+			return
+		}
+
 		ssd.Logger.LogAttrs(ctx, slog.LevelDebug, "Delete::success")
 
 		rw.Header().Set("HX-Trigger", "mod")
@@ -130,7 +137,7 @@ func Get (rw http.ResponseWriter, r *http.Request) {
 	pageNumber  := 2
 	offset      := 0
 	resultLimit := 50
-	trigger     := r.Header.Get("HX-Trigger")
+	trigger     := html.Trigger(r, "aur-tnt-inf-form")
 
 	ssd.Logger.LogAttrs(ctx, slog.LevelDebug, "Get::retrieve datasets",
 		slog.Int   ("pageNumber"  , pageNumber),
@@ -260,15 +267,40 @@ func Get (rw http.ResponseWriter, r *http.Request) {
 				slog.Int("len(aurRs)" , len(aurRs)),
 			)
 
+			if ! html.Htmx(r) {
+				opts := make(map[string]any)
+
+				optsInfRs, optsInfRsErr := OptsInf(&ctx, ssd.Logger, ssd.Conn, ssd.TntId)
+				if optsInfRsErr != nil {
+					error.IntSrv(ctx, rw, optsInfRsErr)
+					return
+				}
+
+				opts["Search"] = optsInfRs
+
+				if data.HasRole ("role_web_core_auth_aur_tnt_reg") {
+					optsRegRs, optsRegRsErr := OptsReg(&ctx, ssd.Logger, ssd.Conn, ssd.TntId)
+					if optsRegRsErr != nil {
+						error.IntSrv(ctx, rw, optsRegRsErr)
+						return
+					}
+
+					opts["Register"] = optsRegRs
+				}
+
+				data.FormOpts = &opts
+			}
+
 			data.ResultSet = &map[string]any{
 				"Search"      : &aurRs,
+				"PageNumber"  : pageNumber,
 				"ResultLimit" : resultLimit,
 				"Params"      : params(aurNm, dbrlId, aurEnabled, lngId, pageNumber),
 			}
 
 			rw.Header().Set("HX-Trigger", "src")
 
-			html.Tmpl(ctx, ssd.Logger, rw, r, "core/auth/aur/tnt/template/res", http.StatusOK, &data)
+			html.Partial(ctx, ssd.Logger, rw, r, "core/auth/aur/tnt/content", "core/auth/aur/tnt/template/res", http.StatusOK, &data)
 
 			ssd.Logger.LogAttrs(ctx, slog.LevelDebug, "Get::end [search]")
 
@@ -325,6 +357,32 @@ func Post(rw http.ResponseWriter, r *http.Request){
 		return
 	}
 
+	pwdRs, pwdRsErr := GetPwdInf(&ctx, ssd.Logger, ssd.Conn, ssd.TntId)
+	if pwdRsErr != nil {
+		error.IntSrv(ctx, rw, pwdRsErr)
+		return
+	}
+
+	vrls := Policy(pwdRs[0]).Check(ctx, aurPw, nil)
+
+	if len(vrls) > 0 {
+		msgs := make([]string, len(vrls))
+
+		for i, vrl := range vrls {
+			msgs[i] = data.T("web-core-auth-aur-tnt-reg-form.error-input-aur-pwd-" + vrl)
+		}
+
+		notification.Vrl(ctx, ssd.Logger, rw, r,
+			data.T("web-core-auth-aur-tnt-page.title"),
+			data.T("web-core-auth-aur-tnt-reg-form.title-warning-singular", "n", strconv.Itoa(len(msgs))),
+			data.T("web-core-auth-aur-tnt-reg-form.title-warning-plural"  , "n", strconv.Itoa(len(msgs))),
+			&msgs,
+			data,
+		)
+
+		return
+	}
+
 	aurHshPw, pErr := password.Hash(aurPw)
 	if pErr != nil {
 		error.IntSrv(ctx, rw, pErr)
@@ -9,12 +9,13 @@ import (
 )
 
 import (
-	"github.com/andrewah64/base-app-client/internal/common/core/session"
-	"github.com/andrewah64/base-app-client/internal/web/core/error"
-	"github.com/andrewah64/base-app-client/internal/web/core/ui/data/form"
-	"github.com/andrewah64/base-app-client/internal/web/core/ui/data/page"
-	"github.com/andrewah64/base-app-client/internal/web/core/ui/html"
-	"github.com/andrewah64/base-app-client/internal/web/core/ui/notification"
+	   "github.com/andrewah64/base-app-client/internal/common/core/session"
+	   "github.com/andrewah64/base-app-client/internal/web/core/error"
+	ws "github.com/andrewah64/base-app-client/internal/web/core/session"
+	   "github.com/andrewah64/base-app-client/internal/web/core/ui/data/form"
+	   "github.com/andrewah64/base-app-client/internal/web/core/ui/data/page"
+	   "github.com/andrewah64/base-app-client/internal/web/core/ui/html"
+	   "github.com/andrewah64/base-app-client/internal/web/core/ui/notification"
 )
 
 import (
@@ -45,7 +46,7 @@ func Get(rw http.ResponseWriter, r *http.Request) {
 
 	aurId, aurIdErr := strconv.Atoi(r.PathValue("id"))
 	if aurIdErr != nil || aurId < 1 {
-		http.NotFound(rw, r)
+		error.Status(ctx, rw, http.StatusNotFound)
 		return
 	}
 
@@ -100,7 +101,7 @@ func Patch(rw http.ResponseWriter, r *http.Request) {
 
 	aurId, aurIdErr := strconv.Atoi(r.PathValue("id"))
 	if aurIdErr != nil || aurId < 1 {
-		http.NotFound(rw, r)
+		error.Status(ctx, rw, http.StatusNotFound)
 		return
 	}
 
@@ -161,6 +162,12 @@ func Patch(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	rvkErr := ws.Revoke(&ctx, ssd.Logger, ssd.Conn, ws.Revocation{TntId: ssd.TntId, AurId: []int{aurId}})
+	if rvkErr != nil {
+		error.IntSrv(ctx, rw, rvkErr)
+		return
+	}
+
 	aurRs, aurRsErr := GetRowAurInf(&ctx, ssd.Logger, ssd.Conn, ssd.TntId, aurId)
 	if aurRsErr != nil {
 		error.IntSrv(ctx, rw, aurRsErr)
@@ -0,0 +1,67 @@
+package lck
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+import (
+	"github.com/andrewah64/base-app-client/internal/common/core/session"
+	"github.com/andrewah64/base-app-client/internal/web/core/error"
+	"github.com/andrewah64/base-app-client/internal/web/core/ui/data/page"
+	"github.com/andrewah64/base-app-client/internal/web/core/ui/html"
+	"github.com/andrewah64/base-app-client/internal/web/core/ui/notification"
+)
+
+func Delete(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	ssd, ok := session.FromContext(ctx)
+	if ! ok {
+		error.IntSrv(ctx, rw, fmt.Errorf("Delete::get request info"))
+		return
+	}
+
+	ssd.Logger.LogAttrs(ctx, slog.LevelDebug, "Delete::start")
+
+	data, ok := page.FromContext(ctx)
+	if ! ok {
+		error.IntSrv(ctx, rw, fmt.Errorf("Delete::get request data"))
+		return
+	}
+
+	aurId, aurIdErr := strconv.Atoi(r.PathValue("id"))
+	if aurIdErr != nil || aurId < 1 {
+		error.Status(ctx, rw, http.StatusNotFound)
+		return
+	}
+
+	delErr := DelLck(&ctx, ssd.Logger, ssd.Conn, ssd.TntId, aurId, data.User.AurNm, nil)
+	if delErr != nil {
+		error.IntSrv(ctx, rw, delErr)
+		return
+	}
+
+	aurRs, aurRsErr := GetRowAurInf(&ctx, ssd.Logger, ssd.Conn, ssd.TntId, aurId)
+	if aurRsErr != nil {
+		error.IntSrv(ctx, rw, aurRsErr)
+		return
+	}
+
+	ssd.Logger.LogAttrs(ctx, slog.LevelDebug, "Delete::unlock user",
+		slog.Int("aurId"      , aurId),
+		slog.Int("len(aurRs)" , len(aurRs)),
+	)
+
+	data.ResultSet = &map[string]any{"Search": &aurRs}
+
+	html.Fragment(ctx, ssd.Logger, rw, r, "core/auth/aur/tnt/fragment/infrow", http.StatusOK, &data)
+
+	if len(aurRs) == 1 {
+		notification.Toast(ctx, ssd.Logger, rw, r, "success", &map[string]string{"Message" : data.T("web-core-auth-aur-tnt-mod-form.message-unlock-success", "aurNm", aurRs[0].AurNm)}, data)
+	}
+
+	ssd.Logger.LogAttrs(ctx, slog.LevelDebug, "Delete::end")
+}
@@ -0,0 +1,78 @@
+package lck
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+)
+
+import (
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+import (
+	"github.com/andrewah64/base-app-client/internal/common/core/db"
+)
+
+import (
+	"github.com/andrewah64/base-app-client/cmd/web/core/auth/aur/tnt/id"
+)
+
+const (
+	dbSchema = "web_core_auth_aur_tnt_lck_del"
+)
+
+func GetRowAurInf(ctx *context.Context, logger *slog.Logger, conn *pgxpool.Conn, tntId int, aurId int) ([]id.Inf, error) {
+	const (
+		dbFunc = "row_aur_inf"
+	)
+
+	rs, rErr := db.DataSet[id.Inf](ctx, logger, conn,
+		func(ctx *context.Context, tx *pgx.Tx)(string, string, *pgx.Rows, error){
+			qry := fmt.Sprintf("select %v.%v($1, $2, $3)", dbSchema, dbFunc)
+
+			c, cErr := (*tx).Query(*ctx, qry, dbFunc, tntId, aurId)
+			if cErr != nil {
+				slog.LogAttrs(*ctx, slog.LevelError, "get dataset",
+					slog.String("error" , cErr.Error()),
+					slog.String("qry"   , qry),
+					slog.Int   ("tntId" , tntId),
+					slog.Int   ("aurId" , aurId),
+				)
+
+				return qry, dbFunc, nil, fmt.Errorf("call database function: %w", cErr)
+			}
+
+			return qry, dbFunc, &c, nil
+		})
+
+	return rs, rErr
+}
+
+func DelLck (ctx *context.Context, logger *slog.Logger, conn *pgxpool.Conn, tntId int, aurId int, by string, exptErrs []string) error {
+	var (
+		sprocCall   = fmt.Sprintf("call %v.del_lck(@p_tnt_id, @p_aur_id, @p_by)", dbSchema)
+		sprocParams = pgx.NamedArgs{
+			"p_tnt_id" : tntId,
+			"p_aur_id" : aurId,
+			"p_by"     : by,
+		}
+	)
+
+	sprocErr := db.Sproc(ctx, logger, conn, sprocCall, sprocParams, exptErrs)
+	if sprocErr != nil {
+		logger.LogAttrs(*ctx, slog.LevelDebug, "call sproc",
+			slog.String("sprocCall" , sprocCall),
+			slog.String("error"     , sprocErr.Error()),
+			slog.Int   ("tntId"     , tntId),
+			slog.Int   ("aurId"     , aurId),
+			slog.String("by"        , by),
+			slog.Any   ("exptErrs"  , exptErrs),
+		)
+
+		return sprocErr
+	}
+
+	return nil
+}
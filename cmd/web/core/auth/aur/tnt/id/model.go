@@ -23,12 +23,14 @@ type Opt struct {
 }
 
 type Inf struct {
-	AurId      int
-	AurNm      string
-	RolName    string
-	AurEnabled bool
-	LngNm      string
-	PgNm       string
+	AurId         int
+	AurNm         string
+	RolName       string
+	AurEnabled    bool
+	LngNm         string
+	PgNm          string
+	AurLocked     bool
+	AurOtpEnabled bool
 }
 
 type Mod struct {
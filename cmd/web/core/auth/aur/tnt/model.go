@@ -13,6 +13,7 @@ import (
 
 import (
 	"github.com/andrewah64/base-app-client/internal/common/core/db"
+	"github.com/andrewah64/base-app-client/internal/common/core/password"
 )
 
 type Opt struct {
@@ -22,12 +23,14 @@ type Opt struct {
 }
 
 type Inf struct {
-	AurId        int
-	AurNm        string
-	RolName      string
-	AurEnabled   bool
-	LngNm        string
-	PgNm         string
+	AurId         int
+	AurNm         string
+	RolName       string
+	AurEnabled    bool
+	LngNm         string
+	PgNm          string
+	AurLocked     bool
+	AurOtpEnabled bool
 }
 
 func OptsInf (ctx *context.Context, logger *slog.Logger, conn *pgxpool.Conn, tntId int) (*map[string][]Opt, error) {
@@ -152,6 +155,55 @@ func GetAur (ctx *context.Context, logger *slog.Logger, conn *pgxpool.Conn, tntI
 	return rs, rErr
 }
 
+type PwdInf struct {
+	AupcAurPwdMinLen int
+	AupcAurPwdMaxLen int
+	AupcAurPwdIncSym bool
+	AupcAurPwdIncNum bool
+	AupcAurPwdIncUpr bool
+	AupcAurPwdIncLwr bool
+	AupcAurPwdSymSet string
+	AupcAurPwdBrc    bool
+}
+
+// GetPwdInf returns the tenant's password policy.
+func GetPwdInf (ctx *context.Context, logger *slog.Logger, conn *pgxpool.Conn, tntId int) ([]PwdInf, error) {
+	rs, rErr := db.DataSet[PwdInf](ctx, logger, conn,
+		func(ctx *context.Context, tx *pgx.Tx)(string, string, *pgx.Rows, error){
+			dbFunc := "pwd_inf"
+			qry    := fmt.Sprintf("select web_core_auth_aur_tnt_reg.%v($1, $2)", dbFunc)
+
+			c, cErr := (*tx).Query(*ctx, qry, dbFunc, tntId)
+			if cErr != nil {
+				slog.LogAttrs(*ctx, slog.LevelError, "get dataset",
+					slog.String("error" , cErr.Error()),
+					slog.String("qry"   , qry),
+					slog.Int   ("tntId" , tntId),
+				)
+
+				return qry, dbFunc, nil, fmt.Errorf("call database function: %w", cErr)
+			}
+
+			return qry, dbFunc, &c, nil
+		})
+
+	return rs, rErr
+}
+
+// Policy returns the tenant's password policy.
+func Policy (inf PwdInf) password.Policy {
+	return password.Policy{
+		MinLen : inf.AupcAurPwdMinLen,
+		MaxLen : inf.AupcAurPwdMaxLen,
+		IncUpr : inf.AupcAurPwdIncUpr,
+		IncLwr : inf.AupcAurPwdIncLwr,
+		IncNum : inf.AupcAurPwdIncNum,
+		IncSym : inf.AupcAurPwdIncSym,
+		SymSet : inf.AupcAurPwdSymSet,
+		Brc    : inf.AupcAurPwdBrc,
+	}
+}
+
 func PostAur (ctx *context.Context, logger *slog.Logger, conn *pgxpool.Conn, tntId int, grpId *int64, aurNm string, aurHshPw string, lngId *int64, by string, exptErrs []string) error {
 	var (
 		sprocCall   = "call web_core_auth_aur_tnt_reg.reg_aur(@p_tnt_id, @p_grp_id, @p_aur_nm, @p_aur_hsh_pw, @p_lng_id, @p_by)"
@@ -0,0 +1,210 @@
+package tnt
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+)
+
+import (
+	"golang.org/x/crypto/argon2"
+)
+
+const (
+	Version = 1
+
+	SecretsEncrypted = "encrypted"
+	SecretsOmitted   = "omitted"
+)
+
+// Bundle is the versioned export of a tenant's configuration. Secrets are
+// either absent or sealed with a key derived from a passphrase that is not
+// part of the bundle.
+type Bundle struct {
+	Version int               `json:"version"`
+	Created time.Time         `json:"created"`
+	Origin  string            `json:"origin"`
+	Secrets string            `json:"secrets"`
+	Salt    string            `json:"salt,omitempty"`
+	Config  json.RawMessage   `json:"config"`
+	Secret  map[string]string `json:"secret,omitempty"`
+}
+
+type Change struct {
+	Path string
+	Old  string
+	New  string
+}
+
+func NewBundle(origin string, cfg []byte, sec []SecInf, pass string) (*Bundle, error) {
+	b := &Bundle{
+		Version : Version,
+		Created : time.Now().UTC(),
+		Origin  : origin,
+		Secrets : SecretsOmitted,
+		Config  : cfg,
+	}
+
+	if pass == "" {
+		return b, nil
+	}
+
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+
+	aead, aeadErr := gcm(pass, salt)
+	if aeadErr != nil {
+		return nil, aeadErr
+	}
+
+	b.Secrets = SecretsEncrypted
+	b.Salt    = base64.StdEncoding.EncodeToString(salt)
+	b.Secret  = make(map[string]string, len(sec))
+
+	for _, v := range sec {
+		nonce := make([]byte, aead.NonceSize())
+		if _, err := rand.Read(nonce); err != nil {
+			return nil, err
+		}
+
+		b.Secret[v.SecKey] = base64.StdEncoding.EncodeToString(aead.Seal(nonce, nonce, []byte(v.SecVal), []byte(v.SecKey)))
+	}
+
+	return b, nil
+}
+
+func ReadBundle(raw []byte) (*Bundle, error) {
+	b := &Bundle{}
+
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.DisallowUnknownFields()
+
+	if err := dec.Decode(b); err != nil {
+		return nil, fmt.Errorf("decode bundle: %w", err)
+	}
+
+	if b.Version != Version {
+		return nil, fmt.Errorf("unsupported bundle version %v", b.Version)
+	}
+
+	if ! json.Valid(b.Config) {
+		return nil, fmt.Errorf("bundle configuration is not valid json")
+	}
+
+	return b, nil
+}
+
+// Open returns the bundle's secrets in plain text. A bundle without secrets
+// returns an empty map so that the tenant's existing secrets are kept.
+func (b *Bundle) Open(pass string) (map[string]string, error) {
+	sec := make(map[string]string, len(b.Secret))
+
+	if b.Secrets != SecretsEncrypted || len(b.Secret) == 0 {
+		return sec, nil
+	}
+
+	salt, saltErr := base64.StdEncoding.DecodeString(b.Salt)
+	if saltErr != nil {
+		return nil, fmt.Errorf("decode salt: %w", saltErr)
+	}
+
+	aead, aeadErr := gcm(pass, salt)
+	if aeadErr != nil {
+		return nil, aeadErr
+	}
+
+	for k, v := range b.Secret {
+		ct, ctErr := base64.StdEncoding.DecodeString(v)
+		if ctErr != nil || len(ct) < aead.NonceSize() {
+			return nil, fmt.Errorf("decode secret '%v'", k)
+		}
+
+		pt, ptErr := aead.Open(nil, ct[:aead.NonceSize()], ct[aead.NonceSize():], []byte(k))
+		if ptErr != nil {
+			return nil, fmt.Errorf("decrypt secret '%v': %w", k, ptErr)
+		}
+
+		sec[k] = string(pt)
+	}
+
+	return sec, nil
+}
+
+// Diff lists the differences between two configuration documents. Objects are
+// compared key by key; any other value, including arrays, is compared whole.
+func Diff(cur []byte, nxt []byte) ([]Change, error) {
+	var (
+		c any
+		n any
+	)
+
+	if err := json.Unmarshal(cur, &c); err != nil {
+		return nil, fmt.Errorf("decode current configuration: %w", err)
+	}
+
+	if err := json.Unmarshal(nxt, &n); err != nil {
+		return nil, fmt.Errorf("decode bundle configuration: %w", err)
+	}
+
+	cf := make(map[string]string)
+	nf := make(map[string]string)
+
+	flatten("", c, cf)
+	flatten("", n, nf)
+
+	chg := make([]Change, 0)
+
+	for _, k := range slices.Sorted(maps.Keys(cf)) {
+		if v, ok := nf[k]; ! ok {
+			chg = append(chg, Change{Path: k, Old: cf[k]})
+		} else if v != cf[k] {
+			chg = append(chg, Change{Path: k, Old: cf[k], New: v})
+		}
+	}
+
+	for _, k := range slices.Sorted(maps.Keys(nf)) {
+		if _, ok := cf[k]; ! ok {
+			chg = append(chg, Change{Path: k, New: nf[k]})
+		}
+	}
+
+	return chg, nil
+}
+
+func flatten(path string, v any, f map[string]string) {
+	if o, ok := v.(map[string]any); ok && len(o) > 0 {
+		for k, cv := range o {
+			if path == "" {
+				flatten(k, cv, f)
+			} else {
+				flatten(path + "." + k, cv, f)
+			}
+		}
+
+		return
+	}
+
+	j, _ := json.Marshal(v)
+
+	f[path] = string(j)
+}
+
+func gcm(pass string, salt []byte) (cipher.AEAD, error) {
+	key := argon2.IDKey([]byte(pass), salt, 1, 64 * 1024, 4, 32)
+
+	block, blockErr := aes.NewCipher(key)
+	if blockErr != nil {
+		return nil, blockErr
+	}
+
+	return cipher.NewGCM(block)
+}
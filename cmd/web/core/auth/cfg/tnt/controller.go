@@ -0,0 +1,304 @@
+package tnt
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+import (
+	   "github.com/andrewah64/base-app-client/internal/common/core/session"
+	   "github.com/andrewah64/base-app-client/internal/web/core/error"
+	   "github.com/andrewah64/base-app-client/internal/web/core/refresh"
+	ws "github.com/andrewah64/base-app-client/internal/web/core/session"
+	   "github.com/andrewah64/base-app-client/internal/web/core/ui/data/form"
+	   "github.com/andrewah64/base-app-client/internal/web/core/ui/data/page"
+	   "github.com/andrewah64/base-app-client/internal/web/core/ui/html"
+	   "github.com/andrewah64/base-app-client/internal/web/core/ui/notification"
+)
+
+import (
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+func Get (rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	ssd, ok := session.FromContext(ctx)
+	if ! ok {
+		error.IntSrv(ctx, rw, fmt.Errorf("Get::get request info"))
+		return
+	}
+
+	ssd.Logger.LogAttrs(ctx, slog.LevelDebug, "Get::start")
+
+	data, ok := page.FromContext(ctx)
+	if ! ok {
+		error.IntSrv(ctx, rw, fmt.Errorf("Get::get request data"))
+		return
+	}
+
+	data.FormOpts = &map[string]any{"Version": Version}
+
+	html.Tmpl(ctx, ssd.Logger, rw, r, "core/auth/cfg/tnt/content", http.StatusOK, &data)
+
+	ssd.Logger.LogAttrs(ctx, slog.LevelDebug, "Get::end")
+
+	return
+}
+
+// Post exports the tenant's configuration as a downloadable bundle.
+func Post (rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	ssd, ok := session.FromContext(ctx)
+	if ! ok {
+		error.IntSrv(ctx, rw, fmt.Errorf("Post::get request info"))
+		return
+	}
+
+	ssd.Logger.LogAttrs(ctx, slog.LevelDebug, "Post::start")
+
+	data, ok := page.FromContext(ctx)
+	if ! ok {
+		error.IntSrv(ctx, rw, fmt.Errorf("Post::get request data"))
+		return
+	}
+
+	pfErr := r.ParseForm()
+	if pfErr != nil {
+		error.IntSrv(ctx, rw, pfErr)
+		return
+	}
+
+	sec  := form.VBool (r, "cfg-tnt-exp-sec")
+	pass := form.VText (r, "cfg-tnt-exp-pass")
+
+	ssd.Logger.LogAttrs(ctx, slog.LevelDebug, "Post::get data from form",
+		slog.Bool("sec"       , sec),
+		slog.Int ("len(pass)" , len(pass)),
+	)
+
+	if ! sec {
+		pass = ""
+	} else if pass == "" {
+		notification.Toast(ctx, ssd.Logger, rw, r, "error" , &map[string]string{"Message" : data.T("web-core-auth-cfg-tnt-exp-form.warning-input-pass-required")}, data)
+		return
+	}
+
+	cfgRs, cfgRsErr := GetCfgInf(&ctx, ssd.Logger, ssd.Conn, ssd.TntId)
+	if cfgRsErr != nil {
+		error.IntSrv(ctx, rw, cfgRsErr)
+		return
+	}
+
+	if len(cfgRs) != 1 {
+		notification.Toast(ctx, ssd.Logger, rw, r, "error" , &map[string]string{"Message" : data.T("web-core-auth-cfg-tnt-exp-form.warning-cfg-not-found")}, data)
+		return
+	}
+
+	secRs := make([]SecInf, 0)
+
+	if sec {
+		rs, rsErr := GetSecInf(&ctx, ssd.Logger, ssd.Conn, ssd.TntId)
+		if rsErr != nil {
+			error.IntSrv(ctx, rw, rsErr)
+			return
+		}
+
+		secRs = rs
+	}
+
+	ssd.Logger.LogAttrs(ctx, slog.LevelDebug, "Post::retrieve datasets",
+		slog.Int("len(cfgRs)" , len(cfgRs)),
+		slog.Int("len(secRs)" , len(secRs)),
+	)
+
+	b, bErr := NewBundle(cfgRs[0].TntOrigin, cfgRs[0].Cfg, secRs, pass)
+	if bErr != nil {
+		error.IntSrv(ctx, rw, bErr)
+		return
+	}
+
+	raw, rawErr := json.MarshalIndent(b, "", "  ")
+	if rawErr != nil {
+		error.IntSrv(ctx, rw, rawErr)
+		return
+	}
+
+	rw.Header().Set("Content-Type"       , "application/json")
+	rw.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="tenant-config-v%v-%v.json"`, Version, b.Created.Format("20060102T150405Z")))
+	rw.Header().Set("Cache-Control"      , "no-store")
+	rw.WriteHeader(http.StatusOK)
+	rw.Write(raw)
+
+	ssd.Logger.LogAttrs(ctx, slog.LevelDebug, "Post::end")
+
+	return
+}
+
+// Put imports a bundle into the tenant. A dry run lists the changes the
+// import would make without applying them.
+func Put (rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	ssd, ok := session.FromContext(ctx)
+	if ! ok {
+		error.IntSrv(ctx, rw, fmt.Errorf("Put::get request info"))
+		return
+	}
+
+	ssd.Logger.LogAttrs(ctx, slog.LevelDebug, "Put::start")
+
+	data, ok := page.FromContext(ctx)
+	if ! ok {
+		error.IntSrv(ctx, rw, fmt.Errorf("Put::get request data"))
+		return
+	}
+
+	mpfErr := r.ParseMultipartForm(200 * 1024) // 200 Kb file upload limit
+	if mpfErr != nil {
+		error.IntSrv(ctx, rw, mpfErr)
+		return
+	}
+
+	dryRun := form.VBool (r, "cfg-tnt-imp-dry-run")
+	pass   := form.VText (r, "cfg-tnt-imp-pass")
+
+	ssd.Logger.LogAttrs(ctx, slog.LevelDebug, "Put::get data from form",
+		slog.Bool("dryRun"    , dryRun),
+		slog.Int ("len(pass)" , len(pass)),
+	)
+
+	bFile, _, bFileErr := r.FormFile("cfg-tnt-imp-file")
+	if bFileErr != nil {
+		notification.Toast(ctx, ssd.Logger, rw, r, "error" , &map[string]string{"Message" : data.T("web-core-auth-cfg-tnt-imp-form.warning-input-file-required")}, data)
+
+		ssd.Logger.LogAttrs(ctx, slog.LevelDebug, "Put::get bundle file",
+			slog.String("bFileErr" , bFileErr.Error()),
+		)
+
+		return
+	}
+
+	raw, rawErr := io.ReadAll(bFile)
+	if rawErr != nil {
+		notification.Toast(ctx, ssd.Logger, rw, r, "error" , &map[string]string{"Message" : data.T("web-core-auth-cfg-tnt-imp-form.warning-input-unreadable-file")}, data)
+
+		ssd.Logger.LogAttrs(ctx, slog.LevelDebug, "Put::read bundle file",
+			slog.String("rawErr" , rawErr.Error()),
+		)
+
+		return
+	}
+
+	b, bErr := ReadBundle(raw)
+	if bErr != nil {
+		notification.Toast(ctx, ssd.Logger, rw, r, "error" , &map[string]string{"Message" : data.T("web-core-auth-cfg-tnt-imp-form.warning-input-bundle-invalid", "version", strconv.Itoa(Version))}, data)
+
+		ssd.Logger.LogAttrs(ctx, slog.LevelDebug, "Put::read bundle",
+			slog.String("bErr" , bErr.Error()),
+		)
+
+		return
+	}
+
+	sec, secErr := b.Open(pass)
+	if secErr != nil {
+		notification.Toast(ctx, ssd.Logger, rw, r, "error" , &map[string]string{"Message" : data.T("web-core-auth-cfg-tnt-imp-form.warning-input-pass-invalid")}, data)
+
+		ssd.Logger.LogAttrs(ctx, slog.LevelDebug, "Put::open bundle secrets",
+			slog.String("secErr" , secErr.Error()),
+		)
+
+		return
+	}
+
+	if dryRun {
+		cfgRs, cfgRsErr := GetCfgInf(&ctx, ssd.Logger, ssd.Conn, ssd.TntId)
+		if cfgRsErr != nil {
+			error.IntSrv(ctx, rw, cfgRsErr)
+			return
+		}
+
+		if len(cfgRs) != 1 {
+			notification.Toast(ctx, ssd.Logger, rw, r, "error" , &map[string]string{"Message" : data.T("web-core-auth-cfg-tnt-imp-form.warning-cfg-not-found")}, data)
+			return
+		}
+
+		chg, chgErr := Diff(cfgRs[0].Cfg, b.Config)
+		if chgErr != nil {
+			error.IntSrv(ctx, rw, chgErr)
+			return
+		}
+
+		secKeys := make([]string, 0, len(sec))
+		for k := range sec {
+			secKeys = append(secKeys, k)
+		}
+
+		ssd.Logger.LogAttrs(ctx, slog.LevelDebug, "Put::dry run",
+			slog.Int("len(chg)"     , len(chg)),
+			slog.Int("len(secKeys)" , len(secKeys)),
+		)
+
+		data.ResultSet = &map[string]any{
+			"Bundle"  : b,
+			"Changes" : &chg,
+			"Secrets" : &secKeys,
+		}
+
+		html.Fragment(ctx, ssd.Logger, rw, r, "core/auth/cfg/tnt/fragment/diff", http.StatusOK, &data)
+
+		ssd.Logger.LogAttrs(ctx, slog.LevelDebug, "Put::end [dry run]")
+
+		return
+	}
+
+	exptErrs := []string{
+		pgerrcode.CheckViolation,
+		pgerrcode.ForeignKeyViolation,
+	}
+
+	putErr := PutCfg(&ctx, ssd.Logger, ssd.Conn, ssd.TntId, b.Config, sec, data.User.AurNm, exptErrs)
+	if putErr != nil {
+		var pgErr *pgconn.PgError
+
+		message := data.T("web-core-auth-cfg-tnt-imp-form.warning-input-unexpected-error")
+
+		if errors.As(putErr, &pgErr) {
+			switch pgErr.Code {
+				case pgerrcode.CheckViolation, pgerrcode.ForeignKeyViolation:
+					message = data.T("web-core-auth-cfg-tnt-imp-form.warning-input-cfg-invalid", "detail", pgErr.Message)
+			}
+		}
+
+		notification.Toast(ctx, ssd.Logger, rw, r, "error" , &map[string]string{"Message" : message}, data)
+
+		return
+	}
+
+	rvkErr := ws.Revoke(&ctx, ssd.Logger, ssd.Conn, ws.Revocation{TntId: ssd.TntId})
+	if rvkErr != nil {
+		error.IntSrv(ctx, rw, rvkErr)
+		return
+	}
+
+	rfrErr := refresh.Notify(&ctx, ssd.Logger, ssd.Conn, r.Host)
+	if rfrErr != nil {
+		error.IntSrv(ctx, rw, rfrErr)
+		return
+	}
+
+	notification.Toast(ctx, ssd.Logger, rw, r, "success" , &map[string]string{"Message" : data.T("web-core-auth-cfg-tnt-imp-form.message-input-success", "origin", b.Origin, "created", b.Created.Format(time.DateTime))}, data)
+
+	ssd.Logger.LogAttrs(ctx, slog.LevelDebug, "Put::end")
+
+	return
+}
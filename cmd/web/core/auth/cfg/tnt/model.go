@@ -0,0 +1,108 @@
+package tnt
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+)
+
+import (
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+import (
+	"github.com/andrewah64/base-app-client/internal/common/core/db"
+)
+
+type CfgInf struct {
+	TntOrigin string
+	Cfg       []byte
+}
+
+type SecInf struct {
+	SecKey string
+	SecVal string
+}
+
+// GetCfgInf returns the tenant's aupc, aukc, occ, s2c, group and role grant
+// configuration as a single json document. Collections are keyed by name so
+// that bundles can be compared and applied across tenants.
+func GetCfgInf (ctx *context.Context, logger *slog.Logger, conn *pgxpool.Conn, tntId int) ([]CfgInf, error) {
+	rs, rErr := db.DataSet[CfgInf](ctx, logger, conn,
+		func(ctx *context.Context, tx *pgx.Tx)(string, string, *pgx.Rows, error){
+			dbFunc := "cfg_inf"
+			qry    := fmt.Sprintf("select web_core_auth_cfg_tnt_inf.%v($1, $2)", dbFunc)
+
+			c, cErr := (*tx).Query(*ctx, qry, dbFunc, tntId)
+			if cErr != nil {
+				slog.LogAttrs(*ctx, slog.LevelError, "get dataset",
+					slog.String("error" , cErr.Error()),
+					slog.String("qry"   , qry),
+					slog.Int   ("tntId" , tntId),
+				)
+
+				return qry, dbFunc, nil, fmt.Errorf("call database function: %w", cErr)
+			}
+
+			return qry, dbFunc, &c, nil
+		})
+
+	return rs, rErr
+}
+
+// GetSecInf returns the secrets, e.g. OIDC client secrets, that are held
+// outside of the configuration document.
+func GetSecInf (ctx *context.Context, logger *slog.Logger, conn *pgxpool.Conn, tntId int) ([]SecInf, error) {
+	rs, rErr := db.DataSet[SecInf](ctx, logger, conn,
+		func(ctx *context.Context, tx *pgx.Tx)(string, string, *pgx.Rows, error){
+			dbFunc := "sec_inf"
+			qry    := fmt.Sprintf("select web_core_auth_cfg_tnt_inf.%v($1, $2)", dbFunc)
+
+			c, cErr := (*tx).Query(*ctx, qry, dbFunc, tntId)
+			if cErr != nil {
+				slog.LogAttrs(*ctx, slog.LevelError, "get dataset",
+					slog.String("error" , cErr.Error()),
+					slog.String("qry"   , qry),
+					slog.Int   ("tntId" , tntId),
+				)
+
+				return qry, dbFunc, nil, fmt.Errorf("call database function: %w", cErr)
+			}
+
+			return qry, dbFunc, &c, nil
+		})
+
+	return rs, rErr
+}
+
+// PutCfg replaces the tenant's configuration. Secrets that are not supplied
+// are left unchanged.
+func PutCfg (ctx *context.Context, logger *slog.Logger, conn *pgxpool.Conn, tntId int, cfg []byte, sec map[string]string, by string, exptErrs []string) error {
+	var (
+		sprocCall   = "call web_core_auth_cfg_tnt_mod.mod_cfg(@p_tnt_id, @p_cfg, @p_sec, @p_by)"
+		sprocParams = pgx.NamedArgs{
+			"p_tnt_id" : tntId,
+			"p_cfg"    : string(cfg),
+			"p_sec"    : sec,
+			"p_by"     : by,
+		}
+	)
+
+	sprocErr := db.Sproc(ctx, logger, conn, sprocCall, sprocParams, exptErrs)
+	if sprocErr != nil {
+		logger.LogAttrs(*ctx, slog.LevelDebug, "call sproc",
+			slog.String("sprocCall" , sprocCall),
+			slog.String("error"     , sprocErr.Error()),
+			slog.Int   ("tntId"     , tntId),
+			slog.Int   ("len(cfg)"  , len(cfg)),
+			slog.Int   ("len(sec)"  , len(sec)),
+			slog.String("by"        , by),
+			slog.Any   ("exptErrs"  , exptErrs),
+		)
+
+		return sprocErr
+	}
+
+	return nil
+}
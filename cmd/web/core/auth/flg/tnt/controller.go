@@ -0,0 +1,250 @@
+package tnt
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+import (
+	"github.com/andrewah64/base-app-client/internal/common/core/session"
+	"github.com/andrewah64/base-app-client/internal/web/core/error"
+	"github.com/andrewah64/base-app-client/internal/web/core/feature"
+	"github.com/andrewah64/base-app-client/internal/web/core/ui/data/form"
+	"github.com/andrewah64/base-app-client/internal/web/core/ui/data/page"
+	"github.com/andrewah64/base-app-client/internal/web/core/ui/html"
+	"github.com/andrewah64/base-app-client/internal/web/core/ui/notification"
+)
+
+import (
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+func Delete (rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	ssd, ok := session.FromContext(ctx)
+	if ! ok {
+		error.IntSrv(ctx, rw, fmt.Errorf("Delete::get request info"))
+		return
+	}
+
+	ssd.Logger.LogAttrs(ctx, slog.LevelDebug, "Delete::start")
+
+	data, ok := page.FromContext(ctx)
+	if ! ok {
+		error.IntSrv(ctx, rw, fmt.Errorf("Delete::get request data"))
+		return
+	}
+
+	pfErr := r.ParseForm()
+	if pfErr != nil {
+		error.IntSrv(ctx, rw, pfErr)
+		return
+	}
+
+	fgvId, fgvIdErr := form.VIntArray(r, "flg-tnt-inf-fgv-id")
+	if fgvIdErr != nil {
+		error.IntSrv(ctx, rw, fgvIdErr)
+		return
+	}
+
+	ssd.Logger.LogAttrs(ctx, slog.LevelDebug, "Delete::fgvId",
+		slog.Any("fgvId", fgvId),
+	)
+
+	if len(fgvId) > 0 {
+		delErr := DelFgv(&ctx, ssd.Logger, ssd.Conn, ssd.TntId, fgvId, nil)
+		if delErr != nil {
+			error.IntSrv(ctx, rw, delErr)
+			return
+		}
+
+		ntfErr := feature.Notify(&ctx, ssd.Logger, ssd.Conn, ssd.TntId)
+		if ntfErr != nil {
+			error.IntSrv(ctx, rw, ntfErr)
+			return
+		}
+
+		ssd.Logger.LogAttrs(ctx, slog.LevelDebug, "Delete::success")
+
+		rw.Header().Set("HX-Trigger", "mod")
+
+		message := ""
+
+		if len(fgvId) == 1 {
+			message = data.T("web-core-auth-flg-tnt-del-form.message-delete-success-singular", "n", strconv.Itoa(len(fgvId)))
+		} else {
+			message = data.T("web-core-auth-flg-tnt-del-form.message-delete-success-plural"  , "n", strconv.Itoa(len(fgvId)))
+		}
+
+		notification.Toast(ctx, ssd.Logger, rw, r, "success" , &map[string]string{"Message" : message}, data)
+	}
+
+	ssd.Logger.LogAttrs(ctx, slog.LevelDebug, "Delete::end")
+
+	return
+}
+
+func Get (rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	ssd, ok := session.FromContext(ctx)
+	if ! ok {
+		error.IntSrv(ctx, rw, fmt.Errorf("Get::get request info"))
+		return
+	}
+
+	ssd.Logger.LogAttrs(ctx, slog.LevelDebug, "Get::start")
+
+	data, ok := page.FromContext(ctx)
+	if ! ok {
+		error.IntSrv(ctx, rw, fmt.Errorf("Get::get request data"))
+		return
+	}
+
+	trigger := html.Trigger(r, "")
+
+	ssd.Logger.LogAttrs(ctx, slog.LevelDebug, "Get::retrieve datasets",
+		slog.String("trigger" , trigger),
+	)
+
+	fgvRs, fgvRsErr := GetFgv(&ctx, ssd.Logger, ssd.Conn, ssd.TntId)
+	if fgvRsErr != nil {
+		error.IntSrv(ctx, rw, fgvRsErr)
+		return
+	}
+
+	ssd.Logger.LogAttrs(ctx, slog.LevelDebug, "Get::retrieve datasets",
+		slog.Int("len(fgvRs)" , len(fgvRs)),
+	)
+
+	data.ResultSet = &map[string]any{"Search": &fgvRs}
+
+	switch trigger {
+		case "": // page load
+			optsInfRs, optsInfRsErr := OptsInf(&ctx, ssd.Logger, ssd.Conn, ssd.TntId)
+			if optsInfRsErr != nil {
+				error.IntSrv(ctx, rw, optsInfRsErr)
+				return
+			}
+
+			ssd.Logger.LogAttrs(ctx, slog.LevelDebug, "Get::retrieve datasets",
+				slog.Int("len(*optsInfRs)" , len(*optsInfRs)),
+			)
+
+			data.FormOpts = &map[string]any{"Reg": &optsInfRs}
+
+			html.Tmpl(ctx, ssd.Logger, rw, r, "core/auth/flg/tnt/content", http.StatusOK, &data)
+
+			ssd.Logger.LogAttrs(ctx, slog.LevelDebug, "Get::end [page load]")
+
+		case "flg-tnt-inf-res": // refresh after a change
+			html.Tmpl(ctx, ssd.Logger, rw, r, "core/auth/flg/tnt/template/res", http.StatusOK, &data)
+
+			ssd.Logger.LogAttrs(ctx, slog.LevelDebug, "Get::end [refresh]")
+	}
+
+	return
+}
+
+func Post (rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	ssd, ok := session.FromContext(ctx)
+	if ! ok {
+		error.IntSrv(ctx, rw, fmt.Errorf("Post::get request info"))
+		return
+	}
+
+	ssd.Logger.LogAttrs(ctx, slog.LevelDebug, "Post::start")
+
+	data, ok := page.FromContext(ctx)
+	if ! ok {
+		error.IntSrv(ctx, rw, fmt.Errorf("Post::get request data"))
+		return
+	}
+
+	pfErr := r.ParseForm()
+	if pfErr != nil {
+		error.IntSrv(ctx, rw, pfErr)
+		return
+	}
+
+	flgId  := form.VInt  (r, "flg-tnt-reg-flg-id")
+	grpId  := form.VInt  (r, "flg-tnt-reg-grp-id")
+	fgvVal := form.VText (r, "flg-tnt-reg-fgv-val")
+
+	ssd.Logger.LogAttrs(ctx, slog.LevelDebug, "Post::get data from form",
+		slog.Int   ("flgId"  , flgId),
+		slog.Int   ("grpId"  , grpId),
+		slog.String("fgvVal" , fgvVal),
+	)
+
+	flgRs, flgRsErr := GetFlg(&ctx, ssd.Logger, ssd.Conn, ssd.TntId, flgId)
+	if flgRsErr != nil {
+		error.IntSrv(ctx, rw, flgRsErr)
+		return
+	}
+
+	if len(flgRs) == 0 {
+		notification.Toast(ctx, ssd.Logger, rw, r, "error" , &map[string]string{"Message" : data.T("web-core-auth-flg-tnt-reg-form.warning-input-flg-not-found")}, data)
+
+		return
+	}
+
+	f, ok := feature.Parse(flgRs[0].FlgNm, flgRs[0].FlgTyp, fgvVal)
+	if ! ok {
+		notification.Toast(ctx, ssd.Logger, rw, r, "error" , &map[string]string{"Message" : data.T(fmt.Sprintf("web-core-auth-flg-tnt-reg-form.warning-input-fgv-val-%v", flgRs[0].FlgTyp), "flgNm", flgRs[0].FlgNm)}, data)
+
+		return
+	}
+
+	exptErrs := []string{
+		pgerrcode.ForeignKeyViolation,
+		pgerrcode.UniqueViolation,
+	}
+
+	var grp *int
+
+	if grpId != feature.TenantWide {
+		grp = &grpId
+	}
+
+	regErr := PostFgv(&ctx, ssd.Logger, ssd.Conn, ssd.TntId, flgId, grp, f.String(), data.User.AurNm, exptErrs)
+	if regErr != nil {
+		var pgErr *pgconn.PgError
+
+		message := data.T("web-core-auth-flg-tnt-reg-form.warning-input-unexpected-error")
+
+		if errors.As(regErr, &pgErr) {
+			switch pgErr.Code {
+				case pgerrcode.ForeignKeyViolation:
+					message = data.T("web-core-auth-flg-tnt-reg-form.warning-input-grp-not-found")
+				case pgerrcode.UniqueViolation:
+					message = data.T("web-core-auth-flg-tnt-reg-form.warning-input-fgv-taken", "flgNm", flgRs[0].FlgNm)
+			}
+		}
+
+		notification.Toast(ctx, ssd.Logger, rw, r, "error" , &map[string]string{"Message" : message}, data)
+
+		return
+	}
+
+	ntfErr := feature.Notify(&ctx, ssd.Logger, ssd.Conn, ssd.TntId)
+	if ntfErr != nil {
+		error.IntSrv(ctx, rw, ntfErr)
+		return
+	}
+
+	rw.Header().Set("HX-Trigger", "mod")
+
+	notification.Toast(ctx, ssd.Logger, rw, r, "success" , &map[string]string{"Message" : data.T("web-core-auth-flg-tnt-reg-form.message-input-success", "flgNm", flgRs[0].FlgNm)}, data)
+
+	ssd.Logger.LogAttrs(ctx, slog.LevelDebug, "Post::end")
+
+	return
+}
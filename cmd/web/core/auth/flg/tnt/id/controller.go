@@ -0,0 +1,178 @@
+package id
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+import (
+	"github.com/andrewah64/base-app-client/internal/common/core/session"
+	"github.com/andrewah64/base-app-client/internal/web/core/error"
+	"github.com/andrewah64/base-app-client/internal/web/core/feature"
+	"github.com/andrewah64/base-app-client/internal/web/core/ui/data/form"
+	"github.com/andrewah64/base-app-client/internal/web/core/ui/data/page"
+	"github.com/andrewah64/base-app-client/internal/web/core/ui/html"
+	"github.com/andrewah64/base-app-client/internal/web/core/ui/notification"
+)
+
+import (
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+func Get(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	ssd, ok := session.FromContext(ctx)
+	if ! ok {
+		error.IntSrv(ctx, rw, fmt.Errorf("Get::get request info"))
+		return
+	}
+
+	ssd.Logger.LogAttrs(ctx, slog.LevelDebug, "Get::start")
+
+	data, ok := page.FromContext(ctx)
+	if ! ok {
+		error.IntSrv(ctx, rw, fmt.Errorf("Get::get request data"))
+		return
+	}
+
+	fgvId, fgvIdErr := strconv.Atoi(r.PathValue("id"))
+	if fgvIdErr != nil || fgvId < 1 {
+		error.IntSrv(ctx, rw, fmt.Errorf("Get::get fgvId"))
+		return
+	}
+
+	fgvRs, fgvRsErr := GetRowFgvMod(&ctx, ssd.Logger, ssd.Conn, ssd.TntId, fgvId)
+	if fgvRsErr != nil {
+		error.IntSrv(ctx, rw, fgvRsErr)
+		return
+	}
+
+	ssd.Logger.LogAttrs(ctx, slog.LevelDebug, "Get::retrieve datasets",
+		slog.Int("fgvId"      , fgvId),
+		slog.Int("len(fgvRs)" , len(fgvRs)),
+	)
+
+	data.ResultSet = &map[string]any{"Flag": &fgvRs}
+
+	html.Fragment(ctx, ssd.Logger, rw, r, "core/auth/flg/tnt/fragment/modrow", http.StatusCreated, &data)
+
+	if len(fgvRs) == 0 {
+		notification.Toast(ctx, slog.Default(), rw, r, "error" , &map[string]string{"Message" : data.T("web-core-auth-flg-tnt-mod-form.warning-input-fgv-olock-error")}, data)
+	}
+
+	ssd.Logger.LogAttrs(ctx, slog.LevelDebug, "Get::end")
+
+	return
+}
+
+func Patch(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	ssd, ok := session.FromContext(ctx)
+	if ! ok {
+		error.IntSrv(ctx, rw, fmt.Errorf("Patch::get request info"))
+		return
+	}
+
+	ssd.Logger.LogAttrs(ctx, slog.LevelDebug, "Patch::start")
+
+	data, ok := page.FromContext(ctx)
+	if ! ok {
+		error.IntSrv(ctx, rw, fmt.Errorf("Patch::get request data"))
+		return
+	}
+
+	fgvId, fgvIdErr := strconv.Atoi(r.PathValue("id"))
+	if fgvIdErr != nil || fgvId < 1 {
+		error.Status(ctx, rw, http.StatusNotFound)
+		return
+	}
+
+	pfErr := r.ParseForm()
+	if pfErr != nil {
+		error.IntSrv(ctx, rw, pfErr)
+		return
+	}
+
+	fgvVal := form.VText (r, fmt.Sprintf("flg-tnt-mod-fgv-val-%v", fgvId))
+	uts    := form.VTime (r, fmt.Sprintf("flg-tnt-mod-uts-%v"    , fgvId))
+
+	ssd.Logger.LogAttrs(ctx, slog.LevelDebug, "Patch::get data from form",
+		slog.Int   ("fgvId"  , fgvId),
+		slog.String("fgvVal" , fgvVal),
+		slog.Any   ("uts"    , uts),
+	)
+
+	fgvRs, fgvRsErr := GetRowFgvInf(&ctx, ssd.Logger, ssd.Conn, ssd.TntId, fgvId)
+	if fgvRsErr != nil {
+		error.IntSrv(ctx, rw, fgvRsErr)
+		return
+	}
+
+	if len(fgvRs) == 0 {
+		Get(rw, r)
+		return
+	}
+
+	f, ok := feature.Parse(fgvRs[0].FlgNm, fgvRs[0].FlgTyp, fgvVal)
+	if ! ok {
+		Get(rw, r)
+
+		notification.Toast(ctx, slog.Default(), rw, r, "error" , &map[string]string{"Message" : data.T(fmt.Sprintf("web-core-auth-flg-tnt-reg-form.warning-input-fgv-val-%v", fgvRs[0].FlgTyp), "flgNm", fgvRs[0].FlgNm)}, data)
+
+		return
+	}
+
+	exptErrs := []string{
+		"OLOKU",
+		"OLOKD",
+	}
+
+	patchErr := PatchFgv(&ctx, ssd.Logger, ssd.Conn, ssd.TntId, fgvId, f.String(), data.User.AurNm, uts, exptErrs)
+	if patchErr != nil {
+		Get(rw, r)
+
+		var pgErr *pgconn.PgError
+
+		if errors.As(patchErr, &pgErr) {
+			switch pgErr.Code {
+				case "OLOKU":
+					notification.Toast(ctx, slog.Default(), rw, r, "error" , &map[string]string{"Message" : data.T("web-core-auth-flg-tnt-mod-form.warning-input-fgv-olock-error")}, data)
+
+				case "OLOKD":
+					//intentionally empty
+
+				default:
+					notification.Toast(ctx, slog.Default(), rw, r, "error" , &map[string]string{"Message" : data.T("web-core-auth-flg-tnt-mod-form.warning-input-unexpected-error")}, data)
+			}
+		}
+
+		return
+	}
+
+	ntfErr := feature.Notify(&ctx, ssd.Logger, ssd.Conn, ssd.TntId)
+	if ntfErr != nil {
+		error.IntSrv(ctx, rw, ntfErr)
+		return
+	}
+
+	fgvRs, fgvRsErr = GetRowFgvInf(&ctx, ssd.Logger, ssd.Conn, ssd.TntId, fgvId)
+	if fgvRsErr != nil {
+		error.IntSrv(ctx, rw, fgvRsErr)
+		return
+	}
+
+	data.ResultSet = &map[string]any{"Flag": &fgvRs}
+
+	html.Fragment(ctx, ssd.Logger, rw, r, "core/auth/flg/tnt/fragment/infrow", http.StatusCreated, &data)
+
+	notification.Toast(ctx, slog.Default(), rw, r, "success" , &map[string]string{"Message" : data.T("web-core-auth-flg-tnt-mod-form.message-input-success", "flgNm", f.Name)}, data)
+
+	ssd.Logger.LogAttrs(ctx, slog.LevelDebug, "Patch::end")
+
+	return
+}
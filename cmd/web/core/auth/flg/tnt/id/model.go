@@ -0,0 +1,127 @@
+package id
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+import (
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+import (
+	"github.com/andrewah64/base-app-client/internal/common/core/db"
+)
+
+type Inf struct {
+	FgvId  int
+	FlgNm  string
+	FlgTyp string
+	FlgDsc string
+	GrpId  *int
+	GrpNm  *string
+	FgvVal string
+}
+
+type Mod struct {
+	FgvId  int
+	FlgNm  string
+	FlgTyp string
+	FlgDsc string
+	GrpId  *int
+	GrpNm  *string
+	FgvVal string
+	Uts    time.Time
+}
+
+const (
+	dbSchema = "web_core_auth_flg_tnt_mod"
+)
+
+func GetRowFgvInf (ctx *context.Context, logger *slog.Logger, conn *pgxpool.Conn, tntId int, fgvId int) ([]Inf, error) {
+	const (
+		dbFunc = "row_fgv_inf"
+	)
+
+	rs, rErr := db.DataSet[Inf](ctx, logger, conn,
+		func(ctx *context.Context, tx *pgx.Tx)(string, string, *pgx.Rows, error){
+			qry := fmt.Sprintf("select %v.%v($1, $2, $3)", dbSchema, dbFunc)
+
+			c, cErr := (*tx).Query(*ctx, qry, dbFunc, tntId, fgvId)
+			if cErr != nil {
+				slog.LogAttrs(*ctx, slog.LevelError, "get dataset",
+					slog.String("error" , cErr.Error()),
+					slog.String("qry"   , qry),
+					slog.Int   ("tntId" , tntId),
+					slog.Int   ("fgvId" , fgvId),
+				)
+
+				return qry, dbFunc, nil, fmt.Errorf("call database function: %w", cErr)
+			}
+
+			return qry, dbFunc, &c, nil
+		})
+
+	return rs, rErr
+}
+
+func GetRowFgvMod (ctx *context.Context, logger *slog.Logger, conn *pgxpool.Conn, tntId int, fgvId int) ([]Mod, error) {
+	const (
+		dbFunc = "row_fgv_mod"
+	)
+
+	rs, rErr := db.DataSet[Mod](ctx, logger, conn,
+		func(ctx *context.Context, tx *pgx.Tx)(string, string, *pgx.Rows, error){
+			qry := fmt.Sprintf("select %v.%v($1, $2, $3)", dbSchema, dbFunc)
+
+			c, cErr := (*tx).Query(*ctx, qry, dbFunc, tntId, fgvId)
+			if cErr != nil {
+				slog.LogAttrs(*ctx, slog.LevelError, "get dataset",
+					slog.String("error" , cErr.Error()),
+					slog.String("qry"   , qry),
+					slog.Int   ("tntId" , tntId),
+					slog.Int   ("fgvId" , fgvId),
+				)
+
+				return qry, dbFunc, nil, fmt.Errorf("call database function: %w", cErr)
+			}
+
+			return qry, dbFunc, &c, nil
+		})
+
+	return rs, rErr
+}
+
+func PatchFgv (ctx *context.Context, logger *slog.Logger, conn *pgxpool.Conn, tntId int, fgvId int, fgvVal string, by string, uts time.Time, exptErrs []string) error {
+	var (
+		sprocCall   = fmt.Sprintf("call %v.row_mod_fgv(@p_tnt_id, @p_fgv_id, @p_fgv_val, @p_by, @p_uts)", dbSchema)
+		sprocParams = pgx.NamedArgs{
+			"p_tnt_id"  : tntId,
+			"p_fgv_id"  : fgvId,
+			"p_fgv_val" : fgvVal,
+			"p_by"      : by,
+			"p_uts"     : uts,
+		}
+	)
+
+	sprocErr := db.Sproc(ctx, logger, conn, sprocCall, sprocParams, exptErrs)
+	if sprocErr != nil {
+		logger.LogAttrs(*ctx, slog.LevelDebug, "call sproc",
+			slog.String("sprocCall" , sprocCall),
+			slog.String("error"     , sprocErr.Error()),
+			slog.Int   ("tntId"     , tntId),
+			slog.Int   ("fgvId"     , fgvId),
+			slog.String("fgvVal"    , fgvVal),
+			slog.String("by"        , by),
+			slog.Any   ("uts"       , uts),
+			slog.Any   ("exptErrs"  , exptErrs),
+		)
+
+		return sprocErr
+	}
+
+	return nil
+}
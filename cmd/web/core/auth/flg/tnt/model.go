@@ -0,0 +1,175 @@
+package tnt
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+)
+
+import (
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+import (
+	"github.com/andrewah64/base-app-client/internal/common/core/db"
+)
+
+type Inf struct {
+	FgvId  int
+	FlgNm  string
+	FlgTyp string
+	FlgDsc string
+	GrpId  *int
+	GrpNm  *string
+	FgvVal string
+}
+
+type Flg struct {
+	FlgId  int
+	FlgNm  string
+	FlgTyp string
+}
+
+type Opt struct {
+	Key   string
+	Id    int
+	Value string
+}
+
+func DelFgv (ctx *context.Context, logger *slog.Logger, conn *pgxpool.Conn, tntId int, fgvId []int, exptErrs []string) error {
+	var (
+		sprocCall   = "call web_core_auth_flg_tnt_del.del_fgv(@p_tnt_id, @p_fgv_id)"
+		sprocParams = pgx.NamedArgs{
+			"p_tnt_id" : tntId,
+			"p_fgv_id" : fgvId,
+		}
+	)
+
+	sprocErr := db.Sproc(ctx, logger, conn, sprocCall, sprocParams, exptErrs)
+	if sprocErr != nil {
+		logger.LogAttrs(*ctx, slog.LevelDebug, "call sproc",
+			slog.String("sprocCall" , sprocCall),
+			slog.String("error"     , sprocErr.Error()),
+			slog.Int   ("tntId"     , tntId),
+			slog.Any   ("fgvId"     , fgvId),
+			slog.Any   ("exptErrs"  , exptErrs),
+		)
+
+		return sprocErr
+	}
+
+	return nil
+}
+
+func GetFgv (ctx *context.Context, logger *slog.Logger, conn *pgxpool.Conn, tntId int) ([]Inf, error) {
+	rs, rErr := db.DataSet[Inf](ctx, logger, conn,
+		func(ctx *context.Context, tx *pgx.Tx)(string, string, *pgx.Rows, error){
+			dbFunc := "fgv_inf"
+			qry    := fmt.Sprintf("select web_core_auth_flg_tnt_inf.%v($1, $2)", dbFunc)
+
+			c, cErr := (*tx).Query(*ctx, qry, dbFunc, tntId)
+			if cErr != nil {
+				slog.LogAttrs(*ctx, slog.LevelError, "get dataset",
+					slog.String("error" , cErr.Error()),
+					slog.String("qry"   , qry),
+					slog.Int   ("tntId" , tntId),
+				)
+
+				return qry, dbFunc, nil, fmt.Errorf("call database function: %w", cErr)
+			}
+
+			return qry, dbFunc, &c, nil
+		})
+
+	return rs, rErr
+}
+
+func GetFlg (ctx *context.Context, logger *slog.Logger, conn *pgxpool.Conn, tntId int, flgId int) ([]Flg, error) {
+	rs, rErr := db.DataSet[Flg](ctx, logger, conn,
+		func(ctx *context.Context, tx *pgx.Tx)(string, string, *pgx.Rows, error){
+			dbFunc := "flg_inf"
+			qry    := fmt.Sprintf("select web_core_auth_flg_tnt_inf.%v($1, $2, $3)", dbFunc)
+
+			c, cErr := (*tx).Query(*ctx, qry, dbFunc, tntId, flgId)
+			if cErr != nil {
+				slog.LogAttrs(*ctx, slog.LevelError, "get dataset",
+					slog.String("error" , cErr.Error()),
+					slog.String("qry"   , qry),
+					slog.Int   ("tntId" , tntId),
+					slog.Int   ("flgId" , flgId),
+				)
+
+				return qry, dbFunc, nil, fmt.Errorf("call database function: %w", cErr)
+			}
+
+			return qry, dbFunc, &c, nil
+		})
+
+	return rs, rErr
+}
+
+func OptsInf (ctx *context.Context, logger *slog.Logger, conn *pgxpool.Conn, tntId int) (*map[string][]Opt, error) {
+	rs, rErr := db.DataSet[Opt](ctx, logger, conn,
+		func(ctx *context.Context, tx *pgx.Tx)(string, string, *pgx.Rows, error){
+			dbFunc := "ref_inf"
+			qry    := fmt.Sprintf("select web_core_auth_flg_tnt_inf.%v($1, $2)", dbFunc)
+
+			c, cErr := (*tx).Query(*ctx, qry, dbFunc, tntId)
+			if cErr != nil {
+				slog.LogAttrs(*ctx, slog.LevelError, "get dataset",
+					slog.String("error"   , cErr.Error()),
+					slog.String("qry"     , qry),
+					slog.Int   ("tntId"   , tntId),
+				)
+
+				return qry, dbFunc, nil, fmt.Errorf("call database function: %w", cErr)
+			}
+
+			return qry, dbFunc, &c, nil
+		},
+	)
+
+	if rErr != nil {
+		return nil, fmt.Errorf("get Opts dataset: %w", rErr)
+	}
+
+	idValMap := make(map[string][]Opt)
+
+	for _, v := range rs {
+		idValMap[v.Key] = append(idValMap[v.Key], Opt{Id: v.Id, Value: v.Value})
+	}
+
+	return &idValMap, nil
+}
+
+func PostFgv (ctx *context.Context, logger *slog.Logger, conn *pgxpool.Conn, tntId int, flgId int, grpId *int, fgvVal string, by string, exptErrs []string) error {
+	var (
+		sprocCall   = "call web_core_auth_flg_tnt_reg.reg_fgv(@p_tnt_id, @p_flg_id, @p_grp_id, @p_fgv_val, @p_by)"
+		sprocParams = pgx.NamedArgs{
+			"p_tnt_id"  : tntId,
+			"p_flg_id"  : flgId,
+			"p_grp_id"  : grpId,
+			"p_fgv_val" : fgvVal,
+			"p_by"      : by,
+		}
+	)
+
+	sprocErr := db.Sproc(ctx, logger, conn, sprocCall, sprocParams, exptErrs)
+	if sprocErr != nil {
+		logger.LogAttrs(*ctx, slog.LevelDebug, "call sproc",
+			slog.String("sprocCall" , sprocCall),
+			slog.String("error"     , sprocErr.Error()),
+			slog.Int   ("tntId"     , tntId),
+			slog.Int   ("flgId"     , flgId),
+			slog.Any   ("grpId"     , grpId),
+			slog.String("fgvVal"    , fgvVal),
+			slog.String("by"        , by),
+			slog.Any   ("exptErrs"  , exptErrs),
+		)
+
+		return sprocErr
+	}
+
+	return nil
+}
@@ -8,12 +8,13 @@ import (
 )
 
 import (
-	"github.com/andrewah64/base-app-client/internal/common/core/session"
-	"github.com/andrewah64/base-app-client/internal/web/core/error"
-	"github.com/andrewah64/base-app-client/internal/web/core/ui/data/form"
-	"github.com/andrewah64/base-app-client/internal/web/core/ui/data/page"
-	"github.com/andrewah64/base-app-client/internal/web/core/ui/html"
-	"github.com/andrewah64/base-app-client/internal/web/core/ui/notification"
+	   "github.com/andrewah64/base-app-client/internal/common/core/session"
+	   "github.com/andrewah64/base-app-client/internal/web/core/error"
+	ws "github.com/andrewah64/base-app-client/internal/web/core/session"
+	   "github.com/andrewah64/base-app-client/internal/web/core/ui/data/form"
+	   "github.com/andrewah64/base-app-client/internal/web/core/ui/data/page"
+	   "github.com/andrewah64/base-app-client/internal/web/core/ui/html"
+	   "github.com/andrewah64/base-app-client/internal/web/core/ui/notification"
 )
 
 func Get (rw http.ResponseWriter, r *http.Request) {
@@ -35,7 +36,7 @@ func Get (rw http.ResponseWriter, r *http.Request) {
 
 	aurId, aurIdErr := strconv.Atoi(r.PathValue("id"))
 	if aurIdErr != nil || aurId < 1 {
-		http.NotFound(rw, r)
+		error.Status(ctx, rw, http.StatusNotFound)
 		return
 	}
 
@@ -83,7 +84,7 @@ func Patch(rw http.ResponseWriter, r *http.Request){
 
 	tgtAurId, tgtAurIdErr := strconv.Atoi(r.PathValue("id"))
 	if tgtAurIdErr != nil || tgtAurId < 1 {
-		http.NotFound(rw, r)
+		error.Status(ctx, rw, http.StatusNotFound)
 		return
 	}
 
@@ -110,6 +111,12 @@ func Patch(rw http.ResponseWriter, r *http.Request){
 		return
 	}
 
+	rvkErr := ws.Revoke(&ctx, ssd.Logger, ssd.Conn, ws.Revocation{TntId: ssd.TntId, AurId: []int{tgtAurId}})
+	if rvkErr != nil {
+		error.IntSrv(ctx, rw, rvkErr)
+		return
+	}
+
 	data.ResultSet = &map[string]any{"GroupCount": len(grpId)}
 
 	numGrps := len(grpId)
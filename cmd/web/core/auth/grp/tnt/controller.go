@@ -120,7 +120,7 @@ func Get (rw http.ResponseWriter, r *http.Request) {
 	pageNumber  := 2
 	offset      := 0
 	resultLimit := 50
-	trigger     := r.Header.Get("HX-Trigger")
+	trigger     := html.Trigger(r, "grp-tnt-inf-form")
 
 	ssd.Logger.LogAttrs(ctx, slog.LevelDebug, "Get::retrieve datasets",
 		slog.Int   ("pageNumber"  , pageNumber),
@@ -232,15 +232,26 @@ func Get (rw http.ResponseWriter, r *http.Request) {
 				slog.Int("len(grpRs)" , len(grpRs)),
 			)
 
+			if ! html.Htmx(r) {
+				optsInfRs, optsInfRsErr := OptsInf(&ctx, ssd.Logger, ssd.Conn, ssd.TntId)
+				if optsInfRsErr != nil {
+					error.IntSrv(ctx, rw, optsInfRsErr)
+					return
+				}
+
+				data.FormOpts = &map[string]any{"Search": &optsInfRs}
+			}
+
 			data.ResultSet = &map[string]any{
 				"Search"      : &grpRs,
+				"PageNumber"  : pageNumber,
 				"ResultLimit" : resultLimit,
 				"Params"      : params(grpNm, aurNm, dbrlId, pageNumber),
 			}
 
 			rw.Header().Set("HX-Trigger", "src")
 
-			html.Tmpl(ctx, ssd.Logger, rw, r, "core/auth/grp/tnt/template/res", http.StatusOK, &data)
+			html.Partial(ctx, ssd.Logger, rw, r, "core/auth/grp/tnt/content", "core/auth/grp/tnt/template/res", http.StatusOK, &data)
 
 			ssd.Logger.LogAttrs(ctx, slog.LevelDebug, "Get::end [search]")
 	}
@@ -88,7 +88,7 @@ func Patch(rw http.ResponseWriter, r *http.Request) {
 
 	grpId, grpIdErr := strconv.Atoi(r.PathValue("id"))
 	if grpIdErr != nil || grpId < 1 {
-		http.NotFound(rw, r)
+		error.Status(ctx, rw, http.StatusNotFound)
 		return
 	}
 
@@ -126,7 +126,7 @@ func Get(rw http.ResponseWriter, r *http.Request){
 	pageNumber  := 2
 	offset      := 0
 	resultLimit := 50
-	trigger     := r.Header.Get("HX-Trigger")
+	trigger     := html.Trigger(r, "key-aur-inf-form")
 
 	ssd.Logger.LogAttrs(ctx, slog.LevelDebug, "Get::retrieve datasets",
 		slog.Int   ("pageNumber"  , pageNumber),
@@ -233,15 +233,26 @@ func Get(rw http.ResponseWriter, r *http.Request){
 				return
 			}
 
+			if ! html.Htmx(r) {
+				optsInfRs, optsInfRsErr := OptsInf(&ctx, ssd.Logger, ssd.Conn, ssd.TntId, data.User.AurId)
+				if optsInfRsErr != nil {
+					error.IntSrv(ctx, rw, optsInfRsErr)
+					return
+				}
+
+				data.FormOpts = &map[string]any{"Search" : &optsInfRs}
+			}
+
 			data.ResultSet = &map[string]any{
 				"Search"      : &keyRs,
+				"PageNumber"  : pageNumber,
 				"ResultLimit" : resultLimit,
 				"Params"      : params(aaukNm, dbrlId, aaukEnabled, pageNumber),
 			}
 
 			rw.Header().Set("HX-Trigger", "src")
 
-			html.Tmpl(ctx, ssd.Logger, rw, r, "core/auth/key/aur/template/res", http.StatusOK, &data)
+			html.Partial(ctx, ssd.Logger, rw, r, "core/auth/key/aur/content", "core/auth/key/aur/template/res", http.StatusOK, &data)
 
 			ssd.Logger.LogAttrs(ctx, slog.LevelDebug, "Get::end [search]")
 	}
@@ -63,7 +63,7 @@ func Get(rw http.ResponseWriter, r *http.Request) {
 	pageNumber  := 2
 	offset      := 0
 	resultLimit := 50
-	trigger     := r.Header.Get("HX-Trigger")
+	trigger     := html.Trigger(r, "log-aur-tnt-inf-form")
 
 	ssd.Logger.LogAttrs(ctx, slog.LevelDebug, "Get::retrieve datasets",
 		slog.Int   ("pageNumber"  , pageNumber),
@@ -174,13 +174,24 @@ func Get(rw http.ResponseWriter, r *http.Request) {
 				slog.Int("len(logRs)" , len(logRs)),
 			)
 
+			if ! html.Htmx(r) {
+				optsRs, optsRsErr := Opts(&ctx, ssd.Logger, ssd.Conn, ssd.TntId)
+				if optsRsErr != nil {
+					error.IntSrv(ctx, rw, optsRsErr)
+					return
+				}
+
+				data.FormOpts = &map[string]any{"Search": &optsRs}
+			}
+
 			data.ResultSet = &map[string]any{
 				"Search"      : &logRs,
+				"PageNumber"  : pageNumber,
 				"ResultLimit" : resultLimit,
 				"Params"      : params(aurNm, eppPt, hrmId, lvlId, pageNumber),
 			}
 
-			html.Tmpl(ctx, ssd.Logger, rw, r, "core/auth/log/aur/tnt/template/res", http.StatusOK, &data)
+			html.Partial(ctx, ssd.Logger, rw, r, "core/auth/log/aur/tnt/content", "core/auth/log/aur/tnt/template/res", http.StatusOK, &data)
 
 			ssd.Logger.LogAttrs(ctx, slog.LevelDebug, "Get::end [default]")
 	}
@@ -40,7 +40,7 @@ func Get(rw http.ResponseWriter, r *http.Request) {
 
 	auellId, auellIdErr := strconv.Atoi(r.PathValue("id"))
 	if auellIdErr != nil || auellId < 1 {
-		http.NotFound(rw, r)
+		error.Status(ctx, rw, http.StatusNotFound)
 		return
 	}
 
@@ -95,7 +95,7 @@ func Patch(rw http.ResponseWriter, r *http.Request) {
 
 	auellId, auellIdErr := strconv.Atoi(r.PathValue("id"))
 	if auellIdErr != nil || auellId < 1 {
-		http.NotFound(rw, r)
+		error.Status(ctx, rw, http.StatusNotFound)
 		return
 	}
 
@@ -86,7 +86,7 @@ func Patch(rw http.ResponseWriter, r *http.Request){
 
 	occId, occIdErr := strconv.Atoi(r.PathValue("id"))
 	if occIdErr != nil || occId < 1 {
-		http.NotFound(rw, r)
+		error.Status(ctx, rw, http.StatusNotFound)
 		return
 	}
 
@@ -123,9 +123,15 @@ func Patch(rw http.ResponseWriter, r *http.Request){
 		if errors.As(patchErr, &pgErr) {
 			switch pgErr.Code {
 				case "OLOCK":
-					currentUrl := r.Header.Get("HX-Current-URL")
-
-					rw.Header().Set("HX-Location", fmt.Sprintf(`{"path":"%v", "target":"#main", "select":"#content", "swap" : "innerHTML show:window:top", "values":{"ntf": "web-core-auth-occ-tnt-mod-form.warning-input-occ-olock-error", "lvl": "error"}}`, currentUrl))
+					currentUrl := html.CurrentURL(r)
+
+					html.Locate(rw, r, html.Location{
+						Path   : currentUrl,
+						Target : "#main",
+						Select : "#content",
+						Swap   : "innerHTML show:window:top",
+						Values : map[string]string{"ntf": "web-core-auth-occ-tnt-mod-form.warning-input-occ-olock-error", "lvl": "error"},
+					})
 
 				case pgerrcode.UniqueViolation:
 					notification.Toast(ctx, slog.Default(), rw, r, "error" , &map[string]string{"Message" : data.T("web-core-auth-occ-tnt-mod-form.warning-input-occ-url-taken")}, data)
@@ -0,0 +1,350 @@
+package aur
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+import (
+	"github.com/andrewah64/base-app-client/internal/common/core/session"
+	"github.com/andrewah64/base-app-client/internal/web/core/error"
+	"github.com/andrewah64/base-app-client/internal/web/core/passkey"
+	"github.com/andrewah64/base-app-client/internal/web/core/ui/data/form"
+	"github.com/andrewah64/base-app-client/internal/web/core/ui/data/page"
+	"github.com/andrewah64/base-app-client/internal/web/core/ui/html"
+	"github.com/andrewah64/base-app-client/internal/web/core/ui/notification"
+)
+
+import (
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+import (
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/protocol/webauthncose"
+	"github.com/go-webauthn/webauthn/webauthn"
+)
+
+func Delete (rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	ssd, ok := session.FromContext(ctx)
+	if ! ok {
+		error.IntSrv(ctx, rw, fmt.Errorf("Delete::get request info"))
+		return
+	}
+
+	ssd.Logger.LogAttrs(ctx, slog.LevelDebug, "Delete::start")
+
+	data, ok := page.FromContext(ctx)
+	if ! ok {
+		error.IntSrv(ctx, rw, fmt.Errorf("Delete::get request data"))
+		return
+	}
+
+	pfErr := r.ParseForm()
+	if pfErr != nil {
+		error.IntSrv(ctx, rw, pfErr)
+		return
+	}
+
+	pkyId, pkyIdErr := form.VIntArray(r, "pky-aur-mod-pky-id")
+	if pkyIdErr != nil {
+		error.IntSrv(ctx, rw, pkyIdErr)
+		return
+	}
+
+	ssd.Logger.LogAttrs(ctx, slog.LevelDebug, "Delete::get selected passkeys",
+		slog.Int("len(pkyId)", len(pkyId)),
+	)
+
+	if len(pkyId) > 0 {
+		exptErrs := []string{
+			"PKYLS",
+		}
+
+		delErr := DelPky(&ctx, ssd.Logger, ssd.Conn, ssd.TntId, data.User.AurId, pkyId, exptErrs)
+		if delErr != nil{
+			var pgErr *pgconn.PgError
+
+			if errors.As(delErr, &pgErr) && pgErr.Code == "PKYLS" {
+				notification.Toast(ctx, ssd.Logger, rw, r, "error" , &map[string]string{"Message" : data.T("web-core-auth-pky-aur-del-form.warning-input-pky-last")}, data)
+				return
+			}
+
+			error.IntSrv(ctx, rw, delErr)
+			return
+		}
+
+		ssd.Logger.LogAttrs(ctx, slog.LevelDebug, "Delete::success")
+
+		rw.Header().Set("HX-Trigger", "mod")
+
+		message := ""
+
+		if len(pkyId) == 1 {
+			message = data.T("web-core-auth-pky-aur-del-form.message-delete-success-singular", "n", strconv.Itoa(len(pkyId)))
+		} else {
+			message = data.T("web-core-auth-pky-aur-del-form.message-delete-success-plural"  , "n", strconv.Itoa(len(pkyId)))
+		}
+
+		notification.Toast(ctx, ssd.Logger, rw, r, "success" , &map[string]string{"Message" : message}, data)
+	}
+
+	ssd.Logger.LogAttrs(ctx, slog.LevelDebug, "Delete::end")
+
+	return
+}
+
+func Get(rw http.ResponseWriter, r *http.Request){
+	ctx := r.Context()
+
+	ssd, ok := session.FromContext(ctx)
+	if ! ok {
+		error.IntSrv(ctx, rw, fmt.Errorf("Get::get request info"))
+		return
+	}
+
+	ssd.Logger.LogAttrs(ctx, slog.LevelDebug, "Get::start")
+
+	data, ok := page.FromContext(ctx)
+	if ! ok {
+		error.IntSrv(ctx, rw, fmt.Errorf("Get::get request data"))
+		return
+	}
+
+	trigger := html.Trigger(r, "")
+
+	pkyRs, pkyRsErr := GetPky(&ctx, ssd.Logger, ssd.Conn, ssd.TntId, data.User.AurId)
+	if pkyRsErr != nil {
+		error.IntSrv(ctx, rw, pkyRsErr)
+		return
+	}
+
+	ssd.Logger.LogAttrs(ctx, slog.LevelDebug, "Get::retrieve pky dataset",
+		slog.String("trigger"    , trigger),
+		slog.Int   ("len(pkyRs)" , len(pkyRs)),
+	)
+
+	data.ResultSet = &map[string]any{"Search" : &pkyRs}
+
+	switch trigger {
+		case "pky-aur-inf-res": // passkey added or deleted
+			html.Tmpl(ctx, ssd.Logger, rw, r, "core/auth/pky/aur/template/res", http.StatusOK, &data)
+
+			ssd.Logger.LogAttrs(ctx, slog.LevelDebug, "Get::end [refresh]")
+
+		default: // page load
+			html.Tmpl(ctx, ssd.Logger, rw, r, "core/auth/pky/aur/content", http.StatusOK, &data)
+
+			ssd.Logger.LogAttrs(ctx, slog.LevelDebug, "Get::end [page load]")
+	}
+
+	return
+}
+
+func Post(rw http.ResponseWriter, r *http.Request){
+	ctx := r.Context()
+
+	ssd, ok := session.FromContext(ctx)
+	if ! ok {
+		error.IntSrv(ctx, rw, fmt.Errorf("Post::get request info"))
+		return
+	}
+
+	ssd.Logger.LogAttrs(ctx, slog.LevelDebug, "Post::start")
+
+	data, ok := page.FromContext(ctx)
+	if ! ok {
+		error.IntSrv(ctx, rw, fmt.Errorf("Post::get request data"))
+		return
+	}
+
+	user := &passkey.User{
+		Id          : []byte(data.User.AurNm),
+		Name        : data.User.AurNm,
+		DisplayName : data.User.AurNm,
+	}
+
+	switch r.PathValue("stp") {
+		case "reg-bgn":
+			excRs, excRsErr := GetExcInf(&ctx, ssd.Logger, ssd.Conn, ssd.TntId, data.User.AurId)
+			if excRsErr != nil {
+				error.IntSrv(ctx, rw, excRsErr)
+				return
+			}
+
+			// the user's authenticators are asked not to register a passkey
+			// they already hold for them a second time
+			var exc []protocol.CredentialDescriptor = make([]protocol.CredentialDescriptor, len(excRs))
+			for i, v := range excRs {
+				var t []protocol.AuthenticatorTransport = make([]protocol.AuthenticatorTransport, len(v.PkyAuthenticatorTransport))
+				for i, v := range v.PkyAuthenticatorTransport {
+					t[i] = protocol.AuthenticatorTransport(v)
+				}
+
+				exc[i] = protocol.CredentialDescriptor{
+					Type         : protocol.PublicKeyCredentialType,
+					CredentialID : v.PkyCredentialId,
+					Transport    : t,
+				}
+			}
+
+			aukcRegInfRs, aukcRegInfRsErr := GetAukcRegInf(&ctx, ssd.Logger, ssd.Conn, ssd.TntId)
+			if aukcRegInfRsErr != nil {
+				error.IntSrv(ctx, rw, aukcRegInfRsErr)
+				return
+			}
+
+			var pka protocol.ConveyancePreference = protocol.ConveyancePreference(aukcRegInfRs[0].PkaNm)
+
+			var pkg []protocol.CredentialParameter = make([]protocol.CredentialParameter, len(aukcRegInfRs[0].PkgCd))
+			for i, v := range aukcRegInfRs[0].PkgCd {
+				pkg[i] = protocol.CredentialParameter{
+					Type      : protocol.PublicKeyCredentialType,
+					Algorithm : webauthncose.COSEAlgorithmIdentifier(v),
+				}
+			}
+
+			var pkh []protocol.PublicKeyCredentialHints = make([]protocol.PublicKeyCredentialHints, len(aukcRegInfRs[0].PkhNm))
+			for i, v := range aukcRegInfRs[0].PkhNm {
+				pkh[i] = protocol.PublicKeyCredentialHints(v)
+			}
+
+			// older authenticators only know requireResidentKey, which must
+			// agree with the tenant's resident key setting
+			rrk := aukcRegInfRs[0].PdcNm == string(protocol.ResidentKeyRequirementRequired)
+
+			regOpts := []webauthn.RegistrationOption {
+				webauthn.WithAuthenticatorSelection(
+					protocol.AuthenticatorSelection {
+						AuthenticatorAttachment : protocol.AuthenticatorAttachment(aukcRegInfRs[0].PktNm),
+						RequireResidentKey      : &rrk,
+						ResidentKey             : protocol.ResidentKeyRequirement(aukcRegInfRs[0].PdcNm),
+						UserVerification        : protocol.UserVerificationRequirement(aukcRegInfRs[0].PuvNm),
+					},
+				),
+				webauthn.WithConveyancePreference(pka),
+				webauthn.WithCredentialParameters(pkg),
+				webauthn.WithPublicKeyCredentialHints(pkh),
+				webauthn.WithExclusions(exc),
+			}
+
+			c, s, brErr := passkey.WebAuthn(&ctx, ssd.Logger, ssd.TntId).BeginRegistration(user, regOpts...)
+			if brErr != nil {
+				error.IntSrv(ctx, rw, brErr)
+				return
+			}
+
+			ssd.Logger.LogAttrs(ctx, slog.LevelDebug, "Post::Begin passkey registration ceremony",
+				slog.Int("len(exc)" , len(exc)),
+			)
+
+			sd, sdErr := json.Marshal(s)
+			if sdErr != nil {
+				error.IntSrv(ctx, rw, sdErr)
+				return
+			}
+
+			regErr := PostPrs(&ctx, ssd.Logger, ssd.Conn, ssd.TntId, data.User.AurId, sd, nil)
+			if regErr != nil {
+				error.IntSrv(ctx, rw, regErr)
+				return
+			}
+
+			rw.Header().Set("Content-Type", "application/json")
+
+			json.NewEncoder(rw).Encode(c)
+
+		case "reg-end":
+			prsRs, prsRsErr := GetPrsInf(&ctx, ssd.Logger, ssd.Conn, ssd.TntId, data.User.AurId)
+			if prsRsErr != nil {
+				error.IntSrv(ctx, rw, prsRsErr)
+				return
+			}
+
+			if len(prsRs) == 0 {
+				error.Status(ctx, rw, http.StatusNotFound)
+				return
+			}
+
+			var sd webauthn.SessionData
+			sdErr := json.Unmarshal(prsRs[0].PrsJs, &sd)
+			if sdErr != nil {
+				error.IntSrv(ctx, rw, sdErr)
+				return
+			}
+
+			aukcRegInfRs, aukcRegInfRsErr := GetAukcRegInf(&ctx, ssd.Logger, ssd.Conn, ssd.TntId)
+			if aukcRegInfRsErr != nil {
+				error.IntSrv(ctx, rw, aukcRegInfRsErr)
+				return
+			}
+
+			c, brErr := passkey.FinishRegistration(&ctx, ssd.Logger, ssd.TntId, aukcRegInfRs[0].Policy(), user, sd, r)
+			if brErr != nil {
+				if passkey.Rejected(brErr) {
+					ssd.Logger.LogAttrs(ctx, slog.LevelDebug, "Post::authenticator rejected by the tenant's policy",
+						slog.String("error", brErr.Error()),
+					)
+
+					notification.ToastStatus(ctx, ssd.Logger, rw, r, "error" , &map[string]string{"Message" : data.T("web-core-auth-pky-aur-reg-form.error-input-pky-rejected")}, http.StatusUnprocessableEntity, data)
+					return
+				}
+
+				error.IntSrv(ctx, rw, brErr)
+				return
+			}
+
+			var t []string = make([]string, len(c.Transport))
+			for i, v := range c.Transport {
+				t[i] = string(v)
+			}
+
+			regErr := PostPky(
+				&ctx,
+				ssd.Logger,
+				ssd.Conn,
+				ssd.TntId,
+				data.User.AurId,
+				passkey.Name(c.Authenticator.AAGUID),
+				c.ID,
+				c.PublicKey,
+				c.AttestationType,
+				t,
+				c.Flags.UserPresent,
+				c.Flags.UserVerified,
+				c.Flags.BackupEligible,
+				c.Flags.BackupState,
+				c.Authenticator.AAGUID,
+				int(c.Authenticator.SignCount),
+				c.Authenticator.CloneWarning,
+				string(c.Authenticator.Attachment),
+				c.Attestation.ClientDataJSON,
+				c.Attestation.ClientDataHash,
+				c.Attestation.AuthenticatorData,
+				c.Attestation.PublicKeyAlgorithm,
+				c.Attestation.Object,
+				data.User.AurNm,
+				nil,
+			)
+			if regErr != nil {
+				error.IntSrv(ctx, rw, regErr)
+				return
+			}
+
+			rw.WriteHeader(http.StatusCreated)
+
+		default:
+			error.Status(ctx, rw, http.StatusNotFound)
+			return
+	}
+
+	ssd.Logger.LogAttrs(ctx, slog.LevelDebug, "Post::end")
+
+	return
+}
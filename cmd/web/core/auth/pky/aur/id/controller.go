@@ -0,0 +1,168 @@
+package id
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+import (
+	"github.com/andrewah64/base-app-client/internal/common/core/session"
+	"github.com/andrewah64/base-app-client/internal/web/core/error"
+	"github.com/andrewah64/base-app-client/internal/web/core/ui/data/form"
+	"github.com/andrewah64/base-app-client/internal/web/core/ui/data/page"
+	"github.com/andrewah64/base-app-client/internal/web/core/ui/html"
+	"github.com/andrewah64/base-app-client/internal/web/core/ui/notification"
+)
+
+import (
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+func Get(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	ssd, ok := session.FromContext(ctx)
+	if ! ok {
+		error.IntSrv(ctx, rw, fmt.Errorf("Get::get request info"))
+		return
+	}
+
+	ssd.Logger.LogAttrs(ctx, slog.LevelDebug, "Get::start")
+
+	data, ok := page.FromContext(ctx)
+	if ! ok {
+		error.IntSrv(ctx, rw, fmt.Errorf("Get::get request data"))
+		return
+	}
+
+	pkyId, pkyIdErr := strconv.Atoi(r.PathValue("id"))
+	if pkyIdErr != nil || pkyId < 1 {
+		error.Status(ctx, rw, http.StatusNotFound)
+		return
+	}
+
+	pkyRs, pkyRsErr := GetRowPkyMod(&ctx, ssd.Logger, ssd.Conn, ssd.TntId, data.User.AurId, pkyId)
+	if pkyRsErr != nil {
+		error.IntSrv(ctx, rw, pkyRsErr)
+		return
+	}
+
+	ssd.Logger.LogAttrs(ctx, slog.LevelDebug, "Get::retrieve datasets",
+		slog.Int("pkyId"      , pkyId),
+		slog.Int("len(pkyRs)" , len(pkyRs)),
+	)
+
+	data.ResultSet = &map[string]any{"Pky": &pkyRs}
+
+	html.Fragment(ctx, ssd.Logger, rw, r, "core/auth/pky/aur/fragment/modrow", http.StatusCreated, &data)
+
+	if len(pkyRs) == 0 {
+		notification.Toast(ctx, slog.Default(), rw, r, "error" , &map[string]string{"Message" : data.T("web-core-auth-pky-aur-mod-form.warning-input-pky-olock-error")}, data)
+	}
+
+	ssd.Logger.LogAttrs(ctx, slog.LevelDebug, "Get::end")
+
+	return
+}
+
+func Patch(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	ssd, ok := session.FromContext(ctx)
+	if ! ok {
+		error.IntSrv(ctx, rw, fmt.Errorf("Patch::get request info"))
+		return
+	}
+
+	ssd.Logger.LogAttrs(ctx, slog.LevelDebug, "Patch::start")
+
+	data, ok := page.FromContext(ctx)
+	if ! ok {
+		error.IntSrv(ctx, rw, fmt.Errorf("Patch::get request data"))
+		return
+	}
+
+	pkyId, pkyIdErr := strconv.Atoi(r.PathValue("id"))
+	if pkyIdErr != nil || pkyId < 1 {
+		error.Status(ctx, rw, http.StatusNotFound)
+		return
+	}
+
+	pfErr := r.ParseForm()
+	if pfErr != nil {
+		error.IntSrv(ctx, rw, pfErr)
+		return
+	}
+
+	pkyNm := form.VText(r, fmt.Sprintf("pky-aur-mod-pky-nm-%v" , pkyId))
+	uts   := form.VTime(r, fmt.Sprintf("pky-aur-mod-uts-%v"    , pkyId))
+
+	ssd.Logger.LogAttrs(ctx, slog.LevelDebug, "Patch::get data from form",
+		slog.Int   ("pkyId" , pkyId),
+		slog.String("pkyNm" , pkyNm),
+		slog.Any   ("uts"   , uts),
+	)
+
+	exptErrs := []string{
+		"OLOKU",
+		"OLOKD",
+		pgerrcode.UniqueViolation,
+		pgerrcode.CheckViolation,
+	}
+
+	patchErr := PatchPky(&ctx, ssd.Logger, ssd.Conn, ssd.TntId, data.User.AurId, pkyId, pkyNm, data.User.AurNm, uts, exptErrs)
+	if patchErr != nil{
+		Get(rw, r)
+
+		var pgErr *pgconn.PgError
+
+		if errors.As(patchErr, &pgErr) {
+			ssd.Logger.LogAttrs(ctx, slog.LevelDebug, "Patch: PatchPky params",
+				slog.Int   ("ssd.TntId" , ssd.TntId),
+				slog.Int   ("pkyId"     , pkyId),
+				slog.String("pkyNm"     , pkyNm),
+				slog.String("patchErr"  , patchErr.Error()),
+			)
+
+			switch pgErr.Code {
+				case "OLOKU":
+					notification.Toast(ctx, slog.Default(), rw, r, "error" , &map[string]string{"Message" : data.T("web-core-auth-pky-aur-mod-form.warning-input-pky-olock-error")}, data)
+
+				case "OLOKD":
+					// intentionally empty
+
+				case pgerrcode.CheckViolation:
+					notification.Toast(ctx, slog.Default(), rw, r, "error" , &map[string]string{"Message" : data.T("web-core-auth-pky-aur-mod-form.warning-input-pky-nm-blank")}, data)
+
+				case pgerrcode.UniqueViolation:
+					notification.Toast(ctx, slog.Default(), rw, r, "error" , &map[string]string{"Message" : data.T("web-core-auth-pky-aur-mod-form.warning-input-pky-nm-taken", "pkyNm", pkyNm)}, data)
+
+				default:
+					notification.Toast(ctx, slog.Default(), rw, r, "error" , &map[string]string{"Message" : data.T("web-core-auth-pky-aur-mod-form.warning-input-unexpected-error")}, data)
+
+			}
+
+			return
+		}
+	}
+
+	pkyRs, pkyRsErr := GetRowPkyInf(&ctx, ssd.Logger, ssd.Conn, ssd.TntId, data.User.AurId, pkyId)
+	if pkyRsErr != nil {
+		error.IntSrv(ctx, rw, pkyRsErr)
+		return
+	}
+
+	data.ResultSet = &map[string]any{"Pky": &pkyRs}
+
+	html.Fragment(ctx, ssd.Logger, rw, r, "core/auth/pky/aur/fragment/infrow", http.StatusCreated, &data)
+
+	notification.Toast(ctx, ssd.Logger, rw, r, "success", &map[string]string{"Message" : data.T("web-core-auth-pky-aur-mod-form.message-input-success")}, data)
+
+	ssd.Logger.LogAttrs(ctx, slog.LevelDebug, "Patch::end")
+
+	return
+}
@@ -0,0 +1,142 @@
+package id
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+import (
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+import (
+	"github.com/andrewah64/base-app-client/internal/common/core/db"
+	"github.com/andrewah64/base-app-client/internal/web/core/passkey"
+)
+
+type Inf struct {
+	PkyId             int
+	PkyNm             string
+	PkyAaguid         []byte
+	PkyBackupEligible bool
+	PkyBackupState    bool
+	PkySignCount      int
+	PkyLastUsedTs     *time.Time
+	Cts               time.Time
+}
+
+func (i Inf) AutNm() string {
+	return passkey.Name(i.PkyAaguid)
+}
+
+type Mod struct {
+	PkyId             int
+	PkyNm             string
+	PkyAaguid         []byte
+	PkyBackupEligible bool
+	PkyBackupState    bool
+	PkySignCount      int
+	PkyLastUsedTs     *time.Time
+	Cts               time.Time
+	Uts               time.Time
+}
+
+func (m Mod) AutNm() string {
+	return passkey.Name(m.PkyAaguid)
+}
+
+const (
+	dbSchema = "web_core_auth_pky_aur_mod"
+)
+
+func GetRowPkyInf(ctx *context.Context, logger *slog.Logger, conn *pgxpool.Conn, tntId int, aurId int, pkyId int) ([]Inf, error) {
+	const (
+		dbFunc = "row_pky_inf"
+	)
+
+	rs, rErr := db.DataSet[Inf](ctx, logger, conn,
+		func(ctx *context.Context, tx *pgx.Tx)(string, string, *pgx.Rows, error){
+			qry := fmt.Sprintf("select %v.%v($1, $2, $3, $4)", dbSchema, dbFunc)
+
+			c, cErr := (*tx).Query(*ctx, qry, dbFunc, tntId, aurId, pkyId)
+			if cErr != nil {
+				slog.LogAttrs(*ctx, slog.LevelError, "get dataset",
+					slog.String("error" , cErr.Error()),
+					slog.String("qry"   , qry),
+					slog.Int   ("tntId" , tntId),
+					slog.Int   ("aurId" , aurId),
+					slog.Int   ("pkyId" , pkyId),
+				)
+
+				return qry, dbFunc, nil, fmt.Errorf("call database function: %w", cErr)
+			}
+
+			return qry, dbFunc, &c, nil
+		})
+
+	return rs, rErr
+}
+
+func GetRowPkyMod(ctx *context.Context, logger *slog.Logger, conn *pgxpool.Conn, tntId int, aurId int, pkyId int) ([]Mod, error) {
+	const (
+		dbFunc = "row_pky_mod"
+	)
+
+	rs, rErr := db.DataSet[Mod](ctx, logger, conn,
+		func(ctx *context.Context, tx *pgx.Tx)(string, string, *pgx.Rows, error){
+			qry := fmt.Sprintf("select %v.%v($1, $2, $3, $4)", dbSchema, dbFunc)
+
+			c, cErr := (*tx).Query(*ctx, qry, dbFunc, tntId, aurId, pkyId)
+			if cErr != nil {
+				slog.LogAttrs(*ctx, slog.LevelError, "get dataset",
+					slog.String("error" , cErr.Error()),
+					slog.String("qry"   , qry),
+					slog.Int   ("tntId" , tntId),
+					slog.Int   ("aurId" , aurId),
+					slog.Int   ("pkyId" , pkyId),
+				)
+
+				return qry, dbFunc, nil, fmt.Errorf("call database function: %w", cErr)
+			}
+
+			return qry, dbFunc, &c, nil
+		})
+
+	return rs, rErr
+}
+
+func PatchPky (ctx *context.Context, logger *slog.Logger, conn *pgxpool.Conn, tntId int, aurId int, pkyId int, pkyNm string, by string, uts time.Time, exptErrs []string) error {
+	var (
+		sprocCall   = fmt.Sprintf("call %v.row_mod_pky(@p_tnt_id, @p_aur_id, @p_pky_id, @p_pky_nm, @p_by, @p_uts)", dbSchema)
+		sprocParams = pgx.NamedArgs{
+			"p_tnt_id" : tntId,
+			"p_aur_id" : aurId,
+			"p_pky_id" : pkyId,
+			"p_pky_nm" : pkyNm,
+			"p_by"     : by,
+			"p_uts"    : uts,
+		}
+	)
+
+	sprocErr := db.Sproc(ctx, logger, conn, sprocCall, sprocParams, exptErrs)
+	if sprocErr != nil {
+		logger.LogAttrs(*ctx, slog.LevelDebug, "call sproc",
+			slog.String("sprocCall" , sprocCall),
+			slog.String("error"     , sprocErr.Error()),
+			slog.Int   ("tntId"     , tntId),
+			slog.Int   ("aurId"     , aurId),
+			slog.Int   ("pkyId"     , pkyId),
+			slog.String("pkyNm"     , pkyNm),
+			slog.String("by"        , by),
+			slog.Any   ("uts"       , uts),
+			slog.Any   ("exptErrs"  , exptErrs),
+		)
+
+		return sprocErr
+	}
+
+	return nil
+}
@@ -0,0 +1,284 @@
+package aur
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+import (
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jackc/pgx/v5"
+)
+
+import (
+	"github.com/andrewah64/base-app-client/internal/common/core/db"
+	"github.com/andrewah64/base-app-client/internal/web/core/passkey"
+)
+
+const (
+	dbSchema = "web_core_auth_pky_aur_mod"
+)
+
+type Inf struct {
+	PkyId             int
+	PkyNm             string
+	PkyAaguid         []byte
+	PkyBackupEligible bool
+	PkyBackupState    bool
+	PkySignCount      int
+	PkyLastUsedTs     *time.Time
+	Cts               time.Time
+}
+
+// AutNm is the name of the make and model of authenticator the passkey is
+// kept on, found from its AAGUID.
+func (i Inf) AutNm() string {
+	return passkey.Name(i.PkyAaguid)
+}
+
+func GetPky (ctx *context.Context, logger *slog.Logger, conn *pgxpool.Conn, tntId int, aurId int) ([]Inf, error) {
+	const (
+		dbFunc = "pky_inf"
+	)
+
+	rs, rErr := db.DataSet[Inf](ctx, logger, conn,
+		func(ctx *context.Context, tx *pgx.Tx)(string, string, *pgx.Rows, error){
+			qry := fmt.Sprintf("select %v.%v($1, $2, $3)", dbSchema, dbFunc)
+
+			c, cErr := (*tx).Query(*ctx, qry, dbFunc, tntId, aurId)
+			if cErr != nil {
+				slog.LogAttrs(*ctx, slog.LevelError, "get dataset",
+					slog.String("error" , cErr.Error()),
+					slog.String("qry"   , qry),
+					slog.Int   ("tntId" , tntId),
+					slog.Int   ("aurId" , aurId),
+				)
+
+				return qry, dbFunc, nil, fmt.Errorf("call database function: %w", cErr)
+			}
+
+			return qry, dbFunc, &c, nil
+		})
+
+	return rs, rErr
+}
+
+type AukcRegInf struct {
+	PkaNm   string
+	PktNm   string
+	PdcNm   string
+	PuvNm   string
+	PkgCd []int
+	PkhNm []string
+	PapNm   string
+	PalNm   string
+	Aaguid []string
+}
+
+// Policy is the tenant's rules for which authenticators a passkey may be
+// registered on.
+func (a AukcRegInf) Policy() passkey.Policy {
+	return passkey.Policy{
+		PapNm  : a.PapNm,
+		PalNm  : a.PalNm,
+		Aaguid : a.Aaguid,
+	}
+}
+
+func GetAukcRegInf (ctx *context.Context, logger *slog.Logger, conn *pgxpool.Conn, tntId int) ([]AukcRegInf, error) {
+	const (
+		dbFunc = "aukc_reg_inf"
+	)
+
+	rs, rErr := db.DataSet[AukcRegInf](ctx, logger, conn,
+		func(ctx *context.Context, tx *pgx.Tx)(string, string, *pgx.Rows, error){
+			qry := fmt.Sprintf("select %v.%v($1, $2)", dbSchema, dbFunc)
+
+			c, cErr := (*tx).Query(*ctx, qry, dbFunc, tntId)
+			if cErr != nil {
+				slog.LogAttrs(*ctx, slog.LevelError, "get dataset",
+					slog.String("error" , cErr.Error()),
+					slog.String("qry"   , qry),
+					slog.Int   ("tntId" , tntId),
+				)
+
+				return qry, dbFunc, nil, fmt.Errorf("call database function: %w", cErr)
+			}
+
+			return qry, dbFunc, &c, nil
+		})
+
+	return rs, rErr
+}
+
+// ExcInf is a passkey the user already has, which their authenticator is
+// asked not to register a second time.
+type ExcInf struct {
+	PkyCredentialId           []byte
+	PkyAuthenticatorTransport []string
+}
+
+func GetExcInf (ctx *context.Context, logger *slog.Logger, conn *pgxpool.Conn, tntId int, aurId int) ([]ExcInf, error) {
+	const (
+		dbFunc = "exc_inf"
+	)
+
+	rs, rErr := db.DataSet[ExcInf](ctx, logger, conn,
+		func(ctx *context.Context, tx *pgx.Tx)(string, string, *pgx.Rows, error){
+			qry := fmt.Sprintf("select %v.%v($1, $2, $3)", dbSchema, dbFunc)
+
+			c, cErr := (*tx).Query(*ctx, qry, dbFunc, tntId, aurId)
+			if cErr != nil {
+				slog.LogAttrs(*ctx, slog.LevelError, "get dataset",
+					slog.String("error" , cErr.Error()),
+					slog.String("qry"   , qry),
+					slog.Int   ("tntId" , tntId),
+					slog.Int   ("aurId" , aurId),
+				)
+
+				return qry, dbFunc, nil, fmt.Errorf("call database function: %w", cErr)
+			}
+
+			return qry, dbFunc, &c, nil
+		})
+
+	return rs, rErr
+}
+
+type PrsInf struct {
+	PrsJs []byte
+}
+
+func GetPrsInf (ctx *context.Context, logger *slog.Logger, conn *pgxpool.Conn, tntId int, aurId int) ([]PrsInf, error) {
+	const (
+		dbFunc = "prs_inf"
+	)
+
+	rs, rErr := db.DataSet[PrsInf](ctx, logger, conn,
+		func(ctx *context.Context, tx *pgx.Tx)(string, string, *pgx.Rows, error){
+			qry := fmt.Sprintf("select %v.%v($1, $2, $3)", dbSchema, dbFunc)
+
+			c, cErr := (*tx).Query(*ctx, qry, dbFunc, tntId, aurId)
+			if cErr != nil {
+				slog.LogAttrs(*ctx, slog.LevelError, "get dataset",
+					slog.String("error" , cErr.Error()),
+					slog.String("qry"   , qry),
+					slog.Int   ("tntId" , tntId),
+					slog.Int   ("aurId" , aurId),
+				)
+
+				return qry, dbFunc, nil, fmt.Errorf("call database function: %w", cErr)
+			}
+
+			return qry, dbFunc, &c, nil
+		})
+
+	return rs, rErr
+}
+
+func PostPrs (ctx *context.Context, logger *slog.Logger, conn *pgxpool.Conn, tntId int, aurId int, prsJs []byte, exptErrs []string) error {
+	var (
+		sprocCall   = fmt.Sprintf("call %v.reg_prs(@p_tnt_id, @p_aur_id, @p_prs_js)", dbSchema)
+		sprocParams = pgx.NamedArgs{
+			"p_tnt_id" : tntId,
+			"p_aur_id" : aurId,
+			"p_prs_js" : prsJs,
+		}
+	)
+
+	sprocErr := db.Sproc(ctx, logger, conn, sprocCall, sprocParams, exptErrs)
+	if sprocErr != nil {
+		logger.LogAttrs(*ctx, slog.LevelDebug, "call sproc",
+			slog.String("sprocCall" , sprocCall),
+			slog.String("error"     , sprocErr.Error()),
+			slog.Int   ("tntId"     , tntId),
+			slog.Int   ("aurId"     , aurId),
+			slog.Any   ("prsJs"     , prsJs),
+			slog.Any   ("exptErrs"  , exptErrs),
+		)
+
+		return sprocErr
+	}
+
+	return nil
+}
+
+func PostPky (ctx *context.Context, logger *slog.Logger, conn *pgxpool.Conn, tntId int, aurId int, pkyNm string, pkyCredentialId []byte, pkyPublicKey []byte, pkyAttestationType string, pkyAuthenticatorTransport []string, pkyUserPresent bool, pkyUserVerified bool, pkyBackupEligible bool, pkyBackupState bool, pkyAaguid []byte, pkySignCount int, pkyCloneWarning bool, pkyAttachment string, pkyClientDataJson []byte, pkyClientDataHash []byte, pkyAuthenticatorData []byte, pkyPublicKeyAlgorithm int64, pkyObject []byte, by string, exptErrs []string) error {
+	var (
+		sprocCall   = fmt.Sprintf("call %v.reg_pky(@p_tnt_id, @p_aur_id, @p_pky_nm, @p_pky_credential_id, @p_pky_public_key, @p_pky_attestation_type, @p_pky_authenticator_transport, @p_pky_user_present, @p_pky_user_verified, @p_pky_backup_eligible, @p_pky_backup_state, @p_pky_aaguid, @p_pky_sign_count, @p_pky_clone_warning, @p_pky_attachment, @p_pky_client_data_json, @p_pky_client_data_hash, @p_pky_authenticator_data, @p_pky_public_key_algorithm, @p_pky_object, @p_by)", dbSchema)
+		sprocParams = pgx.NamedArgs{
+			"p_tnt_id"                      : tntId,
+			"p_aur_id"                      : aurId,
+			"p_pky_nm"                      : pkyNm,
+			"p_pky_credential_id"           : pkyCredentialId,
+			"p_pky_public_key"              : pkyPublicKey,
+			"p_pky_attestation_type"        : pkyAttestationType,
+			"p_pky_authenticator_transport" : pkyAuthenticatorTransport,
+			"p_pky_user_present"            : pkyUserPresent,
+			"p_pky_user_verified"           : pkyUserVerified,
+			"p_pky_backup_eligible"         : pkyBackupEligible,
+			"p_pky_backup_state"            : pkyBackupState,
+			"p_pky_aaguid"                  : pkyAaguid,
+			"p_pky_sign_count"              : pkySignCount,
+			"p_pky_clone_warning"           : pkyCloneWarning,
+			"p_pky_attachment"              : pkyAttachment,
+			"p_pky_client_data_json"        : pkyClientDataJson,
+			"p_pky_client_data_hash"        : pkyClientDataHash,
+			"p_pky_authenticator_data"      : pkyAuthenticatorData,
+			"p_pky_public_key_algorithm"    : pkyPublicKeyAlgorithm,
+			"p_pky_object"                  : pkyObject,
+			"p_by"                          : by,
+		}
+	)
+
+	sprocErr := db.Sproc(ctx, logger, conn, sprocCall, sprocParams, exptErrs)
+	if sprocErr != nil {
+		logger.LogAttrs(*ctx, slog.LevelDebug, "call sproc",
+			slog.String("sprocCall"          , sprocCall),
+			slog.String("error"              , sprocErr.Error()),
+			slog.Int   ("tntId"              , tntId),
+			slog.Int   ("aurId"              , aurId),
+			slog.String("pkyNm"              , pkyNm),
+			slog.Any   ("pkyCredentialId"    , pkyCredentialId),
+			slog.String("pkyAttestationType" , pkyAttestationType),
+			slog.Any   ("pkyAaguid"          , pkyAaguid),
+			slog.String("by"                 , by),
+			slog.Any   ("exptErrs"           , exptErrs),
+		)
+
+		return sprocErr
+	}
+
+	return nil
+}
+
+// DelPky deletes the user's passkeys. PKYLS is raised when that would leave
+// them without a password or any passkey to sign in with.
+func DelPky (ctx *context.Context, logger *slog.Logger, conn *pgxpool.Conn, tntId int, aurId int, pkyId []int, exptErrs []string) error {
+	var (
+		sprocCall   = fmt.Sprintf("call %v.del_pky(@p_tnt_id, @p_aur_id, @p_pky_id)", dbSchema)
+		sprocParams = pgx.NamedArgs{
+			"p_tnt_id" : tntId,
+			"p_aur_id" : aurId,
+			"p_pky_id" : pkyId,
+		}
+	)
+
+	sprocErr := db.Sproc(ctx, logger, conn, sprocCall, sprocParams, exptErrs)
+	if sprocErr != nil {
+		logger.LogAttrs(*ctx, slog.LevelDebug, "call sproc",
+			slog.String("sprocCall" , sprocCall),
+			slog.String("error"     , sprocErr.Error()),
+			slog.Int   ("tntId"     , tntId),
+			slog.Int   ("aurId"     , aurId),
+			slog.Any   ("pkyId"     , pkyId),
+			slog.Any   ("exptErrs"  , exptErrs),
+		)
+
+		return sprocErr
+	}
+
+	return nil
+}
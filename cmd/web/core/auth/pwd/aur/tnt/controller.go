@@ -40,7 +40,7 @@ func Get (rw http.ResponseWriter, r *http.Request) {
 
 	aurId, aurIdErr := strconv.Atoi(r.PathValue("id"))
 	if aurIdErr != nil || aurId < 1 {
-		http.NotFound(rw, r)
+		error.Status(ctx, rw, http.StatusNotFound)
 		return
 	}
 
@@ -81,7 +81,7 @@ func Patch(rw http.ResponseWriter, r *http.Request) {
 
 	aurId, aurIdErr := strconv.Atoi(r.PathValue("id"))
 	if aurIdErr != nil || aurId < 1 {
-		http.NotFound(rw, r)
+		error.Status(ctx, rw, http.StatusNotFound)
 		return
 	}
 
@@ -97,32 +97,57 @@ func Patch(rw http.ResponseWriter, r *http.Request) {
 		slog.Int("aurId" , aurId),
 	)
 
-	aurHshPw, aurHshPwErr := password.Hash(aurPwd)
-	if aurHshPwErr != nil {
-		error.IntSrv(ctx, rw, aurHshPwErr)
-		return
-	}
-
 	valInfRs, valInfRsErr := val.GetInf(&ctx, ssd.Logger, ssd.Conn, ssd.TntId)
 	if valInfRsErr != nil {
 		error.IntSrv(ctx, rw, valInfRsErr)
 		return
 	}
 
-	lenPass, symPass, numPass := password.Validate(aurPwd, valInfRs[0].AupcAurPwdMinLen, valInfRs[0].AupcAurPwdMaxLen, valInfRs[0].AupcAurPwdIncSym, valInfRs[0].AupcAurPwdIncNum)
+	hstRs, hstRsErr := GetHstInf(&ctx, ssd.Logger, ssd.Conn, ssd.TntId, aurId)
+	if hstRsErr != nil {
+		error.IntSrv(ctx, rw, hstRsErr)
+		return
+	}
+
+	hsts := make([]string, len(hstRs))
+
+	for i, hst := range hstRs {
+		hsts[i] = hst.AurHshPw
+	}
+
+	vrls := val.Policy(valInfRs[0]).Check(ctx, aurPwd, hsts)
+
+	if len(vrls) > 0 {
+		msgs := make([]string, len(vrls))
 
-	if lenPass && symPass && numPass {
-		patchErr := PatchPwd(&ctx, ssd.Logger, ssd.Conn, ssd.TntId, aurId, aurHshPw, data.User.AurNm, nil)
-		if patchErr != nil{
-			error.IntSrv(ctx, rw, patchErr)
-			return
+		for i, vrl := range vrls {
+			msgs[i] = data.T("web-core-auth-pwd-aur-tnt-mod-form.error-input-aur-pwd-" + vrl)
 		}
 
-		notification.Toast(ctx, ssd.Logger, rw, r, "success" , &map[string]string{"Message" : data.T("web-core-auth-pwd-aur-tnt-mod-form.message-success")}, data)
-	} else {
-		notification.Toast(ctx, ssd.Logger, rw, r, "error"   , &map[string]string{"Message" : data.T("web-core-auth-pwd-aur-tnt-mod-form.message-error")}, data)
+		notification.Vrl(ctx, ssd.Logger, rw, r,
+			data.T("web-core-auth-pwd-aur-tnt-mod-form.message-error"),
+			data.T("web-core-auth-pwd-aur-tnt-mod-form.title-warning-singular", "n", strconv.Itoa(len(msgs))),
+			data.T("web-core-auth-pwd-aur-tnt-mod-form.title-warning-plural"  , "n", strconv.Itoa(len(msgs))),
+			&msgs,
+			data,
+		)
+
+		return
+	}
+
+	aurHshPw, aurHshPwErr := password.Hash(aurPwd)
+	if aurHshPwErr != nil {
+		error.IntSrv(ctx, rw, aurHshPwErr)
+		return
+	}
+
+	patchErr := PatchPwd(&ctx, ssd.Logger, ssd.Conn, ssd.TntId, aurId, aurHshPw, data.User.AurNm, nil)
+	if patchErr != nil{
+		error.IntSrv(ctx, rw, patchErr)
+		return
 	}
 
-	
+	notification.Toast(ctx, ssd.Logger, rw, r, "success" , &map[string]string{"Message" : data.T("web-core-auth-pwd-aur-tnt-mod-form.message-success")}, data)
+
 	ssd.Logger.LogAttrs(ctx, slog.LevelDebug, "Patch::end")
 }
@@ -20,6 +20,9 @@ type PwdAurInf struct {
 	AupcAurPwdMaxLen int
 	AupcAurPwdIncSym bool
 	AupcAurPwdIncNum bool
+	AupcAurPwdIncUpr bool
+	AupcAurPwdIncLwr bool
+	AupcAurPwdSymSet string
 	AurId            int
 	AurNm            string
 }
@@ -48,6 +51,36 @@ func GetPwdAurInf (ctx *context.Context, logger *slog.Logger, conn *pgxpool.Conn
 	return rs, rErr
 }
 
+type HstInf struct {
+	AurHshPw string
+}
+
+// GetHstInf returns the hashes of the passwords the user may not reuse, as
+// many as the tenant's policy keeps.
+func GetHstInf (ctx *context.Context, logger *slog.Logger, conn *pgxpool.Conn, tntId int, aurId int) ([]HstInf, error) {
+	rs, rErr := db.DataSet[HstInf](ctx, logger, conn,
+		func(ctx *context.Context, tx *pgx.Tx)(string, string, *pgx.Rows, error){
+			dbFunc := "hst_inf"
+			qry    := fmt.Sprintf("select web_core_auth_pwd_aur_tnt_mod.%v($1, $2, $3)", dbFunc)
+
+			c, cErr := (*tx).Query(*ctx, qry, dbFunc, tntId, aurId)
+			if cErr != nil {
+				slog.LogAttrs(*ctx, slog.LevelError, "get dataset",
+					slog.String("error" , cErr.Error()),
+					slog.String("qry"   , qry),
+					slog.Int   ("tntId" , tntId),
+					slog.Int   ("aurId" , aurId),
+				)
+
+				return qry, dbFunc, nil, fmt.Errorf("call database function: %w", cErr)
+			}
+
+			return qry, dbFunc, &c, nil
+		})
+
+	return rs, rErr
+}
+
 func PatchPwd (ctx *context.Context, logger *slog.Logger, conn *pgxpool.Conn, tntId int, aurId int, aurHshPw string, by string, exptErrs []string) error {
 	var (
 		sprocCall   = "call web_core_auth_pwd_aur_tnt_mod.mod_pwd(@p_tnt_id, @p_aur_id, @p_aur_hsh_pw, @p_by)"
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"log/slog"
 	"net/http"
+	"slices"
 )
 
 import (
@@ -46,9 +47,15 @@ func Get(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	lenPass, symPass, numPass := password.Validate(aurPwd, infRs[0].AupcAurPwdMinLen, infRs[0].AupcAurPwdMaxLen, infRs[0].AupcAurPwdIncSym, infRs[0].AupcAurPwdIncNum)
+	vrls := Policy(infRs[0]).Check(ctx, aurPwd, nil)
 
-	data.ResultSet = &map[string]any{"LenPass" : lenPass, "SymPass" : symPass, "NumPass" : numPass}
+	data.ResultSet = &map[string]any{
+		"LenPass" : ! slices.Contains(vrls, password.VrlLen),
+		"UprPass" : ! slices.Contains(vrls, password.VrlUpr),
+		"LwrPass" : ! slices.Contains(vrls, password.VrlLwr),
+		"NumPass" : ! slices.Contains(vrls, password.VrlNum),
+		"SymPass" : ! slices.Contains(vrls, password.VrlSym),
+	}
 
 	html.Fragment(ctx, ssd.Logger, rw, r, "core/auth/pwd/aur/tnt/fragment/val", http.StatusOK, &data)
 
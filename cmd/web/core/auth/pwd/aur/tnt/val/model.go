@@ -13,6 +13,7 @@ import (
 
 import (
 	"github.com/andrewah64/base-app-client/internal/common/core/db"
+	"github.com/andrewah64/base-app-client/internal/common/core/password"
 )
 
 type Inf struct {
@@ -20,6 +21,10 @@ type Inf struct {
 	AupcAurPwdMaxLen int
 	AupcAurPwdIncSym bool
 	AupcAurPwdIncNum bool
+	AupcAurPwdIncUpr bool
+	AupcAurPwdIncLwr bool
+	AupcAurPwdSymSet string
+	AupcAurPwdBrc    bool
 }
 
 func GetInf (ctx *context.Context, logger *slog.Logger, conn *pgxpool.Conn, tntId int) ([]Inf, error) {
@@ -44,3 +49,17 @@ func GetInf (ctx *context.Context, logger *slog.Logger, conn *pgxpool.Conn, tntI
 
 	return rs, rErr
 }
+
+// Policy returns the tenant's password policy.
+func Policy (inf Inf) password.Policy {
+	return password.Policy{
+		MinLen : inf.AupcAurPwdMinLen,
+		MaxLen : inf.AupcAurPwdMaxLen,
+		IncUpr : inf.AupcAurPwdIncUpr,
+		IncLwr : inf.AupcAurPwdIncLwr,
+		IncNum : inf.AupcAurPwdIncNum,
+		IncSym : inf.AupcAurPwdIncSym,
+		SymSet : inf.AupcAurPwdSymSet,
+		Brc    : inf.AupcAurPwdBrc,
+	}
+}
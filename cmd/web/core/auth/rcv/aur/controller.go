@@ -0,0 +1,166 @@
+package aur
+
+import (
+	"encoding/base32"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+)
+
+import (
+	"github.com/andrewah64/base-app-client/internal/common/core/session"
+	"github.com/andrewah64/base-app-client/internal/web/core/error"
+	"github.com/andrewah64/base-app-client/internal/web/core/mfa"
+	"github.com/andrewah64/base-app-client/internal/web/core/recovery"
+	"github.com/andrewah64/base-app-client/internal/web/core/ui/data/form"
+	"github.com/andrewah64/base-app-client/internal/web/core/ui/data/page"
+	"github.com/andrewah64/base-app-client/internal/web/core/ui/html"
+	"github.com/andrewah64/base-app-client/internal/web/core/ui/notification"
+)
+
+import (
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+func Get(rw http.ResponseWriter, r *http.Request){
+	ctx := r.Context()
+
+	ssd, ok := session.FromContext(ctx)
+	if ! ok {
+		error.IntSrv(ctx, rw, fmt.Errorf("Get::get request info"))
+		return
+	}
+
+	ssd.Logger.LogAttrs(ctx, slog.LevelDebug, "Get::start")
+
+	data, ok := page.FromContext(ctx)
+	if ! ok {
+		error.IntSrv(ctx, rw, fmt.Errorf("Get::get request data"))
+		return
+	}
+
+	infRs, infRsErr := GetInf(&ctx, ssd.Logger, ssd.Conn, ssd.TntId, data.User.AurId)
+	if infRsErr != nil {
+		error.IntSrv(ctx, rw, infRsErr)
+		return
+	}
+
+	ssd.Logger.LogAttrs(ctx, slog.LevelDebug, "Get::retrieve datasets",
+		slog.Int("len(infRs)" , len(infRs)),
+	)
+
+	data.ResultSet = &map[string]any{"Inf" : &infRs}
+
+	html.Tmpl(ctx, ssd.Logger, rw, r, "core/auth/rcv/aur/content", http.StatusOK, &data)
+
+	ssd.Logger.LogAttrs(ctx, slog.LevelDebug, "Get::end")
+}
+
+func Post(rw http.ResponseWriter, r *http.Request){
+	ctx := r.Context()
+
+	ssd, ok := session.FromContext(ctx)
+	if ! ok {
+		error.IntSrv(ctx, rw, fmt.Errorf("Post::get request info"))
+		return
+	}
+
+	ssd.Logger.LogAttrs(ctx, slog.LevelDebug, "Post::start")
+
+	data, ok := page.FromContext(ctx)
+	if ! ok {
+		error.IntSrv(ctx, rw, fmt.Errorf("Post::get request data"))
+		return
+	}
+
+	pfErr := r.ParseForm()
+	if pfErr != nil {
+		error.IntSrv(ctx, rw, pfErr)
+		return
+	}
+
+	otpCd := form.VText(r, "rcv-aur-reg-otp-cd")
+
+	infRs, infRsErr := GetInf(&ctx, ssd.Logger, ssd.Conn, ssd.TntId, data.User.AurId)
+	if infRsErr != nil {
+		error.IntSrv(ctx, rw, infRsErr)
+		return
+	}
+
+	if len(infRs) != 1 || ! infRs[0].OtpEnabled {
+		notification.Toast(ctx, ssd.Logger, rw, r, "error" , &map[string]string{"Message" : data.T("web-core-auth-rcv-aur-reg-form.warning-otp-disabled")}, data)
+
+		return
+	}
+
+	// a session alone isn't enough to replace the codes, as whoever has it
+	// could then sign in as the user without their authenticator app
+	otpRs, otpRsErr := GetOtpInf(&ctx, ssd.Logger, ssd.Conn, ssd.TntId, data.User.AurId)
+	if otpRsErr != nil {
+		error.IntSrv(ctx, rw, otpRsErr)
+		return
+	}
+
+	if len(otpRs) != 1 {
+		error.IntSrv(ctx, rw, fmt.Errorf("Post::received %v records", len(otpRs)))
+		return
+	}
+
+	otpSecret := base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString([]byte(otpRs[0].OtpSecret))
+
+	otpOpts := mfa.Opts{
+		Skew   : otpRs[0].AupcOtpSkew,
+		Digits : otpRs[0].OtpDigits,
+		Alg    : otpRs[0].OtpAlg,
+	}
+
+	otpStp, otpOk := mfa.Validate(otpCd, otpSecret, otpOpts, otpRs[0].OtpStp)
+
+	if otpOk {
+		// another request may have used the same code since it was read
+		exptErrs := []string{
+			"OTPRP",
+		}
+
+		patchErr := PatchOtpStp(&ctx, ssd.Logger, ssd.Conn, ssd.TntId, data.User.AurId, otpStp, exptErrs)
+		if patchErr != nil {
+			var pgErr *pgconn.PgError
+
+			if ! errors.As(patchErr, &pgErr) || pgErr.Code != "OTPRP" {
+				error.IntSrv(ctx, rw, patchErr)
+				return
+			}
+		}
+
+		otpOk = patchErr == nil
+	}
+
+	if ! otpOk {
+		ssd.Logger.LogAttrs(ctx, slog.LevelDebug, "Post::otp is invalid",
+			slog.Int("data.User.AurId", data.User.AurId),
+		)
+
+		notification.Toast(ctx, ssd.Logger, rw, r, "error" , &map[string]string{"Message" : data.T("web-core-auth-rcv-aur-reg-form.error-input-otp-cd")}, data)
+
+		return
+	}
+
+	rcvCds, rcvHshs, rcvErr := recovery.Codes()
+	if rcvErr != nil {
+		error.IntSrv(ctx, rw, rcvErr)
+		return
+	}
+
+	regErr := PostRcv(&ctx, ssd.Logger, ssd.Conn, ssd.TntId, data.User.AurId, rcvHshs, data.User.AurNm, nil)
+	if regErr != nil {
+		error.IntSrv(ctx, rw, regErr)
+		return
+	}
+
+	data.ResultSet = &map[string]any{"RcvCds" : &rcvCds}
+
+	html.Fragment(ctx, ssd.Logger, rw, r, "core/auth/rcv/aur/fragment/res", http.StatusCreated, &data)
+
+	ssd.Logger.LogAttrs(ctx, slog.LevelDebug, "Post::end")
+}
@@ -0,0 +1,151 @@
+package aur
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+)
+
+import (
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+import (
+	"github.com/andrewah64/base-app-client/internal/common/core/db"
+)
+
+const (
+	dbSchema = "web_core_auth_rcv_aur_mod"
+)
+
+type Inf struct {
+	OtpEnabled bool
+	RcvCnt     int
+}
+
+// GetInf returns whether the user has set up TOTP and how many of their
+// recovery codes are left.
+func GetInf (ctx *context.Context, logger *slog.Logger, conn *pgxpool.Conn, tntId int, aurId int) ([]Inf, error) {
+	const (
+		dbFunc = "rcv_inf"
+	)
+
+	rs, rErr := db.DataSet[Inf](ctx, logger, conn,
+		func(ctx *context.Context, tx *pgx.Tx)(string, string, *pgx.Rows, error){
+			qry := fmt.Sprintf("select %v.%v($1, $2, $3)", dbSchema, dbFunc)
+
+			c, cErr := (*tx).Query(*ctx, qry, dbFunc, tntId, aurId)
+			if cErr != nil {
+				slog.LogAttrs(*ctx, slog.LevelError, "get dataset",
+					slog.String("error" , cErr.Error()),
+					slog.String("qry"   , qry),
+					slog.Int   ("tntId" , tntId),
+					slog.Int   ("aurId" , aurId),
+				)
+
+				return qry, dbFunc, nil, fmt.Errorf("call database function: %w", cErr)
+			}
+
+			return qry, dbFunc, &c, nil
+		})
+
+	return rs, rErr
+}
+
+type OtpInf struct {
+	OtpSecret   string
+	OtpStp      int64
+	OtpDigits   int
+	OtpAlg      string
+	AupcOtpSkew int
+}
+
+// GetOtpInf returns the user's TOTP secret and settings, which the code they
+// confirm new recovery codes with is checked against.
+func GetOtpInf (ctx *context.Context, logger *slog.Logger, conn *pgxpool.Conn, tntId int, aurId int) ([]OtpInf, error) {
+	const (
+		dbFunc = "otp_inf"
+	)
+
+	rs, rErr := db.DataSet[OtpInf](ctx, logger, conn,
+		func(ctx *context.Context, tx *pgx.Tx)(string, string, *pgx.Rows, error){
+			qry := fmt.Sprintf("select %v.%v($1, $2, $3)", dbSchema, dbFunc)
+
+			c, cErr := (*tx).Query(*ctx, qry, dbFunc, tntId, aurId)
+			if cErr != nil {
+				slog.LogAttrs(*ctx, slog.LevelError, "get dataset",
+					slog.String("error" , cErr.Error()),
+					slog.String("qry"   , qry),
+					slog.Int   ("tntId" , tntId),
+					slog.Int   ("aurId" , aurId),
+				)
+
+				return qry, dbFunc, nil, fmt.Errorf("call database function: %w", cErr)
+			}
+
+			return qry, dbFunc, &c, nil
+		})
+
+	return rs, rErr
+}
+
+// PatchOtpStp records otpStp as the time step of the last code the user
+// confirmed with. OTPRP is raised if a code for that step, or a later one,
+// has been used since it was checked.
+func PatchOtpStp (ctx *context.Context, logger *slog.Logger, conn *pgxpool.Conn, tntId int, aurId int, otpStp int64, exptErrs []string) error {
+	var (
+		sprocCall   = fmt.Sprintf("call %v.mod_otp_stp(@p_tnt_id, @p_aur_id, @p_otp_stp)", dbSchema)
+		sprocParams = pgx.NamedArgs{
+			"p_tnt_id"  : tntId,
+			"p_aur_id"  : aurId,
+			"p_otp_stp" : otpStp,
+		}
+	)
+
+	sprocErr := db.Sproc(ctx, logger, conn, sprocCall, sprocParams, exptErrs)
+	if sprocErr != nil {
+		logger.LogAttrs(*ctx, slog.LevelDebug, "call sproc",
+			slog.String("sprocCall" , sprocCall),
+			slog.String("error"     , sprocErr.Error()),
+			slog.Int   ("tntId"     , tntId),
+			slog.Int   ("aurId"     , aurId),
+			slog.Int64 ("otpStp"    , otpStp),
+			slog.Any   ("exptErrs"  , exptErrs),
+		)
+
+		return sprocErr
+	}
+
+	return nil
+}
+
+// PostRcv replaces the user's recovery codes with the ones whose hashes are
+// rcvHshs.
+func PostRcv (ctx *context.Context, logger *slog.Logger, conn *pgxpool.Conn, tntId int, aurId int, rcvHshs []string, by string, exptErrs []string) error {
+	var (
+		sprocCall   = fmt.Sprintf("call %v.reg_rcv(@p_tnt_id, @p_aur_id, @p_rcv_hsh, @p_by)", dbSchema)
+		sprocParams = pgx.NamedArgs{
+			"p_tnt_id"  : tntId,
+			"p_aur_id"  : aurId,
+			"p_rcv_hsh" : rcvHshs,
+			"p_by"      : by,
+		}
+	)
+
+	sprocErr := db.Sproc(ctx, logger, conn, sprocCall, sprocParams, exptErrs)
+	if sprocErr != nil {
+		logger.LogAttrs(*ctx, slog.LevelDebug, "call sproc",
+			slog.String("sprocCall" , sprocCall),
+			slog.String("error"     , sprocErr.Error()),
+			slog.Int   ("tntId"     , tntId),
+			slog.Int   ("aurId"     , aurId),
+			slog.String("by"        , by),
+			slog.Any   ("exptErrs"  , exptErrs),
+		)
+
+		return sprocErr
+	}
+
+	return nil
+}
@@ -8,12 +8,13 @@ import (
 )
 
 import (
-	"github.com/andrewah64/base-app-client/internal/common/core/session"
-	"github.com/andrewah64/base-app-client/internal/web/core/error"
-	"github.com/andrewah64/base-app-client/internal/web/core/ui/data/form"
-	"github.com/andrewah64/base-app-client/internal/web/core/ui/data/page"
-	"github.com/andrewah64/base-app-client/internal/web/core/ui/html"
-	"github.com/andrewah64/base-app-client/internal/web/core/ui/notification"
+	   "github.com/andrewah64/base-app-client/internal/common/core/session"
+	   "github.com/andrewah64/base-app-client/internal/web/core/error"
+	ws "github.com/andrewah64/base-app-client/internal/web/core/session"
+	   "github.com/andrewah64/base-app-client/internal/web/core/ui/data/form"
+	   "github.com/andrewah64/base-app-client/internal/web/core/ui/data/page"
+	   "github.com/andrewah64/base-app-client/internal/web/core/ui/html"
+	   "github.com/andrewah64/base-app-client/internal/web/core/ui/notification"
 )
 
 func Get (rw http.ResponseWriter, r *http.Request) {
@@ -35,7 +36,7 @@ func Get (rw http.ResponseWriter, r *http.Request) {
 
 	grpId, grpIdErr := strconv.Atoi(r.PathValue("id"))
 	if grpIdErr != nil || grpId < 1 {
-		http.NotFound(rw, r)
+		error.Status(ctx, rw, http.StatusNotFound)
 		return
 	}
 
@@ -85,7 +86,7 @@ func Patch(rw http.ResponseWriter, r *http.Request){
 
 	grpId, grpIdErr := strconv.Atoi(r.PathValue("id"))
 	if grpIdErr != nil || grpId < 1 {
-		http.NotFound(rw, r)
+		error.Status(ctx, rw, http.StatusNotFound)
 		return
 	}
 
@@ -112,6 +113,12 @@ func Patch(rw http.ResponseWriter, r *http.Request){
 		return
 	}
 
+	rvkErr := ws.Revoke(&ctx, ssd.Logger, ssd.Conn, ws.Revocation{TntId: ssd.TntId})
+	if rvkErr != nil {
+		error.IntSrv(ctx, rw, rvkErr)
+		return
+	}
+
 	message := ""
 
 	if len(dbrlId) == 1 {
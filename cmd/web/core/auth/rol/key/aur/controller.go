@@ -35,7 +35,7 @@ func Get (rw http.ResponseWriter, r *http.Request) {
 
 	aaukId, aaukIdErr := strconv.Atoi(r.PathValue("id"))
 	if aaukIdErr != nil || aaukId < 1 {
-		http.NotFound(rw, r)
+		error.Status(ctx, rw, http.StatusNotFound)
 		return
 	}
 
@@ -85,7 +85,7 @@ func Patch(rw http.ResponseWriter, r *http.Request){
 
 	aaukId, aaukIdErr := strconv.Atoi(r.PathValue("id"))
 	if aaukIdErr != nil || aaukId < 1 {
-		http.NotFound(rw, r)
+		error.Status(ctx, rw, http.StatusNotFound)
 		return
 	}
 
@@ -141,7 +141,7 @@ func Get(rw http.ResponseWriter, r *http.Request){
 	pageNumber  := 2
 	offset      := 0
 	resultLimit := 50
-	trigger     := r.Header.Get("HX-Trigger")
+	trigger     := html.Trigger(r, "")
 
 	ssd.Logger.LogAttrs(ctx, slog.LevelDebug, "Get::retrieve datasets",
 		slog.Int   ("pageNumber"  , pageNumber),
@@ -285,7 +285,7 @@ func Patch(rw http.ResponseWriter, r *http.Request){
 		return
 	}
 
-	currentUrl := r.Header.Get("HX-Current-URL")
+	currentUrl := html.CurrentURL(r)
 
 	switch r.PathValue("nm") {
 		case "gen" :
@@ -318,7 +318,13 @@ func Patch(rw http.ResponseWriter, r *http.Request){
 				if errors.As(patchErr, &pgErr) {
 					switch pgErr.Code {
 						case "OLOCK":
-							rw.Header().Set("HX-Location", fmt.Sprintf(`{"path":"%v", "target":"#main", "select":"#content", "swap" : "innerHTML show:window:top", "values":{"ntf": "web-core-auth-s2c-tnt-mod-gen-form.warning-input-olock-error", "lvl": "error"}}`, currentUrl))
+							html.Locate(rw, r, html.Location{
+								Path   : currentUrl,
+								Target : "#main",
+								Select : "#content",
+								Swap   : "innerHTML show:window:top",
+								Values : map[string]string{"ntf": "web-core-auth-s2c-tnt-mod-gen-form.warning-input-olock-error", "lvl": "error"},
+							})
 
 						default:
 							slog.LogAttrs(ctx, slog.LevelError, "Patch::unexpected error",
@@ -116,6 +116,7 @@ func Patch(rw http.ResponseWriter, r *http.Request) {
 
 			idpNm      := form.VText (r, fmt.Sprintf("s2c-tnt-inf-idp-nm-%v"      , idpId))
 			idpEnabled := form.VBool (r, fmt.Sprintf("s2c-tnt-inf-idp-enabled-%v" , idpId))
+			idpEaVrf   := form.VBool (r, fmt.Sprintf("s2c-tnt-inf-idp-ea-vrf-%v"  , idpId))
 			uts        := form.VTime (r, fmt.Sprintf("s2c-tnt-inf-idp-uts-%v"     , idpId))
 
 			idpValRs, idpValRsErr := GetRowIdpVal(&ctx, ssd.Logger, ssd.Conn, ssd.TntId, idpId, idpEnabled, idpNm)
@@ -145,7 +146,7 @@ func Patch(rw http.ResponseWriter, r *http.Request) {
 				"OLOKD",
 			}
 
-			patchErr := PatchIdp (&ctx, ssd.Logger, ssd.Conn, ssd.TntId, idpId, idpNm, idpEnabled, data.User.AurNm, uts, exptErrs)
+			patchErr := PatchIdp (&ctx, ssd.Logger, ssd.Conn, ssd.TntId, idpId, idpNm, idpEnabled, idpEaVrf, data.User.AurNm, uts, exptErrs)
 			if patchErr != nil{
 				Get(rw, r)
 
@@ -157,6 +158,7 @@ func Patch(rw http.ResponseWriter, r *http.Request) {
 						slog.Int   ("idpId"      , idpId),
 						slog.String("idpNm"      , idpNm),
 						slog.Bool  ("idpEnabled" , idpEnabled),
+						slog.Bool  ("idpEaVrf"   , idpEaVrf),
 						slog.String("patchErr"   , patchErr.Error()),
 					)
 
@@ -25,6 +25,7 @@ type RowIdpInf struct {
 	IdpNm       string
 	IdpEntityId string
 	IdpEnabled  bool
+	IdpEaVrf    bool
 	NumMde      int
 	NumSso      int
 	NumSlo      int
@@ -62,6 +63,7 @@ type RowIdpMod struct {
 	IdpNm       string
 	IdpEntityId string
 	IdpEnabled  bool
+	IdpEaVrf    bool
 	NumMde      int
 	NumSso      int
 	NumSlo      int
@@ -129,14 +131,15 @@ func GetRowIdpVal (ctx *context.Context, logger *slog.Logger, conn *pgxpool.Conn
 	return rs, rErr
 }
 
-func PatchIdp (ctx *context.Context, logger *slog.Logger, conn *pgxpool.Conn, tntId int, idpId int, idpNm string, idpEnabled bool, by string, uts time.Time, exptErrs []string) error {
+func PatchIdp (ctx *context.Context, logger *slog.Logger, conn *pgxpool.Conn, tntId int, idpId int, idpNm string, idpEnabled bool, idpEaVrf bool, by string, uts time.Time, exptErrs []string) error {
 	var (
-		sprocCall   = fmt.Sprintf("call %v.row_mod_idp(@p_tnt_id, @p_idp_id, @p_idp_nm, @p_idp_enabled, @p_by, @p_uts)", dbSchema)
+		sprocCall   = fmt.Sprintf("call %v.row_mod_idp(@p_tnt_id, @p_idp_id, @p_idp_nm, @p_idp_enabled, @p_idp_ea_vrf, @p_by, @p_uts)", dbSchema)
 		sprocParams = pgx.NamedArgs{
 			"p_tnt_id"      : tntId,
 			"p_idp_id"      : idpId,
 			"p_idp_nm"      : idpNm,
 			"p_idp_enabled" : idpEnabled,
+			"p_idp_ea_vrf"  : idpEaVrf,
 			"p_by"          : by,
 			"p_uts"         : uts,
 		}
@@ -151,6 +154,7 @@ func PatchIdp (ctx *context.Context, logger *slog.Logger, conn *pgxpool.Conn, tn
 			slog.Int   ("idpId"      , idpId),
 			slog.String("idpNm"      , idpNm),
 			slog.Bool  ("idpEnabled" , idpEnabled),
+			slog.Bool  ("idpEaVrf"   , idpEaVrf),
 			slog.String("by"         , by),
 			slog.Any   ("uts"        , uts),
 			slog.Any   ("exptErrs"   , exptErrs),
@@ -89,6 +89,7 @@ type IdpInf struct {
 	IdpNm       string
 	IdpEntityId string
 	IdpEnabled  bool
+	IdpEaVrf    bool
 	NumMde      int
 	NumSso      int
 	NumSlo      int
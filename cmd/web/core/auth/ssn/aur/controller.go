@@ -11,6 +11,7 @@ import (
 	   "github.com/andrewah64/base-app-client/internal/web/core/error"
 	ws "github.com/andrewah64/base-app-client/internal/web/core/session"
 	   "github.com/andrewah64/base-app-client/internal/web/core/ui/data/page"
+	   "github.com/andrewah64/base-app-client/internal/web/core/ui/html"
 )
 
 func Delete(rw http.ResponseWriter, r *http.Request) {
@@ -42,7 +43,7 @@ func Delete(rw http.ResponseWriter, r *http.Request) {
 			slog.String("error", err.Error()),
 		)
 
-		rw.Header().Set("HX-Redirect", "/")
+		html.Redirect(rw, r, "/")
 
 		return
 	}
@@ -51,9 +52,9 @@ func Delete(rw http.ResponseWriter, r *http.Request) {
 		slog.Int("data.User.AurId", data.User.AurId),
 	)
 
-	ws.End(&ctx, ssd.Logger, ssd.Conn, rw, ssnTkn)
+	ws.End(&ctx, ssd.Logger, ssd.Conn, rw, ssd.TntId, ssnTkn)
 
-	rw.Header().Set("HX-Redirect", "/")
+	html.Redirect(rw, r, "/")
 
 	return
 }
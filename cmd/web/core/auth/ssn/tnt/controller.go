@@ -9,12 +9,13 @@ import (
 )
 
 import (
-	"github.com/andrewah64/base-app-client/internal/common/core/session"
-	"github.com/andrewah64/base-app-client/internal/web/core/error"
-	"github.com/andrewah64/base-app-client/internal/web/core/ui/data/form"
-	"github.com/andrewah64/base-app-client/internal/web/core/ui/data/page"
-	"github.com/andrewah64/base-app-client/internal/web/core/ui/html"
-	"github.com/andrewah64/base-app-client/internal/web/core/ui/notification"
+	   "github.com/andrewah64/base-app-client/internal/common/core/session"
+	   "github.com/andrewah64/base-app-client/internal/web/core/error"
+	ws "github.com/andrewah64/base-app-client/internal/web/core/session"
+	   "github.com/andrewah64/base-app-client/internal/web/core/ui/data/form"
+	   "github.com/andrewah64/base-app-client/internal/web/core/ui/data/page"
+	   "github.com/andrewah64/base-app-client/internal/web/core/ui/html"
+	   "github.com/andrewah64/base-app-client/internal/web/core/ui/notification"
 )
 
 func Delete (rw http.ResponseWriter, r *http.Request) {
@@ -49,6 +50,12 @@ func Delete (rw http.ResponseWriter, r *http.Request) {
 			return
 		}
 
+		rvkErr := ws.Revoke(&ctx, ssd.Logger, ssd.Conn, ws.Revocation{TntId: ssd.TntId, SsnTk: ssnTkn})
+		if rvkErr != nil {
+			error.IntSrv(ctx, rw, rvkErr)
+			return
+		}
+
 		ssd.Logger.LogAttrs(ctx, slog.LevelDebug, "Delete::success")
 
 		rw.Header().Set("HX-Trigger", "mod")
@@ -99,7 +106,7 @@ func Get (rw http.ResponseWriter, r *http.Request) {
 	pageNumber  := 2
 	offset      := 0
 	resultLimit := 50
-	trigger     := r.Header.Get("HX-Trigger")
+	trigger     := html.Trigger(r, "ssn-tnt-inf-form")
 
 	switch trigger {
 		case "": // page load
@@ -190,13 +197,14 @@ func Get (rw http.ResponseWriter, r *http.Request) {
 
 			data.ResultSet = &map[string]any{
 				"Search"      : &ssnRs,
+				"PageNumber"  : pageNumber,
 				"ResultLimit" : resultLimit,
 				"Params"      : params(aurNm, pageNumber + 1),
 			}
 
 			rw.Header().Set("HX-Trigger", "src")
 
-			html.Tmpl(ctx, ssd.Logger, rw, r, "core/auth/ssn/tnt/template/res", http.StatusOK, &data)
+			html.Partial(ctx, ssd.Logger, rw, r, "core/auth/ssn/tnt/content", "core/auth/ssn/tnt/template/res", http.StatusOK, &data)
 
 			ssd.Logger.LogAttrs(ctx, slog.LevelDebug, "Get::end [search]")
 	}
@@ -0,0 +1,267 @@
+package tnt
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+)
+
+import (
+	"github.com/andrewah64/base-app-client/internal/common/core/session"
+	"github.com/andrewah64/base-app-client/internal/web/core/error"
+	"github.com/andrewah64/base-app-client/internal/web/core/refresh"
+	"github.com/andrewah64/base-app-client/internal/web/core/ui/data/form"
+	"github.com/andrewah64/base-app-client/internal/web/core/ui/data/page"
+	"github.com/andrewah64/base-app-client/internal/web/core/ui/html"
+	"github.com/andrewah64/base-app-client/internal/web/core/ui/notification"
+)
+
+import (
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+var (
+	Protocols = []string{"https", "http"}
+)
+
+func params(tntFqdn string, tntEnb *bool, pageNumber int) string {
+	v := url.Values{}
+
+	v.Set("tnt-all-inf-tnt-fqdn" , tntFqdn)
+
+	switch tntEnb {
+		case nil:
+			v.Set("tnt-all-inf-tnt-enb" , "")
+		default :
+			v.Set("tnt-all-inf-tnt-enb" , strconv.FormatBool(*tntEnb))
+	}
+
+	v.Set("tnt-all-inf-page-number" , strconv.Itoa(pageNumber))
+
+	return v.Encode()
+}
+
+func Get (rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	ssd, ok := session.FromContext(ctx)
+	if ! ok {
+		error.IntSrv(ctx, rw, fmt.Errorf("Get::get request info"))
+		return
+	}
+
+	ssd.Logger.LogAttrs(ctx, slog.LevelDebug, "Get::start")
+
+	data, ok := page.FromContext(ctx)
+	if ! ok {
+		error.IntSrv(ctx, rw, fmt.Errorf("Get::get request data"))
+		return
+	}
+
+	pageNumber  := 2
+	offset      := 0
+	resultLimit := 50
+	trigger     := html.Trigger(r, "tnt-all-inf-form")
+
+	ssd.Logger.LogAttrs(ctx, slog.LevelDebug, "Get::retrieve datasets",
+		slog.Int   ("pageNumber"  , pageNumber),
+		slog.Int   ("offset"      , offset),
+		slog.Int   ("resultLimit" , resultLimit),
+		slog.String("trigger"     , trigger),
+	)
+
+	switch trigger {
+		case "": // page load
+			tntRs, tntRsErr := GetTnt(&ctx, ssd.Logger, ssd.Conn, "", nil, offset, resultLimit)
+			if tntRsErr != nil {
+				error.IntSrv(ctx, rw, tntRsErr)
+				return
+			}
+
+			ssd.Logger.LogAttrs(ctx, slog.LevelDebug, "Get::retrieve datasets",
+				slog.Int("len(tntRs)" , len(tntRs)),
+			)
+
+			data.FormOpts  = &map[string]any{"Protocols": Protocols}
+			data.ResultSet = &map[string]any{
+				"Search"      : &tntRs,
+				"PageNumber"  : pageNumber,
+				"ResultLimit" : resultLimit,
+				"Params"      : params("", nil, pageNumber),
+			}
+
+			html.Tmpl(ctx, ssd.Logger, rw, r, "core/auth/tnt/content", http.StatusOK, &data)
+
+			ssd.Logger.LogAttrs(ctx, slog.LevelDebug, "Get::end [page load]")
+
+		case "tnt-all-inf-scr", "tnt-all-inf-form": // infinite scroll, search
+			pfErr := r.ParseForm()
+			if pfErr != nil {
+				error.IntSrv(ctx, rw, pfErr)
+				return
+			}
+
+			tntFqdn    := form.VText (r, "tnt-all-inf-tnt-fqdn")
+			tntEnb     := form.PBool (r, "tnt-all-inf-tnt-enb")
+			pageNumber := form.VInt  (r, "tnt-all-inf-page-number")
+
+			if trigger == "tnt-all-inf-scr" {
+				offset = (pageNumber - 1) * resultLimit
+			}
+
+			ssd.Logger.LogAttrs(ctx, slog.LevelDebug, "Get::get data from form",
+				slog.String("tntFqdn"    , tntFqdn),
+				slog.Any   ("tntEnb"     , tntEnb),
+				slog.Int   ("pageNumber" , pageNumber),
+				slog.Int   ("offset"     , offset),
+			)
+
+			tntRs, tntRsErr := GetTnt(&ctx, ssd.Logger, ssd.Conn, tntFqdn, tntEnb, offset, resultLimit)
+			if tntRsErr != nil {
+				error.IntSrv(ctx, rw, tntRsErr)
+				return
+			}
+
+			ssd.Logger.LogAttrs(ctx, slog.LevelDebug, "Get::retrieve datasets",
+				slog.Int("len(tntRs)" , len(tntRs)),
+			)
+
+			nextPageNumber := pageNumber
+
+			if trigger == "tnt-all-inf-scr" {
+				nextPageNumber = pageNumber + 1
+
+				rw.Header().Set("HX-Trigger", "inf")
+			} else {
+				rw.Header().Set("HX-Trigger", "src")
+			}
+
+			data.FormOpts  = &map[string]any{"Protocols": Protocols}
+			data.ResultSet = &map[string]any{
+				"Search"      : &tntRs,
+				"PageNumber"  : pageNumber,
+				"ResultLimit" : resultLimit,
+				"Params"      : params(tntFqdn, tntEnb, nextPageNumber),
+			}
+
+			html.Partial(ctx, ssd.Logger, rw, r, "core/auth/tnt/content", "core/auth/tnt/template/res", http.StatusOK, &data)
+
+			ssd.Logger.LogAttrs(ctx, slog.LevelDebug, "Get::end [search]")
+	}
+
+	return
+}
+
+func Post (rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	ssd, ok := session.FromContext(ctx)
+	if ! ok {
+		error.IntSrv(ctx, rw, fmt.Errorf("Post::get request info"))
+		return
+	}
+
+	ssd.Logger.LogAttrs(ctx, slog.LevelDebug, "Post::start")
+
+	data, ok := page.FromContext(ctx)
+	if ! ok {
+		error.IntSrv(ctx, rw, fmt.Errorf("Post::get request data"))
+		return
+	}
+
+	pfErr := r.ParseForm()
+	if pfErr != nil {
+		error.IntSrv(ctx, rw, pfErr)
+		return
+	}
+
+	tntPrtc := form.VText (r, "tnt-all-reg-tnt-prtc")
+	tntFqdn := form.VText (r, "tnt-all-reg-tnt-fqdn")
+	tntPort := form.VInt  (r, "tnt-all-reg-tnt-port")
+	seed    := form.VBool (r, "tnt-all-reg-seed")
+
+	ssd.Logger.LogAttrs(ctx, slog.LevelDebug, "Post::get data from form",
+		slog.String("tntPrtc" , tntPrtc),
+		slog.String("tntFqdn" , tntFqdn),
+		slog.Int   ("tntPort" , tntPort),
+		slog.Bool  ("seed"    , seed),
+	)
+
+	msgs := Validate(data, tntPrtc, tntFqdn, tntPort)
+	if len(msgs) > 0 {
+		notification.Vrl(ctx, ssd.Logger, rw, r,
+			data.T("web-core-auth-tnt-page.title"),
+			data.T("web-core-auth-tnt-reg-form.title-warning-singular", "n", strconv.Itoa(len(msgs))),
+			data.T("web-core-auth-tnt-reg-form.title-warning-plural"  , "n", strconv.Itoa(len(msgs))),
+			&msgs,
+			data,
+		)
+
+		return
+	}
+
+	exptErrs := []string{
+		pgerrcode.CheckViolation,
+		pgerrcode.UniqueViolation,
+	}
+
+	regErr := PostTnt(&ctx, ssd.Logger, ssd.Conn, tntPrtc, tntFqdn, tntPort, seed, data.User.AurNm, exptErrs)
+	if regErr != nil {
+		var pgErr *pgconn.PgError
+
+		message := data.T("web-core-auth-tnt-reg-form.warning-input-unexpected-error")
+
+		if errors.As(regErr, &pgErr) {
+			switch pgErr.Code {
+				case pgerrcode.CheckViolation:
+					message = data.T("web-core-auth-tnt-reg-form.warning-input-tnt-invalid")
+				case pgerrcode.UniqueViolation:
+					message = data.T("web-core-auth-tnt-reg-form.warning-input-tnt-taken", "tntFqdn", tntFqdn)
+			}
+		}
+
+		notification.Toast(ctx, ssd.Logger, rw, r, "error" , &map[string]string{"Message" : message}, data)
+
+		return
+	}
+
+	rfrErr := refresh.Notify(&ctx, ssd.Logger, ssd.Conn, tntFqdn)
+	if rfrErr != nil {
+		error.IntSrv(ctx, rw, rfrErr)
+		return
+	}
+
+	rw.Header().Set("HX-Trigger", "mod")
+
+	notification.Toast(ctx, ssd.Logger, rw, r, "success" , &map[string]string{"Message" : data.T("web-core-auth-tnt-reg-form.message-input-success", "tntFqdn", tntFqdn)}, data)
+
+	ssd.Logger.LogAttrs(ctx, slog.LevelDebug, "Post::end")
+
+	return
+}
+
+// Validate returns the reasons, if any, why a tenant's origin is unusable.
+func Validate(data *page.Data, tntPrtc string, tntFqdn string, tntPort int) []string {
+	msgs := make([]string, 0)
+
+	if ! slices.Contains(Protocols, tntPrtc) {
+		msgs = append(msgs, data.T("web-core-auth-tnt-reg-form.warning-input-tnt-prtc-invalid"))
+	}
+
+	if tntFqdn == "" {
+		msgs = append(msgs, data.T("web-core-auth-tnt-reg-form.warning-input-tnt-fqdn-blank"))
+	} else if u, uErr := url.Parse(fmt.Sprintf("https://%v", tntFqdn)); uErr != nil || u.Host != tntFqdn || u.Port() != "" {
+		msgs = append(msgs, data.T("web-core-auth-tnt-reg-form.warning-input-tnt-fqdn-invalid", "tntFqdn", tntFqdn))
+	}
+
+	if tntPort < 1 || tntPort > 65535 {
+		msgs = append(msgs, data.T("web-core-auth-tnt-reg-form.warning-input-tnt-port-invalid"))
+	}
+
+	return msgs
+}
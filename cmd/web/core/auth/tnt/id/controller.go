@@ -0,0 +1,205 @@
+package id
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+import (
+	   "github.com/andrewah64/base-app-client/internal/common/core/session"
+	   "github.com/andrewah64/base-app-client/internal/web/core/error"
+	   "github.com/andrewah64/base-app-client/internal/web/core/refresh"
+	ws "github.com/andrewah64/base-app-client/internal/web/core/session"
+	   "github.com/andrewah64/base-app-client/internal/web/core/ui/data/form"
+	   "github.com/andrewah64/base-app-client/internal/web/core/ui/data/page"
+	   "github.com/andrewah64/base-app-client/internal/web/core/ui/html"
+	   "github.com/andrewah64/base-app-client/internal/web/core/ui/notification"
+)
+
+import (
+	"github.com/andrewah64/base-app-client/cmd/web/core/auth/tnt"
+)
+
+import (
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+func Get(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	ssd, ok := session.FromContext(ctx)
+	if ! ok {
+		error.IntSrv(ctx, rw, fmt.Errorf("Get::get request info"))
+		return
+	}
+
+	ssd.Logger.LogAttrs(ctx, slog.LevelDebug, "Get::start")
+
+	data, ok := page.FromContext(ctx)
+	if ! ok {
+		error.IntSrv(ctx, rw, fmt.Errorf("Get::get request data"))
+		return
+	}
+
+	tntId, tntIdErr := strconv.Atoi(r.PathValue("id"))
+	if tntIdErr != nil || tntId < 1 {
+		error.IntSrv(ctx, rw, fmt.Errorf("Get::get tntId"))
+		return
+	}
+
+	tntRs, tntRsErr := GetRowTntMod(&ctx, ssd.Logger, ssd.Conn, tntId)
+	if tntRsErr != nil {
+		error.IntSrv(ctx, rw, tntRsErr)
+		return
+	}
+
+	ssd.Logger.LogAttrs(ctx, slog.LevelDebug, "Get::retrieve datasets",
+		slog.Int("tntId"      , tntId),
+		slog.Int("len(tntRs)" , len(tntRs)),
+	)
+
+	data.FormOpts  = &map[string]any{"Protocols": tnt.Protocols}
+	data.ResultSet = &map[string]any{"Tenant": &tntRs}
+
+	html.Fragment(ctx, ssd.Logger, rw, r, "core/auth/tnt/fragment/modrow", http.StatusCreated, &data)
+
+	if len(tntRs) == 0 {
+		notification.Toast(ctx, slog.Default(), rw, r, "error" , &map[string]string{"Message" : data.T("web-core-auth-tnt-mod-form.warning-input-tnt-olock-error")}, data)
+	}
+
+	ssd.Logger.LogAttrs(ctx, slog.LevelDebug, "Get::end")
+
+	return
+}
+
+func Patch(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	ssd, ok := session.FromContext(ctx)
+	if ! ok {
+		error.IntSrv(ctx, rw, fmt.Errorf("Patch::get request info"))
+		return
+	}
+
+	ssd.Logger.LogAttrs(ctx, slog.LevelDebug, "Patch::start")
+
+	data, ok := page.FromContext(ctx)
+	if ! ok {
+		error.IntSrv(ctx, rw, fmt.Errorf("Patch::get request data"))
+		return
+	}
+
+	tntId, tntIdErr := strconv.Atoi(r.PathValue("id"))
+	if tntIdErr != nil || tntId < 1 {
+		error.Status(ctx, rw, http.StatusNotFound)
+		return
+	}
+
+	pfErr := r.ParseForm()
+	if pfErr != nil {
+		error.IntSrv(ctx, rw, pfErr)
+		return
+	}
+
+	tntPrtc := form.VText (r, fmt.Sprintf("tnt-all-mod-tnt-prtc-%v", tntId))
+	tntFqdn := form.VText (r, fmt.Sprintf("tnt-all-mod-tnt-fqdn-%v", tntId))
+	tntPort := form.VInt  (r, fmt.Sprintf("tnt-all-mod-tnt-port-%v", tntId))
+	tntEnb  := form.VBool (r, fmt.Sprintf("tnt-all-mod-tnt-enb-%v" , tntId))
+	uts     := form.VTime (r, fmt.Sprintf("tnt-all-mod-uts-%v"     , tntId))
+
+	ssd.Logger.LogAttrs(ctx, slog.LevelDebug, "Patch::get data from form",
+		slog.Int   ("tntId"   , tntId),
+		slog.String("tntPrtc" , tntPrtc),
+		slog.String("tntFqdn" , tntFqdn),
+		slog.Int   ("tntPort" , tntPort),
+		slog.Bool  ("tntEnb"  , tntEnb),
+		slog.Any   ("uts"     , uts),
+	)
+
+	if tntId == ssd.TntId && ! tntEnb {
+		Get(rw, r)
+
+		notification.Toast(ctx, slog.Default(), rw, r, "error" , &map[string]string{"Message" : data.T("web-core-auth-tnt-mod-form.warning-input-tnt-enb-own")}, data)
+
+		return
+	}
+
+	if msgs := tnt.Validate(data, tntPrtc, tntFqdn, tntPort); len(msgs) > 0 {
+		Get(rw, r)
+
+		for _, v := range msgs {
+			notification.Toast(ctx, slog.Default(), rw, r, "error" , &map[string]string{"Message" : v}, data)
+		}
+
+		return
+	}
+
+	exptErrs := []string{
+		"OLOKU",
+		"OLOKD",
+		pgerrcode.CheckViolation,
+		pgerrcode.UniqueViolation,
+	}
+
+	patchErr := PatchTnt(&ctx, ssd.Logger, ssd.Conn, tntId, tntPrtc, tntFqdn, tntPort, tntEnb, data.User.AurNm, uts, exptErrs)
+	if patchErr != nil {
+		Get(rw, r)
+
+		var pgErr *pgconn.PgError
+
+		if errors.As(patchErr, &pgErr) {
+			switch pgErr.Code {
+				case "OLOKU":
+					notification.Toast(ctx, slog.Default(), rw, r, "error" , &map[string]string{"Message" : data.T("web-core-auth-tnt-mod-form.warning-input-tnt-olock-error")}, data)
+
+				case "OLOKD":
+					//intentionally empty
+
+				case pgerrcode.CheckViolation:
+					notification.Toast(ctx, slog.Default(), rw, r, "error" , &map[string]string{"Message" : data.T("web-core-auth-tnt-mod-form.warning-input-tnt-invalid")}, data)
+
+				case pgerrcode.UniqueViolation:
+					notification.Toast(ctx, slog.Default(), rw, r, "error" , &map[string]string{"Message" : data.T("web-core-auth-tnt-mod-form.warning-input-tnt-taken", "tntFqdn", tntFqdn)}, data)
+
+				default:
+					notification.Toast(ctx, slog.Default(), rw, r, "error" , &map[string]string{"Message" : data.T("web-core-auth-tnt-mod-form.warning-input-unexpected-error")}, data)
+			}
+		}
+
+		return
+	}
+
+	if ! tntEnb {
+		rvkErr := ws.Revoke(&ctx, ssd.Logger, ssd.Conn, ws.Revocation{TntId: tntId})
+		if rvkErr != nil {
+			error.IntSrv(ctx, rw, rvkErr)
+			return
+		}
+	}
+
+	rfrErr := refresh.Notify(&ctx, ssd.Logger, ssd.Conn, tntFqdn)
+	if rfrErr != nil {
+		error.IntSrv(ctx, rw, rfrErr)
+		return
+	}
+
+	tntRs, tntRsErr := GetRowTntInf(&ctx, ssd.Logger, ssd.Conn, tntId)
+	if tntRsErr != nil {
+		error.IntSrv(ctx, rw, tntRsErr)
+		return
+	}
+
+	data.ResultSet = &map[string]any{"Tenant": &tntRs}
+
+	html.Fragment(ctx, ssd.Logger, rw, r, "core/auth/tnt/fragment/infrow", http.StatusCreated, &data)
+
+	notification.Toast(ctx, slog.Default(), rw, r, "success" , &map[string]string{"Message" : data.T("web-core-auth-tnt-mod-form.message-input-success")}, data)
+
+	ssd.Logger.LogAttrs(ctx, slog.LevelDebug, "Patch::end")
+
+	return
+}
@@ -0,0 +1,129 @@
+package id
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+import (
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+import (
+	"github.com/andrewah64/base-app-client/internal/common/core/db"
+)
+
+type Inf struct {
+	TntId     int
+	TntPrtc   string
+	TntFqdn   string
+	TntPort   int
+	TntOrigin string
+	TntEnb    bool
+	NumUsers  int
+}
+
+type Mod struct {
+	TntId     int
+	TntPrtc   string
+	TntFqdn   string
+	TntPort   int
+	TntOrigin string
+	TntEnb    bool
+	NumUsers  int
+	Uts       time.Time
+}
+
+const (
+	dbSchema = "web_core_auth_tnt_all_mod"
+)
+
+func GetRowTntInf (ctx *context.Context, logger *slog.Logger, conn *pgxpool.Conn, tntId int) ([]Inf, error) {
+	const (
+		dbFunc = "row_tnt_inf"
+	)
+
+	rs, rErr := db.DataSet[Inf](ctx, logger, conn,
+		func(ctx *context.Context, tx *pgx.Tx)(string, string, *pgx.Rows, error){
+			qry := fmt.Sprintf("select %v.%v($1, $2)", dbSchema, dbFunc)
+
+			c, cErr := (*tx).Query(*ctx, qry, dbFunc, tntId)
+			if cErr != nil {
+				slog.LogAttrs(*ctx, slog.LevelError, "get dataset",
+					slog.String("error" , cErr.Error()),
+					slog.String("qry"   , qry),
+					slog.Int   ("tntId" , tntId),
+				)
+
+				return qry, dbFunc, nil, fmt.Errorf("call database function: %w", cErr)
+			}
+
+			return qry, dbFunc, &c, nil
+		})
+
+	return rs, rErr
+}
+
+func GetRowTntMod (ctx *context.Context, logger *slog.Logger, conn *pgxpool.Conn, tntId int) ([]Mod, error) {
+	const (
+		dbFunc = "row_tnt_mod"
+	)
+
+	rs, rErr := db.DataSet[Mod](ctx, logger, conn,
+		func(ctx *context.Context, tx *pgx.Tx)(string, string, *pgx.Rows, error){
+			qry := fmt.Sprintf("select %v.%v($1, $2)", dbSchema, dbFunc)
+
+			c, cErr := (*tx).Query(*ctx, qry, dbFunc, tntId)
+			if cErr != nil {
+				slog.LogAttrs(*ctx, slog.LevelError, "get dataset",
+					slog.String("error" , cErr.Error()),
+					slog.String("qry"   , qry),
+					slog.Int   ("tntId" , tntId),
+				)
+
+				return qry, dbFunc, nil, fmt.Errorf("call database function: %w", cErr)
+			}
+
+			return qry, dbFunc, &c, nil
+		})
+
+	return rs, rErr
+}
+
+func PatchTnt (ctx *context.Context, logger *slog.Logger, conn *pgxpool.Conn, tntId int, tntPrtc string, tntFqdn string, tntPort int, tntEnb bool, by string, uts time.Time, exptErrs []string) error {
+	var (
+		sprocCall   = fmt.Sprintf("call %v.row_mod_tnt(@p_tnt_id, @p_tnt_prtc, @p_tnt_fqdn, @p_tnt_port, @p_tnt_enb, @p_by, @p_uts)", dbSchema)
+		sprocParams = pgx.NamedArgs{
+			"p_tnt_id"   : tntId,
+			"p_tnt_prtc" : tntPrtc,
+			"p_tnt_fqdn" : tntFqdn,
+			"p_tnt_port" : tntPort,
+			"p_tnt_enb"  : tntEnb,
+			"p_by"       : by,
+			"p_uts"      : uts,
+		}
+	)
+
+	sprocErr := db.Sproc(ctx, logger, conn, sprocCall, sprocParams, exptErrs)
+	if sprocErr != nil {
+		logger.LogAttrs(*ctx, slog.LevelDebug, "call sproc",
+			slog.String("sprocCall" , sprocCall),
+			slog.String("error"     , sprocErr.Error()),
+			slog.Int   ("tntId"     , tntId),
+			slog.String("tntPrtc"   , tntPrtc),
+			slog.String("tntFqdn"   , tntFqdn),
+			slog.Int   ("tntPort"   , tntPort),
+			slog.Bool  ("tntEnb"    , tntEnb),
+			slog.String("by"        , by),
+			slog.Any   ("uts"       , uts),
+			slog.Any   ("exptErrs"  , exptErrs),
+		)
+
+		return sprocErr
+	}
+
+	return nil
+}
@@ -0,0 +1,83 @@
+package tnt
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+)
+
+import (
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+import (
+	"github.com/andrewah64/base-app-client/internal/common/core/db"
+)
+
+type Inf struct {
+	TntId     int
+	TntPrtc   string
+	TntFqdn   string
+	TntPort   int
+	TntOrigin string
+	TntEnb    bool
+	NumUsers  int
+}
+
+func GetTnt (ctx *context.Context, logger *slog.Logger, conn *pgxpool.Conn, tntFqdn string, tntEnb *bool, offset int, limit int) ([]Inf, error) {
+	rs, rErr := db.DataSet[Inf](ctx, logger, conn,
+		func(ctx *context.Context, tx *pgx.Tx)(string, string, *pgx.Rows, error){
+			dbFunc := "tnt_inf"
+			qry    := fmt.Sprintf("select web_core_auth_tnt_all_inf.%v($1, $2, $3, $4, $5)", dbFunc)
+
+			c, cErr := (*tx).Query(*ctx, qry, dbFunc, tntFqdn, tntEnb, offset, limit)
+			if cErr != nil {
+				slog.LogAttrs(*ctx, slog.LevelError, "get dataset",
+					slog.String("cErr.Error()" , cErr.Error()),
+					slog.String("qry"          , qry),
+					slog.String("tntFqdn"      , tntFqdn),
+					slog.Any   ("tntEnb"       , tntEnb),
+					slog.Int   ("offset"       , offset),
+					slog.Int   ("limit"        , limit),
+				)
+
+				return qry, dbFunc, nil, fmt.Errorf("call database function: %w", cErr)
+			}
+
+			return qry, dbFunc, &c, nil
+		})
+
+	return rs, rErr
+}
+
+func PostTnt (ctx *context.Context, logger *slog.Logger, conn *pgxpool.Conn, tntPrtc string, tntFqdn string, tntPort int, seed bool, by string, exptErrs []string) error {
+	var (
+		sprocCall   = "call web_core_auth_tnt_all_reg.reg_tnt(@p_tnt_prtc, @p_tnt_fqdn, @p_tnt_port, @p_seed, @p_by)"
+		sprocParams = pgx.NamedArgs{
+			"p_tnt_prtc" : tntPrtc,
+			"p_tnt_fqdn" : tntFqdn,
+			"p_tnt_port" : tntPort,
+			"p_seed"     : seed,
+			"p_by"       : by,
+		}
+	)
+
+	sprocErr := db.Sproc(ctx, logger, conn, sprocCall, sprocParams, exptErrs)
+	if sprocErr != nil {
+		logger.LogAttrs(*ctx, slog.LevelDebug, "call sproc",
+			slog.String("sprocCall" , sprocCall),
+			slog.String("error"     , sprocErr.Error()),
+			slog.String("tntPrtc"   , tntPrtc),
+			slog.String("tntFqdn"   , tntFqdn),
+			slog.Int   ("tntPort"   , tntPort),
+			slog.Bool  ("seed"      , seed),
+			slog.String("by"        , by),
+			slog.Any   ("exptErrs"  , exptErrs),
+		)
+
+		return sprocErr
+	}
+
+	return nil
+}
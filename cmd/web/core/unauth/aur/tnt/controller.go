@@ -6,6 +6,7 @@ import (
 	"log/slog"
 	"net/http"
 	"net/mail"
+	"strconv"
 	"strings"
 )
 
@@ -15,11 +16,13 @@ import (
 	"github.com/andrewah64/base-app-client/internal/common/core/tenant"
 	"github.com/andrewah64/base-app-client/internal/common/core/token"
 	"github.com/andrewah64/base-app-client/internal/web/core/error"
+	"github.com/andrewah64/base-app-client/internal/web/core/mfa"
 	"github.com/andrewah64/base-app-client/internal/web/core/passkey"
 	"github.com/andrewah64/base-app-client/internal/web/core/ui/data/form"
 	"github.com/andrewah64/base-app-client/internal/web/core/ui/data/page"
 	"github.com/andrewah64/base-app-client/internal/web/core/ui/html"
 	"github.com/andrewah64/base-app-client/internal/web/core/ui/notification"
+	"github.com/andrewah64/base-app-client/internal/web/core/verify"
 )
 
 import (
@@ -180,6 +183,30 @@ func Post(rw http.ResponseWriter, r *http.Request){
 				return
 			}
 
+			pwdRs, pwdRsErr := val.GetPwdInf(&ctx, ssd.Logger, ssd.Conn, ssd.TntId)
+			if pwdRsErr != nil {
+				error.IntSrv(ctx, rw, pwdRsErr)
+				return
+			}
+
+			if vrls := val.Policy(pwdRs[0]).Check(ctx, aurPw, nil); len(vrls) > 0 {
+				msgs := make([]string, len(vrls))
+
+				for i, vrl := range vrls {
+					msgs[i] = data.T("web-core-unauth-aur-tnt-aupc-tab.error-input-aur-pwd-" + vrl)
+				}
+
+				notification.Vrl(ctx, ssd.Logger, rw, r,
+					data.T("web-core-unauth-aur-tnt-aupc-tab.message-error"),
+					data.T("web-core-unauth-aur-tnt-aupc-tab.title-warning-singular", "n", strconv.Itoa(len(msgs))),
+					data.T("web-core-unauth-aur-tnt-aupc-tab.title-warning-plural"  , "n", strconv.Itoa(len(msgs))),
+					&msgs,
+					data,
+				)
+
+				return
+			}
+
 			aurHshPw, pErr := password.Hash(aurPw)
 			if pErr != nil {
 				error.IntSrv(ctx, rw, pErr)
@@ -194,7 +221,21 @@ func Post(rw http.ResponseWriter, r *http.Request){
 				return
 			}
 
-			if mfaRs[0].AupcMfaEnabled {
+			evrId, evrHsh, evrErr := verify.Token()
+			if evrErr != nil {
+				error.IntSrv(ctx, rw, evrErr)
+				return
+			}
+
+			msg, msgErr := verify.Message(ctx, r, data, aurNm, aurEa, evrId)
+			if msgErr != nil {
+				error.IntSrv(ctx, rw, msgErr)
+				return
+			}
+
+			// a passkey can't be added before the user has signed in, so an
+			// authenticator app is set up wherever the tenant accepts one
+			if mfa.Otp(mfaRs[0].AupcMfaNm) {
 				otpId , otpIdErr := token.Token(32)
 				if otpIdErr != nil {
 					error.IntSrv(ctx, rw, otpIdErr)
@@ -212,21 +253,30 @@ func Post(rw http.ResponseWriter, r *http.Request){
 
 				otpSecret := otpTotpSecret.Secret()
 
-				regErr := PostPwAur(&ctx, ssd.Logger, ssd.Conn, ssd.TntId, aurNm, aurHshPw, aurEa, &otpId, &otpSecret, nil)
+				regErr := PostPwAur(&ctx, ssd.Logger, ssd.Conn, ssd.TntId, aurNm, aurHshPw, aurEa, &otpId, &otpSecret, evrHsh, verify.Mins, msg, nil)
 				if regErr != nil {
 					error.IntSrv(ctx, rw, regErr)
 					return
 				}
 
-				rw.Header().Set("HX-Location", fmt.Sprintf(`{"path":"/web/core/unauth/otp/aur/%v", "target":"#main", "select":"#content"}`, otpId))
+				html.Locate(rw, r, html.Location{
+					Path   : fmt.Sprintf("/web/core/unauth/otp/aur/%v", otpId),
+					Target : "#main",
+					Select : "#content",
+				})
 			} else {
-				regErr := PostPwAur(&ctx, ssd.Logger, ssd.Conn, ssd.TntId, aurNm, aurHshPw, aurEa, nil, nil, nil)
+				regErr := PostPwAur(&ctx, ssd.Logger, ssd.Conn, ssd.TntId, aurNm, aurHshPw, aurEa, nil, nil, evrHsh, verify.Mins, msg, nil)
 				if regErr != nil {
 					error.IntSrv(ctx, rw, regErr)
 					return
 				}
 
-				rw.Header().Set("HX-Location", `{"path":"/", "target":"#main", "select":"#content", "values":{"ntf": "web-core-unauth-aur-tnt-aupc-tab.message-success"}}`)
+				html.Locate(rw, r, html.Location{
+					Path   : "/",
+					Target : "#main",
+					Select : "#content",
+					Values : map[string]string{"ntf": "web-core-unauth-aur-tnt-aupc-tab.message-success"},
+				})
 			}
 		case "pky-reg-bgn":
 			aurNm := strings.ToLower(form.VText(r, "aur-tnt-reg-pky-aur-nm"))
@@ -288,7 +338,9 @@ func Post(rw http.ResponseWriter, r *http.Request){
 				pkh[i] = protocol.PublicKeyCredentialHints(v)
 			}
 
-			rrk := false
+			// older authenticators only know requireResidentKey, which must
+			// agree with the tenant's resident key setting
+			rrk := aukcRegInfRs[0].PdcNm == string(protocol.ResidentKeyRequirementRequired)
 
 			regOpts := []webauthn.RegistrationOption {
 				webauthn.WithAuthenticatorSelection(
@@ -353,8 +405,23 @@ func Post(rw http.ResponseWriter, r *http.Request){
 				return
 			}
 
-			c, brErr := passkey.WebAuthn(&ctx, ssd.Logger, ssd.TntId).FinishRegistration(user, sd,  r)
+			aukcRegInfRs, aukcRegInfRsErr := GetAukcRegInf(&ctx, ssd.Logger, ssd.Conn, ssd.TntId)
+			if aukcRegInfRsErr != nil {
+				error.IntSrv(ctx, rw, aukcRegInfRsErr)
+				return
+			}
+
+			c, brErr := passkey.FinishRegistration(&ctx, ssd.Logger, ssd.TntId, aukcRegInfRs[0].Policy(), user, sd, r)
 			if brErr != nil {
+				if passkey.Rejected(brErr) {
+					ssd.Logger.LogAttrs(ctx, slog.LevelDebug, "Post::authenticator rejected by the tenant's policy",
+						slog.String("error", brErr.Error()),
+					)
+
+					notification.ToastStatus(ctx, ssd.Logger, rw, r, "error" , &map[string]string{"Message" : data.T("web-core-unauth-aur-tnt-pky-tab.error-input-pky-rejected")}, http.StatusUnprocessableEntity, data)
+					return
+				}
+
 				error.IntSrv(ctx, rw, brErr)
 				return
 			}
@@ -13,6 +13,8 @@ import (
 
 import (
 	"github.com/andrewah64/base-app-client/internal/common/core/db"
+	"github.com/andrewah64/base-app-client/internal/common/core/mail"
+	"github.com/andrewah64/base-app-client/internal/web/core/passkey"
 )
 
 type AukcInf struct {
@@ -53,6 +55,19 @@ type AukcRegInf struct {
 	PuvNm   string
 	PkgCd []int
 	PkhNm []string
+	PapNm   string
+	PalNm   string
+	Aaguid []string
+}
+
+// Policy is the tenant's rules for which authenticators a passkey may be
+// registered on.
+func (a AukcRegInf) Policy() passkey.Policy {
+	return passkey.Policy{
+		PapNm  : a.PapNm,
+		PalNm  : a.PalNm,
+		Aaguid : a.Aaguid,
+	}
 }
 
 func GetAukcRegInf (ctx *context.Context, logger *slog.Logger, conn *pgxpool.Conn, tntId int) ([]AukcRegInf, error) {
@@ -121,6 +136,9 @@ type AupcInf struct {
 	AupcAurPwdMaxLen int
 	AupcAurPwdIncSym bool
 	AupcAurPwdIncNum bool
+	AupcAurPwdIncUpr bool
+	AupcAurPwdIncLwr bool
+	AupcAurPwdSymSet string
 }
 
 func GetAupcInf (ctx *context.Context, logger *slog.Logger, conn *pgxpool.Conn, tntId int) ([]AupcInf, error) {
@@ -150,7 +168,7 @@ func GetAupcInf (ctx *context.Context, logger *slog.Logger, conn *pgxpool.Conn,
 }
 
 type MfaInf struct {
-	AupcMfaEnabled bool
+	AupcMfaNm string
 }
 
 func GetMfaInf (ctx *context.Context, logger *slog.Logger, conn *pgxpool.Conn, tntId int) ([]MfaInf, error) {
@@ -210,9 +228,12 @@ func GetPrsInf (ctx *context.Context, logger *slog.Logger, conn *pgxpool.Conn, t
 	return results, err
 }
 
-func PostPwAur (ctx *context.Context, logger *slog.Logger, conn *pgxpool.Conn, tntId int, aurNm string, aurHshPw string, aurEa string, otpId *string, otpSecret *string, exptErrs []string) error {
+// PostPwAur registers the user with their email address waiting to be
+// verified and, in the same transaction, queues msg, which sends them the
+// link to verify it.
+func PostPwAur (ctx *context.Context, logger *slog.Logger, conn *pgxpool.Conn, tntId int, aurNm string, aurHshPw string, aurEa string, otpId *string, otpSecret *string, evrHsh string, evrMins int, msg mail.Message, exptErrs []string) error {
 	var (
-		sprocCall   = "call web_core_unauth_aur_tnt_reg.reg_aur(@p_tnt_id, @p_aur_nm, @p_aur_hsh_pw, @p_aur_ea, @p_otp_id, @p_otp_secret)"
+		sprocCall   = "call web_core_unauth_aur_tnt_reg.reg_aur(@p_tnt_id, @p_aur_nm, @p_aur_hsh_pw, @p_aur_ea, @p_otp_id, @p_otp_secret, @p_evr_hsh, @p_evr_mins, @p_mob_to, @p_mob_sbj, @p_mob_txt, @p_mob_htm)"
 		sprocParams = pgx.NamedArgs{
 			"p_tnt_id"     : tntId,
 			"p_aur_nm"     : aurNm,
@@ -220,6 +241,12 @@ func PostPwAur (ctx *context.Context, logger *slog.Logger, conn *pgxpool.Conn, t
 			"p_aur_ea"     : aurEa,
 			"p_otp_id"     : otpId,
 			"p_otp_secret" : otpSecret,
+			"p_evr_hsh"    : evrHsh,
+			"p_evr_mins"   : evrMins,
+			"p_mob_to"     : msg.To,
+			"p_mob_sbj"    : msg.Subject,
+			"p_mob_txt"    : msg.Text,
+			"p_mob_htm"    : msg.Html,
 		}
 	)
 
@@ -231,6 +258,7 @@ func PostPwAur (ctx *context.Context, logger *slog.Logger, conn *pgxpool.Conn, t
 			slog.Int   ("tntId"     , tntId),
 			slog.String("aurNm"     , aurNm),
 			slog.String("aurEa"     , aurEa),
+			slog.Int   ("evrMins"   , evrMins),
 			slog.Any   ("exptErrs"  , exptErrs),
 		)
 
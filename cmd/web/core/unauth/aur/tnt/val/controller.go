@@ -5,6 +5,7 @@ import (
 	"log/slog"
 	"net/http"
 	"net/mail"
+	"slices"
 )
 
 import (
@@ -88,12 +89,14 @@ func Get(rw http.ResponseWriter, r *http.Request) {
 				return
 			}
 
-			aurPwdLenPass, aurPwdSymPass, aurPwdNumPass := password.Validate(aurPwd, pwdRs[0].AurPwdMinLen, pwdRs[0].AurPwdMaxLen, pwdRs[0].AurPwdIncSym, pwdRs[0].AurPwdIncNum)
+			vrls := Policy(pwdRs[0]).Check(ctx, aurPwd, nil)
 
 			data.ResultSet = &map[string]any{
-				"AurPwdLenPass" : aurPwdLenPass,
-				"AurPwdSymPass" : aurPwdSymPass,
-				"AurPwdNumPass" : aurPwdNumPass,
+				"AurPwdLenPass" : ! slices.Contains(vrls, password.VrlLen),
+				"AurPwdUprPass" : ! slices.Contains(vrls, password.VrlUpr),
+				"AurPwdLwrPass" : ! slices.Contains(vrls, password.VrlLwr),
+				"AurPwdSymPass" : ! slices.Contains(vrls, password.VrlSym),
+				"AurPwdNumPass" : ! slices.Contains(vrls, password.VrlNum),
 				"AurPwd2Pass"   : aurPwd == aurPwd2,
 			}
 
@@ -13,6 +13,7 @@ import (
 
 import (
 	"github.com/andrewah64/base-app-client/internal/common/core/db"
+	"github.com/andrewah64/base-app-client/internal/common/core/password"
 )
 
 type AurEaInf struct {
@@ -77,6 +78,10 @@ type PwdInf struct {
 	AurPwdMaxLen int
 	AurPwdIncSym bool
 	AurPwdIncNum bool
+	AurPwdIncUpr bool
+	AurPwdIncLwr bool
+	AurPwdSymSet string
+	AurPwdBrc    bool
 }
 
 func GetPwdInf (ctx *context.Context, logger *slog.Logger, conn *pgxpool.Conn, tntId int) ([]PwdInf, error) {
@@ -101,3 +106,17 @@ func GetPwdInf (ctx *context.Context, logger *slog.Logger, conn *pgxpool.Conn, t
 
 	return rs, rErr
 }
+
+// Policy returns the tenant's password policy.
+func Policy (inf PwdInf) password.Policy {
+	return password.Policy{
+		MinLen : inf.AurPwdMinLen,
+		MaxLen : inf.AurPwdMaxLen,
+		IncUpr : inf.AurPwdIncUpr,
+		IncLwr : inf.AurPwdIncLwr,
+		IncNum : inf.AurPwdIncNum,
+		IncSym : inf.AurPwdIncSym,
+		SymSet : inf.AurPwdSymSet,
+		Brc    : inf.AurPwdBrc,
+	}
+}
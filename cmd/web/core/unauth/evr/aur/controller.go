@@ -0,0 +1,125 @@
+package evr
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+)
+
+import (
+	"github.com/andrewah64/base-app-client/internal/common/core/session"
+	"github.com/andrewah64/base-app-client/internal/web/core/error"
+	"github.com/andrewah64/base-app-client/internal/web/core/ui/data/form"
+	"github.com/andrewah64/base-app-client/internal/web/core/ui/data/page"
+	"github.com/andrewah64/base-app-client/internal/web/core/ui/html"
+	"github.com/andrewah64/base-app-client/internal/web/core/ui/notification"
+	"github.com/andrewah64/base-app-client/internal/web/core/verify"
+)
+
+func Get (rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	ssd, ok := session.FromContext(ctx)
+	if ! ok {
+		error.IntSrv(ctx, rw, fmt.Errorf("Get::get request info"))
+		return
+	}
+
+	ssd.Logger.LogAttrs(ctx, slog.LevelDebug, "Get::start")
+
+	data, ok := page.FromContext(ctx)
+	if ! ok {
+		error.IntSrv(ctx, rw, fmt.Errorf("Get::get request data"))
+		return
+	}
+
+	p := r.URL.Query()
+
+	// only the notifications that redirects to this page send are shown, as
+	// an unknown id can't be translated
+	switch ntf := p.Get("ntf"); ntf {
+		case "web-core-unauth-evr-aur-page.message-pending", "web-core-unauth-evr-aur-page.message-idp-unverified":
+			notification.Toast(ctx, ssd.Logger, rw, r, "info", &map[string]string{"Message" : data.T(ntf)} , data)
+	}
+
+	html.Tmpl(ctx, ssd.Logger, rw, r, "core/unauth/evr/aur/content", http.StatusOK, &data)
+
+	ssd.Logger.LogAttrs(ctx, slog.LevelDebug, "Get::end")
+}
+
+// Post sends a new verification link to the address that was entered. The
+// response is the same whether or not a link was sent, so that the form can't
+// be used to find out which addresses are registered.
+func Post (rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	ssd, ok := session.FromContext(ctx)
+	if ! ok {
+		error.IntSrv(ctx, rw, fmt.Errorf("Post::get request info"))
+		return
+	}
+
+	ssd.Logger.LogAttrs(ctx, slog.LevelDebug, "Post::start")
+
+	data, ok := page.FromContext(ctx)
+	if ! ok {
+		error.IntSrv(ctx, rw, fmt.Errorf("Post::get request data"))
+		return
+	}
+
+	pfErr := r.ParseForm()
+	if pfErr != nil {
+		error.IntSrv(ctx, rw, pfErr)
+		return
+	}
+
+	aurEa := strings.TrimSpace(form.VText(r, "evr-aur-reg-aur-ea"))
+
+	ssd.Logger.LogAttrs(ctx, slog.LevelDebug, "Post::get data from form",
+		slog.String("aurEa" , aurEa),
+	)
+
+	if aurEa == "" {
+		notification.Toast(ctx, ssd.Logger, rw, r, "error" , &map[string]string{"Message" : data.T("web-core-unauth-evr-aur-reg-form.error-input-unexpected")}, data)
+
+		return
+	}
+
+	session.Identity(&ctx, ssd.Logger, ssd.Conn, "role_web_core_unauth_evr_aur_reg")
+
+	aurRs, aurRsErr := GetAurInf(&ctx, ssd.Logger, ssd.Conn, ssd.TntId, aurEa)
+	if aurRsErr != nil {
+		error.IntSrv(ctx, rw, aurRsErr)
+		return
+	}
+
+	ssd.Logger.LogAttrs(ctx, slog.LevelDebug, "Post::find unverified user",
+		slog.Int("len(aurRs)" , len(aurRs)),
+	)
+
+	if len(aurRs) == 1 && time.Since(aurRs[0].EvrLast) >= verify.Gap {
+		evrId, evrHsh, evrErr := verify.Token()
+		if evrErr != nil {
+			error.IntSrv(ctx, rw, evrErr)
+			return
+		}
+
+		msg, msgErr := verify.Message(ctx, r, data, aurRs[0].AurNm, aurEa, evrId)
+		if msgErr != nil {
+			error.IntSrv(ctx, rw, msgErr)
+			return
+		}
+
+		postErr := PostEvr(&ctx, ssd.Logger, ssd.Conn, ssd.TntId, aurRs[0].AurId, evrHsh, verify.Mins, msg.To, msg.Subject, msg.Text, msg.Html, nil)
+		if postErr != nil {
+			error.IntSrv(ctx, rw, postErr)
+			return
+		}
+	}
+
+	notification.Toast(ctx, ssd.Logger, rw, r, "success" , &map[string]string{"Message" : data.T("web-core-unauth-evr-aur-reg-form.message-success")}, data)
+
+	ssd.Logger.LogAttrs(ctx, slog.LevelDebug, "Post::end")
+}
@@ -0,0 +1,114 @@
+package id
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+)
+
+import (
+	"github.com/andrewah64/base-app-client/internal/common/core/session"
+	"github.com/andrewah64/base-app-client/internal/common/core/token"
+	"github.com/andrewah64/base-app-client/internal/web/core/error"
+	"github.com/andrewah64/base-app-client/internal/web/core/ui/data/page"
+	"github.com/andrewah64/base-app-client/internal/web/core/ui/html"
+	"github.com/andrewah64/base-app-client/internal/web/core/ui/notification"
+)
+
+import (
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// Get shows the address that is about to be verified. It is only verified
+// when the user confirms, so that mail scanners which follow links don't use
+// the token up.
+func Get (rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	ssd, ok := session.FromContext(ctx)
+	if ! ok {
+		error.IntSrv(ctx, rw, fmt.Errorf("Get::get request info"))
+		return
+	}
+
+	ssd.Logger.LogAttrs(ctx, slog.LevelDebug, "Get::start")
+
+	data, ok := page.FromContext(ctx)
+	if ! ok {
+		error.IntSrv(ctx, rw, fmt.Errorf("Get::get request data"))
+		return
+	}
+
+	evrId := r.PathValue("id")
+
+	session.Identity(&ctx, ssd.Logger, ssd.Conn, "role_web_core_unauth_evr_aur_mod")
+
+	evrRs, evrRsErr := GetEvrInf(&ctx, ssd.Logger, ssd.Conn, ssd.TntId, token.Hash(evrId))
+	if evrRsErr != nil {
+		error.IntSrv(ctx, rw, evrRsErr)
+		return
+	}
+
+	ssd.Logger.LogAttrs(ctx, slog.LevelDebug, "Get::retrieve datasets",
+		slog.Int("len(evrRs)" , len(evrRs)),
+	)
+
+	data.ResultSet = &map[string]any{
+		"Evr"   : &evrRs,
+		"EvrId" : &evrId,
+	}
+
+	html.Tmpl(ctx, ssd.Logger, rw, r, "core/unauth/evr/aur/id/content", http.StatusOK, &data)
+
+	ssd.Logger.LogAttrs(ctx, slog.LevelDebug, "Get::end")
+}
+
+func Post (rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	ssd, ok := session.FromContext(ctx)
+	if ! ok {
+		error.IntSrv(ctx, rw, fmt.Errorf("Post::get request info"))
+		return
+	}
+
+	ssd.Logger.LogAttrs(ctx, slog.LevelDebug, "Post::start")
+
+	data, ok := page.FromContext(ctx)
+	if ! ok {
+		error.IntSrv(ctx, rw, fmt.Errorf("Post::get request data"))
+		return
+	}
+
+	evrId := r.PathValue("id")
+
+	session.Identity(&ctx, ssd.Logger, ssd.Conn, "role_web_core_unauth_evr_aur_mod")
+
+	exptErrs := []string{
+		"EVRNF",
+	}
+
+	patchErr := PatchEvr(&ctx, ssd.Logger, ssd.Conn, ssd.TntId, token.Hash(evrId), exptErrs)
+	if patchErr != nil {
+		var pgErr *pgconn.PgError
+
+		if errors.As(patchErr, &pgErr) && pgErr.Code == "EVRNF" {
+			notification.Toast(ctx, ssd.Logger, rw, r, "error" , &map[string]string{"Message" : data.T("web-core-unauth-evr-aur-mod-form.error-evr-expired")}, data)
+
+			return
+		}
+
+		error.IntSrv(ctx, rw, patchErr)
+		return
+	}
+
+	html.Locate(rw, r, html.Location{
+		Path   : "/",
+		Target : "#main",
+		Select : "#content",
+		Values : map[string]string{"ntf": "web-core-unauth-evr-aur-mod-form.message-success"},
+	})
+
+	ssd.Logger.LogAttrs(ctx, slog.LevelDebug, "Post::end")
+}
@@ -0,0 +1,75 @@
+package id
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+)
+
+import (
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jackc/pgx/v5"
+)
+
+import (
+	"github.com/andrewah64/base-app-client/internal/common/core/db"
+)
+
+type EvrInf struct {
+	AurNm string
+	AurEa string
+}
+
+// GetEvrInf returns the user and address a verification token was sent to,
+// if the token has not expired or been used.
+func GetEvrInf (ctx *context.Context, logger *slog.Logger, conn *pgxpool.Conn, tntId int, evrHsh string) ([]EvrInf, error) {
+	const (
+		dbSchema = "web_core_unauth_evr_aur_mod"
+		dbFunc   = "evr_inf"
+	)
+
+	results, err := db.DataSet[EvrInf](ctx, logger, conn, func(ctx *context.Context, tx *pgx.Tx)(string, string, *pgx.Rows, error){
+		qry := fmt.Sprintf("select %v.%v($1, $2, $3)", dbSchema, dbFunc)
+
+		call, err := (*tx).Query(*ctx, qry, dbFunc, tntId, evrHsh)
+		if err != nil {
+			slog.LogAttrs(*ctx, slog.LevelError, "GetEvrInf::get dataset",
+				slog.String("error" , err.Error()),
+				slog.String("qry"   , qry),
+				slog.Int   ("tntId" , tntId),
+			)
+
+			return qry, dbFunc, nil, fmt.Errorf("GetEvrInf::call database function: %w", err)
+		}
+
+		return qry, dbFunc, &call, nil
+	})
+
+	return results, err
+}
+
+// PatchEvr marks the address the token was sent to as verified and uses the
+// token up. EVRNF is raised if the token has expired or been used.
+func PatchEvr (ctx *context.Context, logger *slog.Logger, conn *pgxpool.Conn, tntId int, evrHsh string, exptErrs []string) error {
+	var (
+		sprocCall   = "call web_core_unauth_evr_aur_mod.mod_evr(@p_tnt_id, @p_evr_hsh)"
+		sprocParams = pgx.NamedArgs{
+			"p_tnt_id"  : tntId,
+			"p_evr_hsh" : evrHsh,
+		}
+	)
+
+	sprocErr := db.Sproc(ctx, logger, conn, sprocCall, sprocParams, exptErrs)
+	if sprocErr != nil {
+		logger.LogAttrs(*ctx, slog.LevelDebug, "call sproc",
+			slog.String("sprocCall" , sprocCall),
+			slog.String("error"     , sprocErr.Error()),
+			slog.Int   ("tntId"     , tntId),
+			slog.Any   ("exptErrs"  , exptErrs),
+		)
+
+		return sprocErr
+	}
+
+	return nil
+}
@@ -15,10 +15,13 @@ import (
 )
 
 import (
+	   "github.com/andrewah64/base-app-client/internal/common/core/mail"
 	cs "github.com/andrewah64/base-app-client/internal/common/core/session"
 	t  "github.com/andrewah64/base-app-client/internal/common/core/token"
 	e  "github.com/andrewah64/base-app-client/internal/web/core/error"
 	ws "github.com/andrewah64/base-app-client/internal/web/core/session"
+	   "github.com/andrewah64/base-app-client/internal/web/core/ui/data/page"
+	   "github.com/andrewah64/base-app-client/internal/web/core/verify"
 )
 
 func callbackCookie(rw http.ResponseWriter, r *http.Request, name, value string) {
@@ -245,8 +248,19 @@ func Callback(rw http.ResponseWriter, r *http.Request){
 
 	aurEa := idTknMap["email"].(string)
 
+	// some providers send email_verified as a string
+	var aurEaVrf bool
+
+	switch v := idTknMap["email_verified"].(type) {
+		case bool:
+			aurEaVrf = v
+		case string:
+			aurEaVrf = v == "true"
+	}
+
 	ssd.Logger.LogAttrs(ctx, slog.LevelDebug, "Callback::get user's details from OIDC provider",
-		slog.String("aurEa" , aurEa),
+		slog.String("aurEa"    , aurEa),
+		slog.Bool  ("aurEaVrf" , aurEaVrf),
 	)
 
 	aurInfRs, aurInfRsErr := GetAurInf(&ctx, ssd.Logger, ssd.Conn, ssd.TntId, aurEa)
@@ -261,7 +275,34 @@ func Callback(rw http.ResponseWriter, r *http.Request){
 
 	switch len(aurInfRs) {
 		case 0:
-			regErr := RegAur (&ctx, ssd.Logger, ssd.Conn, ssd.TntId, aurEa, nil)
+			var (
+				evrHsh *string
+				msg    *mail.Message
+			)
+
+			if ! aurEaVrf {
+				data, ok := page.FromContext(ctx)
+				if ! ok {
+					e.IntSrv(ctx, rw, fmt.Errorf("Callback::get page data"))
+					return
+				}
+
+				evrId, evrTkn, evrErr := verify.Token()
+				if evrErr != nil {
+					e.IntSrv(ctx, rw, evrErr)
+					return
+				}
+
+				evrMsg, msgErr := verify.Message(ctx, r, data, aurEa, aurEa, evrId)
+				if msgErr != nil {
+					e.IntSrv(ctx, rw, msgErr)
+					return
+				}
+
+				evrHsh, msg = &evrTkn, &evrMsg
+			}
+
+			regErr := RegAur (&ctx, ssd.Logger, ssd.Conn, ssd.TntId, aurEa, aurEaVrf, evrHsh, verify.Mins, msg, nil)
 			if regErr != nil {
 				e.IntSrv(ctx, rw, regErr)
 				return
@@ -277,12 +318,42 @@ func Callback(rw http.ResponseWriter, r *http.Request){
 				slog.Int("len(aurInfRs)" , len(aurInfRs)),
 			)
 		case 1:
-			//the user was already registered
+			// an address the provider hasn't verified could belong to anyone, so
+			// it is never used to sign in to an account that already exists
+			if ! aurEaVrf {
+				ssd.Logger.LogAttrs(ctx, slog.LevelDebug, "Callback::provider has not verified the email address of an existing user",
+					slog.Int("aurInfRs[0].AurId", aurInfRs[0].AurId),
+				)
+
+				http.Redirect(rw, r, "/web/core/unauth/evr/aur?ntf=web-core-unauth-evr-aur-page.message-idp-unverified", http.StatusFound)
+
+				return
+			}
+
+			if ! aurInfRs[0].AurEaVrf {
+				vrfErr := PatchAurEaVrf(&ctx, ssd.Logger, ssd.Conn, ssd.TntId, aurInfRs[0].AurId, nil)
+				if vrfErr != nil {
+					e.IntSrv(ctx, rw, vrfErr)
+					return
+				}
+
+				aurInfRs[0].AurEaVrf, aurInfRs[0].AurEaVrfOk = true, true
+			}
 		default:
 			e.IntSrv(ctx, rw, fmt.Errorf("Callback::%v records were returned when only 0 or 1 are expected", len(aurInfRs)))
 			return
 	}
 
+	if ! aurInfRs[0].AurEaVrfOk {
+		ssd.Logger.LogAttrs(ctx, slog.LevelDebug, "Callback::email address is not verified",
+			slog.Int("aurInfRs[0].AurId", aurInfRs[0].AurId),
+		)
+
+		http.Redirect(rw, r, "/web/core/unauth/evr/aur?ntf=web-core-unauth-evr-aur-page.message-pending", http.StatusFound)
+
+		return
+	}
+
 	cookieExpiry := time.Now().Add(aurInfRs[0].SsnDn)
 
 	cs.Identity(&ctx, ssd.Logger, ssd.Conn, "role_web_core_unauth_ssn_aur_reg")
@@ -14,6 +14,7 @@ import (
 
 import (
 	"github.com/andrewah64/base-app-client/internal/common/core/db"
+	"github.com/andrewah64/base-app-client/internal/common/core/mail"
 )
 
 type CallInf struct {
@@ -82,6 +83,8 @@ type AurInf struct {
 	AurId           int
 	SsnDn           time.Duration
 	EppPt           string
+	AurEaVrf        bool
+	AurEaVrfOk      bool
 }
 
 func GetAurInf (ctx *context.Context, logger *slog.Logger, conn *pgxpool.Conn, tntId int, aurEa string) ([]AurInf, error) {
@@ -108,12 +111,29 @@ func GetAurInf (ctx *context.Context, logger *slog.Logger, conn *pgxpool.Conn, t
 	return rs, rErr
 }
 
-func RegAur (ctx *context.Context, logger *slog.Logger, conn *pgxpool.Conn, tntId int, aurEa string, exptErrs []string) error {
+// RegAur registers the user. Unless the identity provider has verified aurEa,
+// msg, which sends the link to verify it, is queued in the same transaction.
+func RegAur (ctx *context.Context, logger *slog.Logger, conn *pgxpool.Conn, tntId int, aurEa string, aurEaVrf bool, evrHsh *string, evrMins int, msg *mail.Message, exptErrs []string) error {
 	var (
-		sprocCall   = "call web_core_unauth_oidc_callback_mod.reg_aur(@p_tnt_id, @p_aur_ea)"
+		mobTo, mobSbj, mobTxt, mobHtm *string
+	)
+
+	if msg != nil {
+		mobTo, mobSbj, mobTxt, mobHtm = &msg.To, &msg.Subject, &msg.Text, &msg.Html
+	}
+
+	var (
+		sprocCall   = "call web_core_unauth_oidc_callback_mod.reg_aur(@p_tnt_id, @p_aur_ea, @p_aur_ea_vrf, @p_evr_hsh, @p_evr_mins, @p_mob_to, @p_mob_sbj, @p_mob_txt, @p_mob_htm)"
 		sprocParams = pgx.NamedArgs{
-			"p_tnt_id" : tntId,
-			"p_aur_ea" : aurEa,
+			"p_tnt_id"     : tntId,
+			"p_aur_ea"     : aurEa,
+			"p_aur_ea_vrf" : aurEaVrf,
+			"p_evr_hsh"    : evrHsh,
+			"p_evr_mins"   : evrMins,
+			"p_mob_to"     : mobTo,
+			"p_mob_sbj"    : mobSbj,
+			"p_mob_txt"    : mobTxt,
+			"p_mob_htm"    : mobHtm,
 		}
 	)
 
@@ -124,6 +144,34 @@ func RegAur (ctx *context.Context, logger *slog.Logger, conn *pgxpool.Conn, tntI
 			slog.String("error"     , sprocErr.Error()),
 			slog.Int   ("tntId"     , tntId),
 			slog.String("aurEa"     , aurEa),
+			slog.Bool  ("aurEaVrf"  , aurEaVrf),
+			slog.Any   ("exptErrs"  , exptErrs),
+		)
+
+		return sprocErr
+	}
+
+	return nil
+}
+
+// PatchAurEaVrf records that the identity provider has verified the email
+// address of a user who registered before it did.
+func PatchAurEaVrf (ctx *context.Context, logger *slog.Logger, conn *pgxpool.Conn, tntId int, aurId int, exptErrs []string) error {
+	var (
+		sprocCall   = "call web_core_unauth_oidc_callback_mod.mod_aur_ea_vrf(@p_tnt_id, @p_aur_id)"
+		sprocParams = pgx.NamedArgs{
+			"p_tnt_id" : tntId,
+			"p_aur_id" : aurId,
+		}
+	)
+
+	sprocErr := db.Sproc(ctx, logger, conn, sprocCall, sprocParams, exptErrs)
+	if sprocErr != nil {
+		logger.LogAttrs(*ctx, slog.LevelDebug, "call sproc",
+			slog.String("sprocCall" , sprocCall),
+			slog.String("error"     , sprocErr.Error()),
+			slog.Int   ("tntId"     , tntId),
+			slog.Int   ("aurId"     , aurId),
 			slog.Any   ("exptErrs"  , exptErrs),
 		)
 
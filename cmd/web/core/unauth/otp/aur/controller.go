@@ -14,16 +14,14 @@ import (
 	"github.com/andrewah64/base-app-client/internal/common/core/session"
 	"github.com/andrewah64/base-app-client/internal/common/core/tenant"
 	"github.com/andrewah64/base-app-client/internal/web/core/error"
+	"github.com/andrewah64/base-app-client/internal/web/core/mfa"
+	"github.com/andrewah64/base-app-client/internal/web/core/recovery"
 	"github.com/andrewah64/base-app-client/internal/web/core/ui/data/form"
 	"github.com/andrewah64/base-app-client/internal/web/core/ui/data/page"
 	"github.com/andrewah64/base-app-client/internal/web/core/ui/html"
 	"github.com/andrewah64/base-app-client/internal/web/core/ui/notification"
 )
 
-import (
-	"github.com/pquerna/otp/totp"
-)
-
 func Get (rw http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
@@ -56,10 +54,9 @@ func Get (rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	otpKey, otpKeyErr := totp.Generate(totp.GenerateOpts{
-		Issuer     : tenant.Origin(r),
-		AccountName: otpInfRs[0].AurNm,
-		Secret     : []byte(otpInfRs[0].OtpSecret),
+	otpKey, otpKeyErr := mfa.Key(tenant.Origin(r), otpInfRs[0].AurNm, []byte(otpInfRs[0].OtpSecret), mfa.Opts{
+		Digits : otpInfRs[0].AupcOtpDigits,
+		Alg    : otpInfRs[0].AupcOtpAlg,
 	})
 	if otpKeyErr != nil {
 		error.IntSrv(ctx, rw, otpKeyErr)
@@ -76,9 +73,10 @@ func Get (rw http.ResponseWriter, r *http.Request) {
 	imgStr := base64.StdEncoding.EncodeToString(buf.Bytes())
 
 	data.ResultSet = &map[string]any{
-		"AurId"  : &(otpInfRs[0].AurId),
-		"OtpImg" : &imgStr,
-		"OtpId"  : &otpId,
+		"AurId"     : &(otpInfRs[0].AurId),
+		"OtpImg"    : &imgStr,
+		"OtpId"     : &otpId,
+		"OtpDigits" : otpInfRs[0].AupcOtpDigits,
 	}
 
 	html.Tmpl(ctx, ssd.Logger, rw, r, "core/unauth/otp/aur/content", http.StatusOK, &data)
@@ -123,14 +121,31 @@ func Post (rw http.ResponseWriter, r *http.Request) {
 
 	otpSecret := base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString([]byte(otpInfRs[0].OtpSecret))
 
-	if totp.Validate(otpCd, otpSecret) {
-		postErr := PostOtp (&ctx, ssd.Logger, ssd.Conn, ssd.TntId, aurId, otpId, nil)
+	// the app was set up with the tenant's settings when the QR code was
+	// shown, and keeps them whatever the tenant changes later
+	otpOpts := mfa.Opts{
+		Skew   : otpInfRs[0].AupcOtpSkew,
+		Digits : otpInfRs[0].AupcOtpDigits,
+		Alg    : otpInfRs[0].AupcOtpAlg,
+	}
+
+	if otpStp, otpOk := mfa.Validate(otpCd, otpSecret, otpOpts, 0); otpOk {
+		rcvCds, rcvHshs, rcvErr := recovery.Codes()
+		if rcvErr != nil {
+			error.IntSrv(ctx, rw, rcvErr)
+			return
+		}
+
+		postErr := PostOtp (&ctx, ssd.Logger, ssd.Conn, ssd.TntId, aurId, otpId, otpOpts.Digits, otpOpts.Alg, otpStp, rcvHshs, nil)
 		if postErr != nil {
 			error.IntSrv(ctx, rw, postErr)
 			return
 		}
 
-		rw.Header().Set("HX-Location", `{"path":"/", "target":"#main", "select":"#content", "values":{"ntf": "web-core-unauth-otp-aur-mod-form.message-otp-cd-success"}}`)
+		// the codes are only ever shown here, the database keeps their hashes
+		data.ResultSet = &map[string]any{"RcvCds" : &rcvCds}
+
+		html.Fragment(ctx, ssd.Logger, rw, r, "core/unauth/otp/aur/fragment/rcv", http.StatusOK, &data)
 	} else {
 		notification.Toast(ctx, ssd.Logger, rw, r, "error" , &map[string]string{"Message" : data.T("web-core-unauth-otp-aur-mod-form.error-otp-cd")}, data)
 	}
@@ -16,7 +16,10 @@ import (
 )
 
 type OtpInf struct {
-	OtpSecret string
+	OtpSecret     string
+	AupcOtpSkew   int
+	AupcOtpDigits int
+	AupcOtpAlg    string
 }
 
 func GetOtpInf (ctx *context.Context, logger *slog.Logger, conn *pgxpool.Conn, tntId int, aurId int, otpId string) ([]OtpInf, error) {
@@ -48,9 +51,11 @@ func GetOtpInf (ctx *context.Context, logger *slog.Logger, conn *pgxpool.Conn, t
 }
 
 type OtpAurInf struct {
-	AurId     int
-	AurNm     string
-	OtpSecret string
+	AurId         int
+	AurNm         string
+	OtpSecret     string
+	AupcOtpDigits int
+	AupcOtpAlg    string
 }
 
 func GetOtpAurInf (ctx *context.Context, logger *slog.Logger, conn *pgxpool.Conn, tntId int, otpId string) ([]OtpAurInf, error) {
@@ -80,13 +85,21 @@ func GetOtpAurInf (ctx *context.Context, logger *slog.Logger, conn *pgxpool.Conn
 	return results, err
 }
 
-func PostOtp (ctx *context.Context, logger *slog.Logger, conn *pgxpool.Conn, tntId int, aurId int, otpId string, exptErrs []string) error {
+// PostOtp enables the user's TOTP with the digits and algorithm their
+// authenticator app was set up with, records otpStp as the time step of the
+// last code they used and replaces their recovery codes with the ones whose
+// hashes are rcvHshs.
+func PostOtp (ctx *context.Context, logger *slog.Logger, conn *pgxpool.Conn, tntId int, aurId int, otpId string, otpDigits int, otpAlg string, otpStp int64, rcvHshs []string, exptErrs []string) error {
 	var (
-		sprocCall   = "call web_core_unauth_otp_aur_mod.mod_otp(@p_tnt_id, @p_aur_id, @p_otp_id)"
+		sprocCall   = "call web_core_unauth_otp_aur_mod.mod_otp(@p_tnt_id, @p_aur_id, @p_otp_id, @p_otp_digits, @p_otp_alg, @p_otp_stp, @p_rcv_hsh)"
 		sprocParams = pgx.NamedArgs{
 			"p_tnt_id"     : tntId,
 			"p_aur_id"     : aurId,
 			"p_otp_id"     : otpId,
+			"p_otp_digits" : otpDigits,
+			"p_otp_alg"    : otpAlg,
+			"p_otp_stp"    : otpStp,
+			"p_rcv_hsh"    : rcvHshs,
 		}
 	)
 
@@ -98,6 +111,8 @@ func PostOtp (ctx *context.Context, logger *slog.Logger, conn *pgxpool.Conn, tnt
 			slog.Int   ("tntId"     , tntId),
 			slog.Int   ("aurId"     , aurId),
 			slog.String("otpId"     , otpId),
+			slog.Int   ("otpDigits" , otpDigits),
+			slog.String("otpAlg"    , otpAlg),
 			slog.Any   ("exptErrs"  , exptErrs),
 		)
 
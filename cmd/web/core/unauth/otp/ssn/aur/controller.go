@@ -2,6 +2,8 @@ package otp
 
 import (
 	"encoding/base32"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
 	"net/http"
@@ -13,6 +15,10 @@ import (
 	   "github.com/andrewah64/base-app-client/internal/common/core/tenant"
 	   "github.com/andrewah64/base-app-client/internal/common/core/token"
 	   "github.com/andrewah64/base-app-client/internal/web/core/error"
+	   "github.com/andrewah64/base-app-client/internal/web/core/lockout"
+	   "github.com/andrewah64/base-app-client/internal/web/core/mfa"
+	   "github.com/andrewah64/base-app-client/internal/web/core/passkey"
+	   "github.com/andrewah64/base-app-client/internal/web/core/recovery"
 	ws "github.com/andrewah64/base-app-client/internal/web/core/session"
 	   "github.com/andrewah64/base-app-client/internal/web/core/ui/data/form"
 	   "github.com/andrewah64/base-app-client/internal/web/core/ui/data/page"
@@ -20,10 +26,20 @@ import (
 	   "github.com/andrewah64/base-app-client/internal/web/core/ui/notification"
 )
 
+import (
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
 import (
 	"github.com/pquerna/otp/totp"
 )
 
+import (
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/protocol/webauthncose"
+	"github.com/go-webauthn/webauthn/webauthn"
+)
+
 func Get(rw http.ResponseWriter, r *http.Request){
 	ctx := r.Context()
 
@@ -56,46 +72,69 @@ func Get(rw http.ResponseWriter, r *http.Request){
 		return
 	}
 
-	if nncRs[0].NncEnabled && nncRs[0].OtpEnabled {
-		data.ResultSet = &map[string]any{"AurId" : nncRs[0].AurId, "NncNonce" : nncNonce}
+	if ! nncRs[0].NncEnabled {
+		html.Locate(rw, r, html.Location{
+			Path   : "/",
+			Target : "#main",
+			Select : "#content",
+			Values : map[string]string{"ntf": "web-core-unauth-otp-ssn-aur-mod-form.error-timeout"},
+		})
 
-		html.Tmpl(ctx, ssd.Logger, rw, r, "core/unauth/otp/ssn/aur/content", http.StatusOK, &data)
-	} else {
-		if ! nncRs[0].NncEnabled {
-			rw.Header().Set("HX-Location", `{"path":"/", "target":"#main", "select":"#content", "values":{"ntf": "web-core-unauth-otp-ssn-aur-mod-form.error-timeout"}}`)
+		return
+	}
 
-			return
-		}
+	// the factors the user has enrolled that the tenant accepts
+	otpOk := mfa.Otp(nncRs[0].AupcMfaNm) && nncRs[0].OtpEnabled
+	pkyOk := mfa.Pky(nncRs[0].AupcMfaNm) && nncRs[0].PkyEnabled
 
-		if ! nncRs[0].OtpEnabled {
-			otpId , otpIdErr := token.Token(32)
-			if otpIdErr != nil {
-				error.IntSrv(ctx, rw, otpIdErr)
-				return
-			}
+	ssd.Logger.LogAttrs(ctx, slog.LevelDebug, "Get::offer second factors",
+		slog.String("aupcMfaNm" , nncRs[0].AupcMfaNm),
+		slog.Bool  ("otpOk"     , otpOk),
+		slog.Bool  ("pkyOk"     , pkyOk),
+	)
 
-			otpTotpSecret, otpTotpSecretErr := totp.Generate(totp.GenerateOpts{
-				Issuer     : tenant.Origin(r),
-				AccountName: nncRs[0].AurNm,
-			})
+	if otpOk || pkyOk {
+		data.ResultSet = &map[string]any{"AurId" : nncRs[0].AurId, "NncNonce" : nncNonce, "OtpDigits" : nncRs[0].OtpDigits, "Otp" : otpOk, "Pky" : pkyOk}
 
-			if otpTotpSecretErr != nil {
-				error.IntSrv(ctx, rw, otpTotpSecretErr)
-				return
-			}
+		html.Tmpl(ctx, ssd.Logger, rw, r, "core/unauth/otp/ssn/aur/content", http.StatusOK, &data)
+	} else if mfa.Otp(nncRs[0].AupcMfaNm) {
+		otpId , otpIdErr := token.Token(32)
+		if otpIdErr != nil {
+			error.IntSrv(ctx, rw, otpIdErr)
+			return
+		}
 
-			otpSecret := otpTotpSecret.Secret()
+		otpTotpSecret, otpTotpSecretErr := totp.Generate(totp.GenerateOpts{
+			Issuer     : tenant.Origin(r),
+			AccountName: nncRs[0].AurNm,
+		})
 
-			regErr := PostOtp(&ctx, ssd.Logger, ssd.Conn, ssd.TntId, nncRs[0].AurId, otpId, otpSecret, nil)
-			if regErr != nil {
-				error.IntSrv(ctx, rw, regErr)
-				return
-			}
+		if otpTotpSecretErr != nil {
+			error.IntSrv(ctx, rw, otpTotpSecretErr)
+			return
+		}
 
-			rw.Header().Set("HX-Location", fmt.Sprintf(`{"path":"/web/core/unauth/otp/aur/%v", "target":"#main", "select":"#content"}`, otpId))
+		otpSecret := otpTotpSecret.Secret()
 
+		regErr := PostOtp(&ctx, ssd.Logger, ssd.Conn, ssd.TntId, nncRs[0].AurId, otpId, otpSecret, nil)
+		if regErr != nil {
+			error.IntSrv(ctx, rw, regErr)
 			return
 		}
+
+		html.Locate(rw, r, html.Location{
+			Path   : fmt.Sprintf("/web/core/unauth/otp/aur/%v", otpId),
+			Target : "#main",
+			Select : "#content",
+		})
+
+		return
+	} else {
+		// the tenant requires a passkey the user hasn't got, so they add
+		// one now, like a user without an app is given a TOTP above
+		data.ResultSet = &map[string]any{"AurId" : nncRs[0].AurId, "NncNonce" : nncNonce, "Otp" : false, "Pky" : false, "PkyReg" : true}
+
+		html.Tmpl(ctx, ssd.Logger, rw, r, "core/unauth/otp/ssn/aur/content", http.StatusOK, &data)
 	}
 
 	ssd.Logger.LogAttrs(ctx, slog.LevelDebug, "Get::end")
@@ -125,36 +164,577 @@ func Post(rw http.ResponseWriter, r *http.Request){
 	}
 
 	nncNonce := r.PathValue("id")
-	aurId    := form.VInt (r, "otp-ssn-aur-mod-aur-id")
-	otpCd    := form.VText(r, "otp-ssn-aur-mod-otp-cd")
 
-	cs.Identity(&ctx, ssd.Logger, ssd.Conn, "role_web_core_unauth_otp_ssn_aur_mod")
+	switch r.PathValue("stp") {
+		case "otp":
+			aurId    := form.VInt (r, "otp-ssn-aur-mod-aur-id")
+			otpCd    := form.VText(r, "otp-ssn-aur-mod-otp-cd")
+			rcvCd    := form.VText(r, "otp-ssn-aur-mod-rcv-cd")
 
-	aurRs, aurRsErr := GetAurInf(&ctx, ssd.Logger, ssd.Conn, ssd.TntId, aurId, nncNonce)
-	if aurRsErr != nil {
-		error.IntSrv(ctx, rw, aurRsErr)
-		return
-	}
+			cs.Identity(&ctx, ssd.Logger, ssd.Conn, "role_web_core_unauth_otp_ssn_aur_mod")
 
-	otpSecret := base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString([]byte(aurRs[0].OtpSecret))
+			aurRs, aurRsErr := GetAurInf(&ctx, ssd.Logger, ssd.Conn, ssd.TntId, aurId, nncNonce)
+			if aurRsErr != nil {
+				error.IntSrv(ctx, rw, aurRsErr)
+				return
+			}
 
-	if totp.Validate(otpCd, otpSecret) {
-		cs.Identity(&ctx, ssd.Logger, ssd.Conn, "role_web_core_unauth_ssn_aur_reg")
+			if len(aurRs) != 1 || ! mfa.Otp(aurRs[0].AupcMfaNm) {
+				notification.Toast(ctx, ssd.Logger, rw, r, "error" , &map[string]string{"Message" : data.T("web-core-unauth-otp-ssn-aur-mod-form.error-otp-cd")}, data)
+				return
+			}
 
-		cookieExpiry := time.Now().Add(aurRs[0].AurSsnDn)
+			otpSecret := base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString([]byte(aurRs[0].OtpSecret))
+
+			valid := false
+
+			if rcvCd == "" && ! aurRs[0].FlrLocked {
+				// codes are made with the digits and algorithm the user's
+				// app was set up with, not the tenant's current settings
+				otpOpts := mfa.Opts{
+					Skew   : aurRs[0].AupcOtpSkew,
+					Digits : aurRs[0].OtpDigits,
+					Alg    : aurRs[0].OtpAlg,
+				}
+
+				otpStp, otpOk := mfa.Validate(otpCd, otpSecret, otpOpts, aurRs[0].OtpStp)
+
+				if otpOk {
+					// another request may have used the same code since it was read
+					exptErrs := []string{
+						"OTPRP",
+					}
+
+					patchErr := PatchOtpStp(&ctx, ssd.Logger, ssd.Conn, ssd.TntId, aurId, otpStp, exptErrs)
+					if patchErr != nil {
+						var pgErr *pgconn.PgError
+
+						if ! errors.As(patchErr, &pgErr) || pgErr.Code != "OTPRP" {
+							error.IntSrv(ctx, rw, patchErr)
+							return
+						}
+					}
+
+					valid = patchErr == nil
+				}
+			} else if rcvCd != "" && ! aurRs[0].FlrLocked {
+				// a recovery code is used up by the same call that finds it, so
+				// not while the user is locked out
+				exptErrs := []string{
+					"RCVNF",
+				}
+
+				delErr := DelRcv(&ctx, ssd.Logger, ssd.Conn, ssd.TntId, aurId, recovery.Hash(rcvCd), exptErrs)
+				if delErr != nil {
+					var pgErr *pgconn.PgError
+
+					if ! errors.As(delErr, &pgErr) || pgErr.Code != "RCVNF" {
+						error.IntSrv(ctx, rw, delErr)
+						return
+					}
+				}
+
+				valid = delErr == nil
+			}
 
-		ssnErr := ws.Begin(&ctx, ssd.Logger, ssd.Conn, rw, aurId, cookieExpiry)
-		if ssnErr != nil {
-			error.IntSrv(ctx, rw, ssnErr)
-			return
-		}
+			if valid && ! aurRs[0].FlrLocked {
+				if aurRs[0].FlrCnt > 0 {
+					delErr := DelFlr(&ctx, ssd.Logger, ssd.Conn, ssd.TntId, aurId, nil)
+					if delErr != nil {
+						error.IntSrv(ctx, rw, delErr)
+						return
+					}
+				}
+
+				cs.Identity(&ctx, ssd.Logger, ssd.Conn, "role_web_core_unauth_ssn_aur_reg")
+
+				cookieExpiry := time.Now().Add(aurRs[0].AurSsnDn)
+
+				ssnErr := ws.Begin(&ctx, ssd.Logger, ssd.Conn, rw, aurId, cookieExpiry)
+				if ssnErr != nil {
+					error.IntSrv(ctx, rw, ssnErr)
+					return
+				}
+
+				ssd.Logger.LogAttrs(ctx, slog.LevelDebug, "Post::redirect to user's home page",
+					slog.String("aurRs[0].EppPt", aurRs[0].EppPt),
+				)
+
+				html.Redirect(rw, r, aurRs[0].EppPt)
+			} else {
+				ssd.Logger.LogAttrs(ctx, slog.LevelDebug, "Post::otp is invalid",
+					slog.Int ("aurId"  , aurId),
+					slog.Bool("locked" , aurRs[0].FlrLocked),
+					slog.Bool("rcv"    , rcvCd != ""),
+				)
+
+				flrErr := PostFlr(&ctx, ssd.Logger, ssd.Conn, ssd.TntId, aurId, nil)
+				if flrErr != nil {
+					error.IntSrv(ctx, rw, flrErr)
+					return
+				}
+
+				lockout.Delay(ctx, ssd.Conn, time.Duration(aurRs[0].AupcDlyMs) * time.Millisecond, aurRs[0].FlrCnt)
+
+				if rcvCd != "" {
+					notification.Toast(ctx, ssd.Logger, rw, r, "error" , &map[string]string{"Message" : data.T("web-core-unauth-otp-ssn-aur-mod-form.error-rcv-cd")}, data)
+				} else {
+					notification.Toast(ctx, ssd.Logger, rw, r, "error" , &map[string]string{"Message" : data.T("web-core-unauth-otp-ssn-aur-mod-form.error-otp-cd")}, data)
+				}
+			}
 
-		ssd.Logger.LogAttrs(ctx, slog.LevelDebug, "Post::redirect to user's home page",
-			slog.String("aurRs[0].EppPt", aurRs[0].EppPt),
-		)
+		case "pky-bgn":
+			cs.Identity(&ctx, ssd.Logger, ssd.Conn, "role_web_core_unauth_otp_ssn_aur_mod")
 
-		rw.Header().Set("HX-Redirect", aurRs[0].EppPt)
-	} else {
-		notification.Toast(ctx, ssd.Logger, rw, r, "error" , &map[string]string{"Message" : data.T("web-core-unauth-otp-ssn-aur-mod-form.error-otp-cd")}, data)
+			nncRs, nncRsErr := GetNncInf(&ctx, ssd.Logger, ssd.Conn, ssd.TntId, nncNonce)
+			if nncRsErr != nil {
+				error.IntSrv(ctx, rw, nncRsErr)
+				return
+			}
+
+			if len(nncRs) != 1 {
+				error.Status(ctx, rw, http.StatusNotFound)
+				return
+			}
+
+			aurId := nncRs[0].AurId
+
+			aurRs, aurRsErr := GetAurInf(&ctx, ssd.Logger, ssd.Conn, ssd.TntId, aurId, nncNonce)
+			if aurRsErr != nil {
+				error.IntSrv(ctx, rw, aurRsErr)
+				return
+			}
+
+			if len(aurRs) != 1 || ! mfa.Pky(aurRs[0].AupcMfaNm) || aurRs[0].FlrLocked {
+				notification.Toast(ctx, ssd.Logger, rw, r, "error" , &map[string]string{"Message" : data.T("web-core-unauth-otp-ssn-aur-mod-form.error-pky")}, data)
+				return
+			}
+
+			pkyAur, pkyAurErr := GetPkyAur(&ctx, ssd.Logger, ssd.Conn, ssd.TntId, aurId, aurRs[0].AurNm)
+			if pkyAurErr != nil {
+				error.IntSrv(ctx, rw, pkyAurErr)
+				return
+			}
+
+			if len(pkyAur.Credentials) == 0 {
+				notification.Toast(ctx, ssd.Logger, rw, r, "error" , &map[string]string{"Message" : data.T("web-core-unauth-otp-ssn-aur-mod-form.error-pky")}, data)
+				return
+			}
+
+			aukcAtnInfRs, aukcAtnInfRsErr := GetAukcAtnInf(&ctx, ssd.Logger, ssd.Conn, ssd.TntId)
+			if aukcAtnInfRsErr != nil {
+				error.IntSrv(ctx, rw, aukcAtnInfRsErr)
+				return
+			}
+
+			var pkh []protocol.PublicKeyCredentialHints = make([]protocol.PublicKeyCredentialHints, len(aukcAtnInfRs[0].PkhNm))
+			for i, v := range aukcAtnInfRs[0].PkhNm {
+				pkh[i] = protocol.PublicKeyCredentialHints(v)
+			}
+
+			atnOpts := []webauthn.LoginOption {
+				webauthn.WithUserVerification(protocol.UserVerificationRequirement(aukcAtnInfRs[0].PuvNm)),
+				webauthn.WithAssertionPublicKeyCredentialHints(pkh),
+			}
+
+			c, s, blErr := passkey.WebAuthn(&ctx, ssd.Logger, ssd.TntId).BeginLogin(pkyAur, atnOpts...)
+			if blErr != nil {
+				error.IntSrv(ctx, rw, blErr)
+				return
+			}
+
+			sd, sdErr := json.Marshal(s)
+			if sdErr != nil {
+				error.IntSrv(ctx, rw, sdErr)
+				return
+			}
+
+			regErr := PostPls(&ctx, ssd.Logger, ssd.Conn, ssd.TntId, aurId, nncNonce, s.Challenge, sd, nil)
+			if regErr != nil {
+				error.IntSrv(ctx, rw, regErr)
+				return
+			}
+
+			rw.Header().Set("Content-Type", "application/json")
+
+			json.NewEncoder(rw).Encode(c)
+		case "pky-end":
+			cs.Identity(&ctx, ssd.Logger, ssd.Conn, "role_web_core_unauth_otp_ssn_aur_mod")
+
+			nncRs, nncRsErr := GetNncInf(&ctx, ssd.Logger, ssd.Conn, ssd.TntId, nncNonce)
+			if nncRsErr != nil {
+				error.IntSrv(ctx, rw, nncRsErr)
+				return
+			}
+
+			if len(nncRs) != 1 {
+				error.Status(ctx, rw, http.StatusNotFound)
+				return
+			}
+
+			aurId := nncRs[0].AurId
+
+			aurRs, aurRsErr := GetAurInf(&ctx, ssd.Logger, ssd.Conn, ssd.TntId, aurId, nncNonce)
+			if aurRsErr != nil {
+				error.IntSrv(ctx, rw, aurRsErr)
+				return
+			}
+
+			if len(aurRs) != 1 || ! mfa.Pky(aurRs[0].AupcMfaNm) {
+				notification.ToastStatus(ctx, ssd.Logger, rw, r, "error" , &map[string]string{"Message" : data.T("web-core-unauth-otp-ssn-aur-mod-form.error-pky")}, http.StatusUnprocessableEntity, data)
+				return
+			}
+
+			pkyAur, pkyAurErr := GetPkyAur(&ctx, ssd.Logger, ssd.Conn, ssd.TntId, aurId, aurRs[0].AurNm)
+			if pkyAurErr != nil {
+				error.IntSrv(ctx, rw, pkyAurErr)
+				return
+			}
+
+			pR, pRErr := protocol.ParseCredentialRequestResponse(r)
+			if pRErr != nil {
+				error.IntSrv(ctx, rw, pRErr)
+				return
+			}
+
+			plsRs, plsRsErr := GetPlsInf(&ctx, ssd.Logger, ssd.Conn, ssd.TntId, aurId, nncNonce, pR.Response.CollectedClientData.Challenge)
+			if plsRsErr != nil {
+				error.IntSrv(ctx, rw, plsRsErr)
+				return
+			}
+
+			if len(plsRs) != 1 {
+				notification.ToastStatus(ctx, ssd.Logger, rw, r, "error" , &map[string]string{"Message" : data.T("web-core-unauth-otp-ssn-aur-mod-form.error-pky")}, http.StatusUnprocessableEntity, data)
+				return
+			}
+
+			var sd webauthn.SessionData
+			sdErr := json.Unmarshal(plsRs[0].PlsJs, &sd)
+			if sdErr != nil {
+				error.IntSrv(ctx, rw, sdErr)
+				return
+			}
+
+			var c *webauthn.Credential
+
+			// a locked out user can't finish signing in, whatever passkey
+			// they use
+			if ! aurRs[0].FlrLocked {
+				vc, vlErr := passkey.WebAuthn(&ctx, ssd.Logger, ssd.TntId).ValidateLogin(pkyAur, sd, pR)
+				if vlErr != nil {
+					ssd.Logger.LogAttrs(ctx, slog.LevelDebug, "Post::validate passkey",
+						slog.String("error" , vlErr.Error()),
+						slog.Int   ("aurId" , aurId),
+					)
+				} else {
+					c = vc
+				}
+			}
+
+			if c == nil {
+				ssd.Logger.LogAttrs(ctx, slog.LevelDebug, "Post::passkey is invalid",
+					slog.Int   ("aurId"  , aurId),
+					slog.Bool  ("locked" , aurRs[0].FlrLocked),
+				)
+
+				flrErr := PostFlr(&ctx, ssd.Logger, ssd.Conn, ssd.TntId, aurId, nil)
+				if flrErr != nil {
+					error.IntSrv(ctx, rw, flrErr)
+					return
+				}
+
+				lockout.Delay(ctx, ssd.Conn, time.Duration(aurRs[0].AupcDlyMs) * time.Millisecond, aurRs[0].FlrCnt)
+
+				notification.ToastStatus(ctx, ssd.Logger, rw, r, "error" , &map[string]string{"Message" : data.T("web-core-unauth-otp-ssn-aur-mod-form.error-pky")}, http.StatusUnprocessableEntity, data)
+				return
+			}
+
+			pkyErr := PatchPky(&ctx, ssd.Logger, ssd.Conn, ssd.TntId, aurId, c.ID, int(c.Authenticator.SignCount), c.Flags.BackupState, c.Authenticator.CloneWarning, nil)
+			if pkyErr != nil {
+				error.IntSrv(ctx, rw, pkyErr)
+				return
+			}
+
+			if aurRs[0].FlrCnt > 0 {
+				delErr := DelFlr(&ctx, ssd.Logger, ssd.Conn, ssd.TntId, aurId, nil)
+				if delErr != nil {
+					error.IntSrv(ctx, rw, delErr)
+					return
+				}
+			}
+
+			cs.Identity(&ctx, ssd.Logger, ssd.Conn, "role_web_core_unauth_ssn_aur_reg")
+
+			cookieExpiry := time.Now().Add(aurRs[0].AurSsnDn)
+
+			ssnErr := ws.Begin(&ctx, ssd.Logger, ssd.Conn, rw, aurId, cookieExpiry)
+			if ssnErr != nil {
+				error.IntSrv(ctx, rw, ssnErr)
+				return
+			}
+
+			ssd.Logger.LogAttrs(ctx, slog.LevelDebug, "Post::redirect to user's home page",
+				slog.String("aurRs[0].EppPt", aurRs[0].EppPt),
+			)
+
+			rw.Header().Set("Content-Type", "application/json")
+
+			json.NewEncoder(rw).Encode(struct {EppPt string `json:"eppPt"`}{EppPt : aurRs[0].EppPt})
+
+		case "pky-reg-bgn":
+			cs.Identity(&ctx, ssd.Logger, ssd.Conn, "role_web_core_unauth_otp_ssn_aur_mod")
+
+			nncRs, nncRsErr := GetNncInf(&ctx, ssd.Logger, ssd.Conn, ssd.TntId, nncNonce)
+			if nncRsErr != nil {
+				error.IntSrv(ctx, rw, nncRsErr)
+				return
+			}
+
+			if len(nncRs) != 1 {
+				error.Status(ctx, rw, http.StatusNotFound)
+				return
+			}
+
+			aurId := nncRs[0].AurId
+
+			// only a user without a passkey may add one before signing in,
+			// anyone else has to use the one they have
+			if ! nncRs[0].NncEnabled || ! mfa.Pky(nncRs[0].AupcMfaNm) || nncRs[0].PkyEnabled {
+				notification.Toast(ctx, ssd.Logger, rw, r, "error" , &map[string]string{"Message" : data.T("web-core-unauth-otp-ssn-aur-mod-form.error-pky")}, data)
+				return
+			}
+
+			aurRs, aurRsErr := GetAurInf(&ctx, ssd.Logger, ssd.Conn, ssd.TntId, aurId, nncNonce)
+			if aurRsErr != nil {
+				error.IntSrv(ctx, rw, aurRsErr)
+				return
+			}
+
+			if len(aurRs) != 1 || aurRs[0].FlrLocked {
+				notification.Toast(ctx, ssd.Logger, rw, r, "error" , &map[string]string{"Message" : data.T("web-core-unauth-otp-ssn-aur-mod-form.error-pky")}, data)
+				return
+			}
+
+			user := &passkey.User{
+				Id          : []byte(aurRs[0].AurNm),
+				Name        : aurRs[0].AurNm,
+				DisplayName : aurRs[0].AurNm,
+			}
+
+			aukcRegInfRs, aukcRegInfRsErr := GetAukcRegInf(&ctx, ssd.Logger, ssd.Conn, ssd.TntId)
+			if aukcRegInfRsErr != nil {
+				error.IntSrv(ctx, rw, aukcRegInfRsErr)
+				return
+			}
+
+			var pka protocol.ConveyancePreference = protocol.ConveyancePreference(aukcRegInfRs[0].PkaNm)
+
+			var pkg []protocol.CredentialParameter = make([]protocol.CredentialParameter, len(aukcRegInfRs[0].PkgCd))
+			for i, v := range aukcRegInfRs[0].PkgCd {
+				pkg[i] = protocol.CredentialParameter{
+					Type      : protocol.PublicKeyCredentialType,
+					Algorithm : webauthncose.COSEAlgorithmIdentifier(v),
+				}
+			}
+
+			var pkh []protocol.PublicKeyCredentialHints = make([]protocol.PublicKeyCredentialHints, len(aukcRegInfRs[0].PkhNm))
+			for i, v := range aukcRegInfRs[0].PkhNm {
+				pkh[i] = protocol.PublicKeyCredentialHints(v)
+			}
+
+			// older authenticators only know requireResidentKey, which must
+			// agree with the tenant's resident key setting
+			rrk := aukcRegInfRs[0].PdcNm == string(protocol.ResidentKeyRequirementRequired)
+
+			regOpts := []webauthn.RegistrationOption {
+				webauthn.WithAuthenticatorSelection(
+					protocol.AuthenticatorSelection {
+						AuthenticatorAttachment : protocol.AuthenticatorAttachment(aukcRegInfRs[0].PktNm),
+						RequireResidentKey      : &rrk,
+						ResidentKey             : protocol.ResidentKeyRequirement(aukcRegInfRs[0].PdcNm),
+						UserVerification        : protocol.UserVerificationRequirement(aukcRegInfRs[0].PuvNm),
+					},
+				),
+				webauthn.WithConveyancePreference(pka),
+				webauthn.WithCredentialParameters(pkg),
+				webauthn.WithPublicKeyCredentialHints(pkh),
+			}
+
+			c, s, brErr := passkey.WebAuthn(&ctx, ssd.Logger, ssd.TntId).BeginRegistration(user, regOpts...)
+			if brErr != nil {
+				error.IntSrv(ctx, rw, brErr)
+				return
+			}
+
+			sd, sdErr := json.Marshal(s)
+			if sdErr != nil {
+				error.IntSrv(ctx, rw, sdErr)
+				return
+			}
+
+			regErr := PostPrs(&ctx, ssd.Logger, ssd.Conn, ssd.TntId, aurId, nncNonce, sd, nil)
+			if regErr != nil {
+				error.IntSrv(ctx, rw, regErr)
+				return
+			}
+
+			rw.Header().Set("Content-Type", "application/json")
+
+			json.NewEncoder(rw).Encode(c)
+		case "pky-reg-end":
+			cs.Identity(&ctx, ssd.Logger, ssd.Conn, "role_web_core_unauth_otp_ssn_aur_mod")
+
+			nncRs, nncRsErr := GetNncInf(&ctx, ssd.Logger, ssd.Conn, ssd.TntId, nncNonce)
+			if nncRsErr != nil {
+				error.IntSrv(ctx, rw, nncRsErr)
+				return
+			}
+
+			if len(nncRs) != 1 {
+				error.Status(ctx, rw, http.StatusNotFound)
+				return
+			}
+
+			aurId := nncRs[0].AurId
+
+			if ! nncRs[0].NncEnabled || ! mfa.Pky(nncRs[0].AupcMfaNm) || nncRs[0].PkyEnabled {
+				notification.ToastStatus(ctx, ssd.Logger, rw, r, "error" , &map[string]string{"Message" : data.T("web-core-unauth-otp-ssn-aur-mod-form.error-pky")}, http.StatusUnprocessableEntity, data)
+				return
+			}
+
+			aurRs, aurRsErr := GetAurInf(&ctx, ssd.Logger, ssd.Conn, ssd.TntId, aurId, nncNonce)
+			if aurRsErr != nil {
+				error.IntSrv(ctx, rw, aurRsErr)
+				return
+			}
+
+			if len(aurRs) != 1 || aurRs[0].FlrLocked {
+				notification.ToastStatus(ctx, ssd.Logger, rw, r, "error" , &map[string]string{"Message" : data.T("web-core-unauth-otp-ssn-aur-mod-form.error-pky")}, http.StatusUnprocessableEntity, data)
+				return
+			}
+
+			prsRs, prsRsErr := GetPrsInf(&ctx, ssd.Logger, ssd.Conn, ssd.TntId, aurId, nncNonce)
+			if prsRsErr != nil {
+				error.IntSrv(ctx, rw, prsRsErr)
+				return
+			}
+
+			if len(prsRs) != 1 {
+				notification.ToastStatus(ctx, ssd.Logger, rw, r, "error" , &map[string]string{"Message" : data.T("web-core-unauth-otp-ssn-aur-mod-form.error-pky")}, http.StatusUnprocessableEntity, data)
+				return
+			}
+
+			var sd webauthn.SessionData
+			sdErr := json.Unmarshal(prsRs[0].PrsJs, &sd)
+			if sdErr != nil {
+				error.IntSrv(ctx, rw, sdErr)
+				return
+			}
+
+			user := &passkey.User{
+				Id          : []byte(aurRs[0].AurNm),
+				Name        : aurRs[0].AurNm,
+				DisplayName : aurRs[0].AurNm,
+			}
+
+			aukcRegInfRs, aukcRegInfRsErr := GetAukcRegInf(&ctx, ssd.Logger, ssd.Conn, ssd.TntId)
+			if aukcRegInfRsErr != nil {
+				error.IntSrv(ctx, rw, aukcRegInfRsErr)
+				return
+			}
+
+			c, frErr := passkey.FinishRegistration(&ctx, ssd.Logger, ssd.TntId, aukcRegInfRs[0].Policy(), user, sd, r)
+			if frErr != nil {
+				ssd.Logger.LogAttrs(ctx, slog.LevelDebug, "Post::finish passkey registration",
+					slog.String("error"    , frErr.Error()),
+					slog.Int   ("aurId"    , aurId),
+					slog.Bool  ("rejected" , passkey.Rejected(frErr)),
+				)
+
+				if passkey.Rejected(frErr) {
+					notification.ToastStatus(ctx, ssd.Logger, rw, r, "error" , &map[string]string{"Message" : data.T("web-core-unauth-otp-ssn-aur-mod-form.error-pky-rejected")}, http.StatusUnprocessableEntity, data)
+				} else {
+					notification.ToastStatus(ctx, ssd.Logger, rw, r, "error" , &map[string]string{"Message" : data.T("web-core-unauth-otp-ssn-aur-mod-form.error-pky")}, http.StatusUnprocessableEntity, data)
+				}
+
+				return
+			}
+
+			var t []string = make([]string, len(c.Transport))
+			for i, v := range c.Transport {
+				t[i] = string(v)
+			}
+
+			// another request may have added a passkey since it was checked
+			exptErrs := []string{
+				"PKYEX",
+			}
+
+			regErr := PostPky(
+				&ctx,
+				ssd.Logger,
+				ssd.Conn,
+				ssd.TntId,
+				aurId,
+				passkey.Name(c.Authenticator.AAGUID),
+				c.ID,
+				c.PublicKey,
+				c.AttestationType,
+				t,
+				c.Flags.UserPresent,
+				c.Flags.UserVerified,
+				c.Flags.BackupEligible,
+				c.Flags.BackupState,
+				c.Authenticator.AAGUID,
+				int(c.Authenticator.SignCount),
+				c.Authenticator.CloneWarning,
+				string(c.Authenticator.Attachment),
+				c.Attestation.ClientDataJSON,
+				c.Attestation.ClientDataHash,
+				c.Attestation.AuthenticatorData,
+				c.Attestation.PublicKeyAlgorithm,
+				c.Attestation.Object,
+				exptErrs,
+			)
+			if regErr != nil {
+				var pgErr *pgconn.PgError
+
+				if errors.As(regErr, &pgErr) && pgErr.Code == "PKYEX" {
+					notification.ToastStatus(ctx, ssd.Logger, rw, r, "error" , &map[string]string{"Message" : data.T("web-core-unauth-otp-ssn-aur-mod-form.error-pky")}, http.StatusUnprocessableEntity, data)
+					return
+				}
+
+				error.IntSrv(ctx, rw, regErr)
+				return
+			}
+
+			if aurRs[0].FlrCnt > 0 {
+				delErr := DelFlr(&ctx, ssd.Logger, ssd.Conn, ssd.TntId, aurId, nil)
+				if delErr != nil {
+					error.IntSrv(ctx, rw, delErr)
+					return
+				}
+			}
+
+			cs.Identity(&ctx, ssd.Logger, ssd.Conn, "role_web_core_unauth_ssn_aur_reg")
+
+			cookieExpiry := time.Now().Add(aurRs[0].AurSsnDn)
+
+			ssnErr := ws.Begin(&ctx, ssd.Logger, ssd.Conn, rw, aurId, cookieExpiry)
+			if ssnErr != nil {
+				error.IntSrv(ctx, rw, ssnErr)
+				return
+			}
+
+			ssd.Logger.LogAttrs(ctx, slog.LevelDebug, "Post::redirect to user's home page",
+				slog.String("aurRs[0].EppPt", aurRs[0].EppPt),
+			)
+
+			rw.Header().Set("Content-Type", "application/json")
+
+			json.NewEncoder(rw).Encode(struct {EppPt string `json:"eppPt"`}{EppPt : aurRs[0].EppPt})
+
+		default:
+			error.Status(ctx, rw, http.StatusNotFound)
 	}
 }
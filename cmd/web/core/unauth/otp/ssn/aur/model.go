@@ -14,12 +14,27 @@ import (
 
 import (
 	"github.com/andrewah64/base-app-client/internal/common/core/db"
+	"github.com/andrewah64/base-app-client/internal/web/core/passkey"
+)
+
+import (
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/webauthn"
 )
 
 type AurInf struct {
-	AurSsnDn  time.Duration
-	EppPt     string
-	OtpSecret string
+	AurSsnDn      time.Duration
+	EppPt         string
+	OtpSecret     string
+	FlrCnt        int
+	FlrLocked     bool
+	AupcDlyMs     int
+	OtpStp        int64
+	AupcOtpSkew   int
+	OtpDigits     int
+	OtpAlg        string
+	AupcMfaNm     string
+	AurNm         string
 }
 
 func GetAurInf (ctx *context.Context, logger *slog.Logger, conn *pgxpool.Conn, tntId int, aurId int, nncNonce string) ([]AurInf, error) {
@@ -51,10 +66,13 @@ func GetAurInf (ctx *context.Context, logger *slog.Logger, conn *pgxpool.Conn, t
 }
 
 type NncInf struct {
-	AurId      int
-	AurNm      string
-	NncEnabled bool
-	OtpEnabled bool
+	AurId         int
+	AurNm         string
+	NncEnabled    bool
+	OtpEnabled    bool
+	OtpDigits     int
+	AupcMfaNm     string
+	PkyEnabled    bool
 }
 
 func GetNncInf (ctx *context.Context, logger *slog.Logger, conn *pgxpool.Conn, tntId int, nncNonce string) ([]NncInf, error) {
@@ -84,6 +102,257 @@ func GetNncInf (ctx *context.Context, logger *slog.Logger, conn *pgxpool.Conn, t
 	return results, err
 }
 
+type AukcAtnInf struct {
+	PuvNm   string
+	PkhNm []string
+}
+
+func GetAukcAtnInf (ctx *context.Context, logger *slog.Logger, conn *pgxpool.Conn, tntId int) ([]AukcAtnInf, error) {
+	const (
+		dbSchema = "web_core_unauth_otp_ssn_aur_mod"
+		dbFunc   = "aukc_atn_inf"
+	)
+
+	results, err := db.DataSet[AukcAtnInf](ctx, logger, conn, func(ctx *context.Context, tx *pgx.Tx)(string, string, *pgx.Rows, error){
+		qry := fmt.Sprintf("select %v.%v($1, $2)", dbSchema, dbFunc)
+
+		call, err := (*tx).Query(*ctx, qry, dbFunc, tntId)
+		if err != nil {
+			slog.LogAttrs(*ctx, slog.LevelError, "GetAukcAtnInf::get dataset",
+				slog.String("error"   , err.Error()),
+				slog.String("qry"     , qry),
+				slog.Int   ("tntId"   , tntId),
+			)
+
+			return qry, dbFunc, nil, fmt.Errorf("GetAukcAtnInf::call database function: %w", err)
+		}
+
+		return qry, dbFunc, &call, nil
+	})
+
+	return results, err
+}
+
+type AukcRegInf struct {
+	PkaNm   string
+	PktNm   string
+	PdcNm   string
+	PuvNm   string
+	PkgCd []int
+	PkhNm []string
+	PapNm   string
+	PalNm   string
+	Aaguid []string
+}
+
+// Policy is the tenant's rules for which authenticators a passkey may be
+// registered on.
+func (a AukcRegInf) Policy() passkey.Policy {
+	return passkey.Policy{
+		PapNm  : a.PapNm,
+		PalNm  : a.PalNm,
+		Aaguid : a.Aaguid,
+	}
+}
+
+func GetAukcRegInf (ctx *context.Context, logger *slog.Logger, conn *pgxpool.Conn, tntId int) ([]AukcRegInf, error) {
+	const (
+		dbSchema = "web_core_unauth_otp_ssn_aur_mod"
+		dbFunc   = "aukc_reg_inf"
+	)
+
+	results, err := db.DataSet[AukcRegInf](ctx, logger, conn, func(ctx *context.Context, tx *pgx.Tx)(string, string, *pgx.Rows, error){
+		qry := fmt.Sprintf("select %v.%v($1, $2)", dbSchema, dbFunc)
+
+		call, err := (*tx).Query(*ctx, qry, dbFunc, tntId)
+		if err != nil {
+			slog.LogAttrs(*ctx, slog.LevelError, "GetAukcRegInf::get dataset",
+				slog.String("error"   , err.Error()),
+				slog.String("qry"     , qry),
+				slog.Int   ("tntId"   , tntId),
+			)
+
+			return qry, dbFunc, nil, fmt.Errorf("GetAukcRegInf::call database function: %w", err)
+		}
+
+		return qry, dbFunc, &call, nil
+	})
+
+	return results, err
+}
+
+type PkyInf struct {
+	PkyCredentialId           []byte
+	PkyPublicKey              []byte
+	PkyAttestationType          string
+	PkyAuthenticatorTransport []string
+	PkyUserPresent              bool
+	PkyUserVerified             bool
+	PkyBackupEligible           bool
+	PkyBackupState              bool
+	PkyAaguid                 []byte
+	PkySignCount                int
+	PkyCloneWarning             bool
+	PkyAttachment               string
+	PkyClientDataJson         []byte
+	PkyClientDataHash         []byte
+	PkyAuthenticatorData      []byte
+	PkyPublicKeyAlgorithm       int64
+	PkyObject                 []byte
+}
+
+func GetPkyInf (ctx *context.Context, logger *slog.Logger, conn *pgxpool.Conn, tntId int, aurId int) ([]PkyInf, error) {
+	const (
+		dbSchema = "web_core_unauth_otp_ssn_aur_mod"
+		dbFunc   = "pky_inf"
+	)
+
+	results, err := db.DataSet[PkyInf](ctx, logger, conn, func(ctx *context.Context, tx *pgx.Tx)(string, string, *pgx.Rows, error){
+		qry := fmt.Sprintf("select %v.%v($1, $2, $3)", dbSchema, dbFunc)
+
+		call, err := (*tx).Query(*ctx, qry, dbFunc, tntId, aurId)
+		if err != nil {
+			slog.LogAttrs(*ctx, slog.LevelError, "GetPkyInf::get dataset",
+				slog.String("error"   , err.Error()),
+				slog.String("qry"     , qry),
+				slog.Int   ("tntId"   , tntId),
+				slog.Int   ("aurId"   , aurId),
+			)
+
+			return qry, dbFunc, nil, fmt.Errorf("GetPkyInf::call database function: %w", err)
+		}
+
+		return qry, dbFunc, &call, nil
+	})
+
+	return results, err
+}
+
+// GetPkyAur returns the user with the passkeys they can use as their second
+// factor. Its id is the username, which is what the passkeys were made for.
+func GetPkyAur (ctx *context.Context, logger *slog.Logger, conn *pgxpool.Conn, tntId int, aurId int, aurNm string) (*passkey.User, error) {
+	pkyRs, pkyRsErr := GetPkyInf(ctx, logger, conn, tntId, aurId)
+	if pkyRsErr != nil {
+		return nil, fmt.Errorf("GetPkyAur::get passkeys: %w", pkyRsErr)
+	}
+
+	var credentials []webauthn.Credential = make([]webauthn.Credential, len(pkyRs))
+	for i, v := range pkyRs {
+		var t []protocol.AuthenticatorTransport = make([]protocol.AuthenticatorTransport, len(v.PkyAuthenticatorTransport))
+		for i, v := range v.PkyAuthenticatorTransport {
+			t[i] = protocol.AuthenticatorTransport(v)
+		}
+
+		flags := webauthn.CredentialFlags {
+			UserPresent    : v.PkyUserPresent,
+			UserVerified   : v.PkyUserVerified,
+			BackupEligible : v.PkyBackupEligible,
+			BackupState    : v.PkyBackupState,
+		}
+
+		authn := webauthn.Authenticator {
+			AAGUID       : v.PkyAaguid,
+			SignCount    : uint32(v.PkySignCount),
+			CloneWarning : v.PkyCloneWarning,
+			Attachment   : protocol.AuthenticatorAttachment(v.PkyAttachment),
+		}
+
+		attsn := webauthn.CredentialAttestation {
+			ClientDataJSON     : v.PkyClientDataJson,
+			ClientDataHash     : v.PkyClientDataHash,
+			AuthenticatorData  : v.PkyAuthenticatorData,
+			PublicKeyAlgorithm : v.PkyPublicKeyAlgorithm,
+			Object             : v.PkyObject,
+		}
+
+		credentials[i] = webauthn.Credential {
+			ID              : v.PkyCredentialId,
+			PublicKey       : v.PkyPublicKey,
+			AttestationType : v.PkyAttestationType,
+			Transport       : t,
+			Flags           : flags,
+			Authenticator   : authn,
+			Attestation     : attsn,
+		}
+	}
+
+	user := &passkey.User{
+		Id          : protocol.URLEncodedBase64(aurNm),
+		Name        : aurNm,
+		DisplayName : aurNm,
+		Credentials : credentials,
+	}
+
+	return user, nil
+}
+
+type PlsInf struct {
+	PlsJs []byte
+}
+
+func GetPlsInf (ctx *context.Context, logger *slog.Logger, conn *pgxpool.Conn, tntId int, aurId int, nncNonce string, pkyChallenge string) ([]PlsInf, error) {
+	const (
+		dbSchema = "web_core_unauth_otp_ssn_aur_mod"
+		dbFunc   = "pls_inf"
+	)
+
+	results, err := db.DataSet[PlsInf](ctx, logger, conn, func(ctx *context.Context, tx *pgx.Tx)(string, string, *pgx.Rows, error){
+		qry := fmt.Sprintf("select %v.%v($1, $2, $3, $4, $5)", dbSchema, dbFunc)
+
+		call, err := (*tx).Query(*ctx, qry, dbFunc, tntId, aurId, nncNonce, pkyChallenge)
+		if err != nil {
+			slog.LogAttrs(*ctx, slog.LevelError, "GetPlsInf::get dataset",
+				slog.String("error"        , err.Error()),
+				slog.String("qry"          , qry),
+				slog.Int   ("tntId"        , tntId),
+				slog.Int   ("aurId"        , aurId),
+				slog.String("nncNonce"     , nncNonce),
+				slog.String("pkyChallenge" , pkyChallenge),
+			)
+
+			return qry, dbFunc, nil, fmt.Errorf("GetPlsInf::call database function: %w", err)
+		}
+
+		return qry, dbFunc, &call, nil
+	})
+
+	return results, err
+}
+
+type PrsInf struct {
+	PrsJs []byte
+}
+
+// GetPrsInf returns the session data of the passkey being registered by a
+// user who has none, during the sign in whose nonce is nncNonce.
+func GetPrsInf (ctx *context.Context, logger *slog.Logger, conn *pgxpool.Conn, tntId int, aurId int, nncNonce string) ([]PrsInf, error) {
+	const (
+		dbSchema = "web_core_unauth_otp_ssn_aur_mod"
+		dbFunc   = "prs_inf"
+	)
+
+	results, err := db.DataSet[PrsInf](ctx, logger, conn, func(ctx *context.Context, tx *pgx.Tx)(string, string, *pgx.Rows, error){
+		qry := fmt.Sprintf("select %v.%v($1, $2, $3, $4)", dbSchema, dbFunc)
+
+		call, err := (*tx).Query(*ctx, qry, dbFunc, tntId, aurId, nncNonce)
+		if err != nil {
+			slog.LogAttrs(*ctx, slog.LevelError, "GetPrsInf::get dataset",
+				slog.String("error"    , err.Error()),
+				slog.String("qry"      , qry),
+				slog.Int   ("tntId"    , tntId),
+				slog.Int   ("aurId"    , aurId),
+				slog.String("nncNonce" , nncNonce),
+			)
+
+			return qry, dbFunc, nil, fmt.Errorf("GetPrsInf::call database function: %w", err)
+		}
+
+		return qry, dbFunc, &call, nil
+	})
+
+	return results, err
+}
+
 func PostOtp (ctx *context.Context, logger *slog.Logger, conn *pgxpool.Conn, tntId int, aurId int, otpId string, otpSecret string, exptErrs []string) error {
 	var (
 		sprocCall   = "call web_core_unauth_otp_ssn_aur_mod.reg_otp(@p_tnt_id, @p_aur_id, @p_otp_id, @p_otp_secret)"
@@ -111,3 +380,257 @@ func PostOtp (ctx *context.Context, logger *slog.Logger, conn *pgxpool.Conn, tnt
 
 	return nil
 }
+
+func PostFlr (ctx *context.Context, logger *slog.Logger, conn *pgxpool.Conn, tntId int, aurId int, exptErrs []string) error {
+	var (
+		sprocCall   = "call web_core_unauth_otp_ssn_aur_mod.reg_flr(@p_tnt_id, @p_aur_id)"
+		sprocParams = pgx.NamedArgs{
+			"p_tnt_id" : tntId,
+			"p_aur_id" : aurId,
+		}
+	)
+
+	sprocErr := db.Sproc(ctx, logger, conn, sprocCall, sprocParams, exptErrs)
+	if sprocErr != nil {
+		logger.LogAttrs(*ctx, slog.LevelDebug, "call sproc",
+			slog.String("sprocCall" , sprocCall),
+			slog.String("error"     , sprocErr.Error()),
+			slog.Int   ("tntId"     , tntId),
+			slog.Int   ("aurId"     , aurId),
+			slog.Any   ("exptErrs"  , exptErrs),
+		)
+
+		return sprocErr
+	}
+
+	return nil
+}
+
+func DelFlr (ctx *context.Context, logger *slog.Logger, conn *pgxpool.Conn, tntId int, aurId int, exptErrs []string) error {
+	var (
+		sprocCall   = "call web_core_unauth_otp_ssn_aur_mod.del_flr(@p_tnt_id, @p_aur_id)"
+		sprocParams = pgx.NamedArgs{
+			"p_tnt_id" : tntId,
+			"p_aur_id" : aurId,
+		}
+	)
+
+	sprocErr := db.Sproc(ctx, logger, conn, sprocCall, sprocParams, exptErrs)
+	if sprocErr != nil {
+		logger.LogAttrs(*ctx, slog.LevelDebug, "call sproc",
+			slog.String("sprocCall" , sprocCall),
+			slog.String("error"     , sprocErr.Error()),
+			slog.Int   ("tntId"     , tntId),
+			slog.Int   ("aurId"     , aurId),
+			slog.Any   ("exptErrs"  , exptErrs),
+		)
+
+		return sprocErr
+	}
+
+	return nil
+}
+
+// DelRcv uses up the recovery code whose hash is rcvHsh. RCVNF is raised if
+// the user has no such code.
+func DelRcv (ctx *context.Context, logger *slog.Logger, conn *pgxpool.Conn, tntId int, aurId int, rcvHsh string, exptErrs []string) error {
+	var (
+		sprocCall   = "call web_core_unauth_otp_ssn_aur_mod.del_rcv(@p_tnt_id, @p_aur_id, @p_rcv_hsh)"
+		sprocParams = pgx.NamedArgs{
+			"p_tnt_id"  : tntId,
+			"p_aur_id"  : aurId,
+			"p_rcv_hsh" : rcvHsh,
+		}
+	)
+
+	sprocErr := db.Sproc(ctx, logger, conn, sprocCall, sprocParams, exptErrs)
+	if sprocErr != nil {
+		logger.LogAttrs(*ctx, slog.LevelDebug, "call sproc",
+			slog.String("sprocCall" , sprocCall),
+			slog.String("error"     , sprocErr.Error()),
+			slog.Int   ("tntId"     , tntId),
+			slog.Int   ("aurId"     , aurId),
+			slog.Any   ("exptErrs"  , exptErrs),
+		)
+
+		return sprocErr
+	}
+
+	return nil
+}
+
+// PatchOtpStp records otpStp as the time step of the last code the user
+// signed in with. OTPRP is raised if a code for that step, or a later one,
+// has been used since it was checked.
+func PatchOtpStp (ctx *context.Context, logger *slog.Logger, conn *pgxpool.Conn, tntId int, aurId int, otpStp int64, exptErrs []string) error {
+	var (
+		sprocCall   = "call web_core_unauth_otp_ssn_aur_mod.mod_otp_stp(@p_tnt_id, @p_aur_id, @p_otp_stp)"
+		sprocParams = pgx.NamedArgs{
+			"p_tnt_id"  : tntId,
+			"p_aur_id"  : aurId,
+			"p_otp_stp" : otpStp,
+		}
+	)
+
+	sprocErr := db.Sproc(ctx, logger, conn, sprocCall, sprocParams, exptErrs)
+	if sprocErr != nil {
+		logger.LogAttrs(*ctx, slog.LevelDebug, "call sproc",
+			slog.String("sprocCall" , sprocCall),
+			slog.String("error"     , sprocErr.Error()),
+			slog.Int   ("tntId"     , tntId),
+			slog.Int   ("aurId"     , aurId),
+			slog.Int64 ("otpStp"    , otpStp),
+			slog.Any   ("exptErrs"  , exptErrs),
+		)
+
+		return sprocErr
+	}
+
+	return nil
+}
+
+// PostPls keeps the session data of a passkey being used as the second
+// factor, tied to the nonce of the sign in it finishes.
+func PostPls (ctx *context.Context, logger *slog.Logger, conn *pgxpool.Conn, tntId int, aurId int, nncNonce string, plsChallenge string, plsJs []byte, exptErrs []string) error {
+	var (
+		sprocCall   = "call web_core_unauth_otp_ssn_aur_mod.reg_pls(@p_tnt_id, @p_aur_id, @p_nnc_nonce, @p_pls_challenge, @p_pls_js)"
+		sprocParams = pgx.NamedArgs{
+			"p_tnt_id"        : tntId,
+			"p_aur_id"        : aurId,
+			"p_nnc_nonce"     : nncNonce,
+			"p_pls_challenge" : plsChallenge,
+			"p_pls_js"        : plsJs,
+		}
+	)
+
+	sprocErr := db.Sproc(ctx, logger, conn, sprocCall, sprocParams, exptErrs)
+	if sprocErr != nil {
+		logger.LogAttrs(*ctx, slog.LevelDebug, "call sproc",
+			slog.String("sprocCall"    , sprocCall),
+			slog.String("error"        , sprocErr.Error()),
+			slog.Int   ("tntId"        , tntId),
+			slog.Int   ("aurId"        , aurId),
+			slog.String("nncNonce"     , nncNonce),
+			slog.String("plsChallenge" , plsChallenge),
+			slog.Any   ("exptErrs"     , exptErrs),
+		)
+
+		return sprocErr
+	}
+
+	return nil
+}
+
+// PatchPky records that the passkey was just used to sign in, with the sign
+// count, backup state and clone warning its authenticator reported.
+func PatchPky (ctx *context.Context, logger *slog.Logger, conn *pgxpool.Conn, tntId int, aurId int, pkyCredentialId []byte, pkySignCount int, pkyBackupState bool, pkyCloneWarning bool, exptErrs []string) error {
+	var (
+		sprocCall   = "call web_core_unauth_otp_ssn_aur_mod.mod_pky(@p_tnt_id, @p_aur_id, @p_pky_credential_id, @p_pky_sign_count, @p_pky_backup_state, @p_pky_clone_warning)"
+		sprocParams = pgx.NamedArgs{
+			"p_tnt_id"            : tntId,
+			"p_aur_id"            : aurId,
+			"p_pky_credential_id" : pkyCredentialId,
+			"p_pky_sign_count"    : pkySignCount,
+			"p_pky_backup_state"  : pkyBackupState,
+			"p_pky_clone_warning" : pkyCloneWarning,
+		}
+	)
+
+	sprocErr := db.Sproc(ctx, logger, conn, sprocCall, sprocParams, exptErrs)
+	if sprocErr != nil {
+		logger.LogAttrs(*ctx, slog.LevelDebug, "call sproc",
+			slog.String("sprocCall"       , sprocCall),
+			slog.String("error"           , sprocErr.Error()),
+			slog.Int   ("tntId"           , tntId),
+			slog.Int   ("aurId"           , aurId),
+			slog.Any   ("pkyCredentialId" , pkyCredentialId),
+			slog.Int   ("pkySignCount"    , pkySignCount),
+			slog.Bool  ("pkyBackupState"  , pkyBackupState),
+			slog.Bool  ("pkyCloneWarning" , pkyCloneWarning),
+			slog.Any   ("exptErrs"        , exptErrs),
+		)
+
+		return sprocErr
+	}
+
+	return nil
+}
+
+// PostPrs keeps the session data of the passkey a user who has none is
+// registering, tied to the nonce of the sign in it finishes.
+func PostPrs (ctx *context.Context, logger *slog.Logger, conn *pgxpool.Conn, tntId int, aurId int, nncNonce string, prsJs []byte, exptErrs []string) error {
+	var (
+		sprocCall   = "call web_core_unauth_otp_ssn_aur_mod.reg_prs(@p_tnt_id, @p_aur_id, @p_nnc_nonce, @p_prs_js)"
+		sprocParams = pgx.NamedArgs{
+			"p_tnt_id"    : tntId,
+			"p_aur_id"    : aurId,
+			"p_nnc_nonce" : nncNonce,
+			"p_prs_js"    : prsJs,
+		}
+	)
+
+	sprocErr := db.Sproc(ctx, logger, conn, sprocCall, sprocParams, exptErrs)
+	if sprocErr != nil {
+		logger.LogAttrs(*ctx, slog.LevelDebug, "call sproc",
+			slog.String("sprocCall" , sprocCall),
+			slog.String("error"     , sprocErr.Error()),
+			slog.Int   ("tntId"     , tntId),
+			slog.Int   ("aurId"     , aurId),
+			slog.String("nncNonce"  , nncNonce),
+			slog.Any   ("exptErrs"  , exptErrs),
+		)
+
+		return sprocErr
+	}
+
+	return nil
+}
+
+// PostPky adds the first passkey of a user who has none. PKYEX is raised if
+// they have added one since it was checked.
+func PostPky (ctx *context.Context, logger *slog.Logger, conn *pgxpool.Conn, tntId int, aurId int, pkyNm string, pkyCredentialId []byte, pkyPublicKey []byte, pkyAttestationType string, pkyAuthenticatorTransport []string, pkyUserPresent bool, pkyUserVerified bool, pkyBackupEligible bool, pkyBackupState bool, pkyAaguid []byte, pkySignCount int, pkyCloneWarning bool, pkyAttachment string, pkyClientDataJson []byte, pkyClientDataHash []byte, pkyAuthenticatorData []byte, pkyPublicKeyAlgorithm int64, pkyObject []byte, exptErrs []string) error {
+	var (
+		sprocCall   = "call web_core_unauth_otp_ssn_aur_mod.reg_pky(@p_tnt_id, @p_aur_id, @p_pky_nm, @p_pky_credential_id, @p_pky_public_key, @p_pky_attestation_type, @p_pky_authenticator_transport, @p_pky_user_present, @p_pky_user_verified, @p_pky_backup_eligible, @p_pky_backup_state, @p_pky_aaguid, @p_pky_sign_count, @p_pky_clone_warning, @p_pky_attachment, @p_pky_client_data_json, @p_pky_client_data_hash, @p_pky_authenticator_data, @p_pky_public_key_algorithm, @p_pky_object)"
+		sprocParams = pgx.NamedArgs{
+			"p_tnt_id"                      : tntId,
+			"p_aur_id"                      : aurId,
+			"p_pky_nm"                      : pkyNm,
+			"p_pky_credential_id"           : pkyCredentialId,
+			"p_pky_public_key"              : pkyPublicKey,
+			"p_pky_attestation_type"        : pkyAttestationType,
+			"p_pky_authenticator_transport" : pkyAuthenticatorTransport,
+			"p_pky_user_present"            : pkyUserPresent,
+			"p_pky_user_verified"           : pkyUserVerified,
+			"p_pky_backup_eligible"         : pkyBackupEligible,
+			"p_pky_backup_state"            : pkyBackupState,
+			"p_pky_aaguid"                  : pkyAaguid,
+			"p_pky_sign_count"              : pkySignCount,
+			"p_pky_clone_warning"           : pkyCloneWarning,
+			"p_pky_attachment"              : pkyAttachment,
+			"p_pky_client_data_json"        : pkyClientDataJson,
+			"p_pky_client_data_hash"        : pkyClientDataHash,
+			"p_pky_authenticator_data"      : pkyAuthenticatorData,
+			"p_pky_public_key_algorithm"    : pkyPublicKeyAlgorithm,
+			"p_pky_object"                  : pkyObject,
+		}
+	)
+
+	sprocErr := db.Sproc(ctx, logger, conn, sprocCall, sprocParams, exptErrs)
+	if sprocErr != nil {
+		logger.LogAttrs(*ctx, slog.LevelDebug, "call sproc",
+			slog.String("sprocCall"          , sprocCall),
+			slog.String("error"              , sprocErr.Error()),
+			slog.Int   ("tntId"              , tntId),
+			slog.Int   ("aurId"              , aurId),
+			slog.String("pkyNm"              , pkyNm),
+			slog.Any   ("pkyCredentialId"    , pkyCredentialId),
+			slog.String("pkyAttestationType" , pkyAttestationType),
+			slog.Any   ("pkyAaguid"          , pkyAaguid),
+			slog.Any   ("exptErrs"           , exptErrs),
+		)
+
+		return sprocErr
+	}
+
+	return nil
+}
@@ -0,0 +1,137 @@
+package pwd
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+import (
+	"github.com/andrewah64/base-app-client/internal/common/core/session"
+	"github.com/andrewah64/base-app-client/internal/common/core/tenant"
+	"github.com/andrewah64/base-app-client/internal/common/core/token"
+	"github.com/andrewah64/base-app-client/internal/web/core/error"
+	"github.com/andrewah64/base-app-client/internal/web/core/ui/data/form"
+	"github.com/andrewah64/base-app-client/internal/web/core/ui/data/page"
+	"github.com/andrewah64/base-app-client/internal/web/core/ui/html"
+	"github.com/andrewah64/base-app-client/internal/web/core/ui/notification"
+)
+
+const (
+	pwrMins = 30
+)
+
+func Get (rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	ssd, ok := session.FromContext(ctx)
+	if ! ok {
+		error.IntSrv(ctx, rw, fmt.Errorf("Get::get request info"))
+		return
+	}
+
+	ssd.Logger.LogAttrs(ctx, slog.LevelDebug, "Get::start")
+
+	data, ok := page.FromContext(ctx)
+	if ! ok {
+		error.IntSrv(ctx, rw, fmt.Errorf("Get::get request data"))
+		return
+	}
+
+	html.Tmpl(ctx, ssd.Logger, rw, r, "core/unauth/pwd/aur/content", http.StatusOK, &data)
+
+	ssd.Logger.LogAttrs(ctx, slog.LevelDebug, "Get::end")
+}
+
+// Post emails a password reset link to the user with the address that was
+// entered. The response is the same whether or not there is such a user, so
+// that the form can't be used to find out which addresses are registered.
+func Post (rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	ssd, ok := session.FromContext(ctx)
+	if ! ok {
+		error.IntSrv(ctx, rw, fmt.Errorf("Post::get request info"))
+		return
+	}
+
+	ssd.Logger.LogAttrs(ctx, slog.LevelDebug, "Post::start")
+
+	data, ok := page.FromContext(ctx)
+	if ! ok {
+		error.IntSrv(ctx, rw, fmt.Errorf("Post::get request data"))
+		return
+	}
+
+	pfErr := r.ParseForm()
+	if pfErr != nil {
+		error.IntSrv(ctx, rw, pfErr)
+		return
+	}
+
+	aurEa := strings.TrimSpace(form.VText(r, "pwd-aur-reg-aur-ea"))
+
+	ssd.Logger.LogAttrs(ctx, slog.LevelDebug, "Post::get data from form",
+		slog.String("aurEa" , aurEa),
+	)
+
+	if aurEa == "" {
+		notification.Toast(ctx, ssd.Logger, rw, r, "error" , &map[string]string{"Message" : data.T("web-core-unauth-pwd-aur-reg-form.error-input-unexpected")}, data)
+
+		return
+	}
+
+	session.Identity(&ctx, ssd.Logger, ssd.Conn, "role_web_core_unauth_pwd_aur_reg")
+
+	aurRs, aurRsErr := GetAurInf(&ctx, ssd.Logger, ssd.Conn, ssd.TntId, aurEa)
+	if aurRsErr != nil {
+		error.IntSrv(ctx, rw, aurRsErr)
+		return
+	}
+
+	ssd.Logger.LogAttrs(ctx, slog.LevelDebug, "Post::find user",
+		slog.Int("len(aurRs)" , len(aurRs)),
+	)
+
+	if len(aurRs) == 1 {
+		pwrId, pwrIdErr := token.Token(32)
+		if pwrIdErr != nil {
+			error.IntSrv(ctx, rw, pwrIdErr)
+			return
+		}
+
+		var (
+			link = fmt.Sprintf("%v/web/core/unauth/pwd/aur/%v", tenant.Origin(r), pwrId)
+			mins = strconv.Itoa(pwrMins)
+		)
+
+		data.ResultSet = &map[string]any{
+			"AurNm" : aurRs[0].AurNm,
+			"Link"  : link,
+			"Mins"  : mins,
+		}
+
+		mobHtm, mobHtmErr := html.Render(ctx, "core/unauth/pwd/aur/fragment/mail", data)
+		if mobHtmErr != nil {
+			error.IntSrv(ctx, rw, mobHtmErr)
+			return
+		}
+
+		var (
+			mobSbj = data.T("web-core-unauth-pwd-aur-mail.subject")
+			mobTxt = data.T("web-core-unauth-pwd-aur-mail.text", "aurNm", aurRs[0].AurNm, "link", link, "mins", mins)
+		)
+
+		postErr := PostPwr(&ctx, ssd.Logger, ssd.Conn, ssd.TntId, aurRs[0].AurId, token.Hash(pwrId), pwrMins, aurEa, mobSbj, mobTxt, string(mobHtm), nil)
+		if postErr != nil {
+			error.IntSrv(ctx, rw, postErr)
+			return
+		}
+	}
+
+	notification.Toast(ctx, ssd.Logger, rw, r, "success" , &map[string]string{"Message" : data.T("web-core-unauth-pwd-aur-reg-form.message-success")}, data)
+
+	ssd.Logger.LogAttrs(ctx, slog.LevelDebug, "Post::end")
+}
@@ -0,0 +1,185 @@
+package id
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+import (
+	"github.com/andrewah64/base-app-client/internal/common/core/password"
+	"github.com/andrewah64/base-app-client/internal/common/core/session"
+	"github.com/andrewah64/base-app-client/internal/common/core/token"
+	"github.com/andrewah64/base-app-client/internal/web/core/error"
+	"github.com/andrewah64/base-app-client/internal/web/core/ui/data/form"
+	"github.com/andrewah64/base-app-client/internal/web/core/ui/data/page"
+	"github.com/andrewah64/base-app-client/internal/web/core/ui/html"
+	"github.com/andrewah64/base-app-client/internal/web/core/ui/notification"
+)
+
+import (
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+func Get (rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	ssd, ok := session.FromContext(ctx)
+	if ! ok {
+		error.IntSrv(ctx, rw, fmt.Errorf("Get::get request info"))
+		return
+	}
+
+	ssd.Logger.LogAttrs(ctx, slog.LevelDebug, "Get::start")
+
+	data, ok := page.FromContext(ctx)
+	if ! ok {
+		error.IntSrv(ctx, rw, fmt.Errorf("Get::get request data"))
+		return
+	}
+
+	p := r.URL.Query()
+
+	if p.Has("ntf") {
+		notification.Toast(ctx, ssd.Logger, rw, r, "info", &map[string]string{"Message" : data.T(p.Get("ntf"))} , data)
+	}
+
+	pwrId := r.PathValue("id")
+
+	session.Identity(&ctx, ssd.Logger, ssd.Conn, "role_web_core_unauth_pwd_aur_mod")
+
+	pwrRs, pwrRsErr := GetPwrInf(&ctx, ssd.Logger, ssd.Conn, ssd.TntId, token.Hash(pwrId))
+	if pwrRsErr != nil {
+		error.IntSrv(ctx, rw, pwrRsErr)
+		return
+	}
+
+	ssd.Logger.LogAttrs(ctx, slog.LevelDebug, "Get::retrieve datasets",
+		slog.Int("len(pwrRs)" , len(pwrRs)),
+	)
+
+	data.ResultSet = &map[string]any{
+		"Pwr"   : &pwrRs,
+		"PwrId" : &pwrId,
+	}
+
+	html.Tmpl(ctx, ssd.Logger, rw, r, "core/unauth/pwd/aur/id/content", http.StatusOK, &data)
+
+	ssd.Logger.LogAttrs(ctx, slog.LevelDebug, "Get::end")
+}
+
+func Post (rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	ssd, ok := session.FromContext(ctx)
+	if ! ok {
+		error.IntSrv(ctx, rw, fmt.Errorf("Post::get request info"))
+		return
+	}
+
+	ssd.Logger.LogAttrs(ctx, slog.LevelDebug, "Post::start")
+
+	data, ok := page.FromContext(ctx)
+	if ! ok {
+		error.IntSrv(ctx, rw, fmt.Errorf("Post::get request data"))
+		return
+	}
+
+	pfErr := r.ParseForm()
+	if pfErr != nil {
+		error.IntSrv(ctx, rw, pfErr)
+		return
+	}
+
+	var (
+		pwrId  = r.PathValue("id")
+		pwrHsh = token.Hash(pwrId)
+		aurPw  = form.VText(r, "pwd-aur-mod-aur-pwd")
+		aurPw2 = form.VText(r, "pwd-aur-mod-aur-pwd-2")
+	)
+
+	session.Identity(&ctx, ssd.Logger, ssd.Conn, "role_web_core_unauth_pwd_aur_mod")
+
+	pwrRs, pwrRsErr := GetPwrInf(&ctx, ssd.Logger, ssd.Conn, ssd.TntId, pwrHsh)
+	if pwrRsErr != nil {
+		error.IntSrv(ctx, rw, pwrRsErr)
+		return
+	}
+
+	if len(pwrRs) != 1 {
+		notification.Toast(ctx, ssd.Logger, rw, r, "error" , &map[string]string{"Message" : data.T("web-core-unauth-pwd-aur-mod-form.error-pwr-expired")}, data)
+
+		return
+	}
+
+	if aurPw != aurPw2 {
+		notification.Toast(ctx, ssd.Logger, rw, r, "error" , &map[string]string{"Message" : data.T("web-core-unauth-pwd-aur-mod-form.error-input-aur-pwd-2-vld")}, data)
+
+		return
+	}
+
+	hstRs, hstRsErr := GetHstInf(&ctx, ssd.Logger, ssd.Conn, ssd.TntId, pwrHsh)
+	if hstRsErr != nil {
+		error.IntSrv(ctx, rw, hstRsErr)
+		return
+	}
+
+	hsts := make([]string, len(hstRs))
+
+	for i, hst := range hstRs {
+		hsts[i] = hst.AurHshPw
+	}
+
+	if vrls := Policy(pwrRs[0]).Check(ctx, aurPw, hsts); len(vrls) > 0 {
+		msgs := make([]string, len(vrls))
+
+		for i, vrl := range vrls {
+			msgs[i] = data.T("web-core-unauth-pwd-aur-mod-form.error-input-aur-pwd-" + vrl)
+		}
+
+		notification.Vrl(ctx, ssd.Logger, rw, r,
+			data.T("web-core-unauth-pwd-aur-mod-form.error-input-aur-pwd-vld"),
+			data.T("web-core-unauth-pwd-aur-mod-form.title-warning-singular", "n", strconv.Itoa(len(msgs))),
+			data.T("web-core-unauth-pwd-aur-mod-form.title-warning-plural"  , "n", strconv.Itoa(len(msgs))),
+			&msgs,
+			data,
+		)
+
+		return
+	}
+
+	aurHshPw, aurHshPwErr := password.Hash(aurPw)
+	if aurHshPwErr != nil {
+		error.IntSrv(ctx, rw, aurHshPwErr)
+		return
+	}
+
+	exptErrs := []string{
+		"PWRNF",
+	}
+
+	patchErr := PatchPwd(&ctx, ssd.Logger, ssd.Conn, ssd.TntId, pwrHsh, aurHshPw, exptErrs)
+	if patchErr != nil {
+		var pgErr *pgconn.PgError
+
+		if errors.As(patchErr, &pgErr) && pgErr.Code == "PWRNF" {
+			notification.Toast(ctx, ssd.Logger, rw, r, "error" , &map[string]string{"Message" : data.T("web-core-unauth-pwd-aur-mod-form.error-pwr-expired")}, data)
+
+			return
+		}
+
+		error.IntSrv(ctx, rw, patchErr)
+		return
+	}
+
+	html.Locate(rw, r, html.Location{
+		Path   : "/",
+		Target : "#main",
+		Select : "#content",
+		Values : map[string]string{"ntf": "web-core-unauth-pwd-aur-mod-form.message-success"},
+	})
+
+	ssd.Logger.LogAttrs(ctx, slog.LevelDebug, "Post::end")
+}
@@ -0,0 +1,131 @@
+package id
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+)
+
+import (
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jackc/pgx/v5"
+)
+
+import (
+	"github.com/andrewah64/base-app-client/internal/common/core/db"
+	"github.com/andrewah64/base-app-client/internal/common/core/password"
+)
+
+type PwrInf struct {
+	AupcAurPwdMinLen int
+	AupcAurPwdMaxLen int
+	AupcAurPwdIncSym bool
+	AupcAurPwdIncNum bool
+	AupcAurPwdIncUpr bool
+	AupcAurPwdIncLwr bool
+	AupcAurPwdSymSet string
+	AupcAurPwdBrc    bool
+	AurNm            string
+}
+
+// GetPwrInf returns the tenant's password policy and the user a password
+// reset token was sent to, if the token has not expired or been used.
+func GetPwrInf (ctx *context.Context, logger *slog.Logger, conn *pgxpool.Conn, tntId int, pwrHsh string) ([]PwrInf, error) {
+	const (
+		dbSchema = "web_core_unauth_pwd_aur_mod"
+		dbFunc   = "pwr_inf"
+	)
+
+	results, err := db.DataSet[PwrInf](ctx, logger, conn, func(ctx *context.Context, tx *pgx.Tx)(string, string, *pgx.Rows, error){
+		qry := fmt.Sprintf("select %v.%v($1, $2, $3)", dbSchema, dbFunc)
+
+		call, err := (*tx).Query(*ctx, qry, dbFunc, tntId, pwrHsh)
+		if err != nil {
+			slog.LogAttrs(*ctx, slog.LevelError, "GetPwrInf::get dataset",
+				slog.String("error" , err.Error()),
+				slog.String("qry"   , qry),
+				slog.Int   ("tntId" , tntId),
+			)
+
+			return qry, dbFunc, nil, fmt.Errorf("GetPwrInf::call database function: %w", err)
+		}
+
+		return qry, dbFunc, &call, nil
+	})
+
+	return results, err
+}
+
+// Policy returns the tenant's password policy.
+func Policy (inf PwrInf) password.Policy {
+	return password.Policy{
+		MinLen : inf.AupcAurPwdMinLen,
+		MaxLen : inf.AupcAurPwdMaxLen,
+		IncUpr : inf.AupcAurPwdIncUpr,
+		IncLwr : inf.AupcAurPwdIncLwr,
+		IncNum : inf.AupcAurPwdIncNum,
+		IncSym : inf.AupcAurPwdIncSym,
+		SymSet : inf.AupcAurPwdSymSet,
+		Brc    : inf.AupcAurPwdBrc,
+	}
+}
+
+type HstInf struct {
+	AurHshPw string
+}
+
+// GetHstInf returns the hashes of the passwords that the user the token was
+// sent to may not reuse.
+func GetHstInf (ctx *context.Context, logger *slog.Logger, conn *pgxpool.Conn, tntId int, pwrHsh string) ([]HstInf, error) {
+	const (
+		dbSchema = "web_core_unauth_pwd_aur_mod"
+		dbFunc   = "hst_inf"
+	)
+
+	results, err := db.DataSet[HstInf](ctx, logger, conn, func(ctx *context.Context, tx *pgx.Tx)(string, string, *pgx.Rows, error){
+		qry := fmt.Sprintf("select %v.%v($1, $2, $3)", dbSchema, dbFunc)
+
+		call, err := (*tx).Query(*ctx, qry, dbFunc, tntId, pwrHsh)
+		if err != nil {
+			slog.LogAttrs(*ctx, slog.LevelError, "GetHstInf::get dataset",
+				slog.String("error" , err.Error()),
+				slog.String("qry"   , qry),
+				slog.Int   ("tntId" , tntId),
+			)
+
+			return qry, dbFunc, nil, fmt.Errorf("GetHstInf::call database function: %w", err)
+		}
+
+		return qry, dbFunc, &call, nil
+	})
+
+	return results, err
+}
+
+// PatchPwd sets the password of the user the token was sent to and uses the
+// token up. PWRNF is raised if the token has expired or been used since it
+// was checked.
+func PatchPwd (ctx *context.Context, logger *slog.Logger, conn *pgxpool.Conn, tntId int, pwrHsh string, aurHshPw string, exptErrs []string) error {
+	var (
+		sprocCall   = "call web_core_unauth_pwd_aur_mod.mod_pwd(@p_tnt_id, @p_pwr_hsh, @p_aur_hsh_pw)"
+		sprocParams = pgx.NamedArgs{
+			"p_tnt_id"     : tntId,
+			"p_pwr_hsh"    : pwrHsh,
+			"p_aur_hsh_pw" : aurHshPw,
+		}
+	)
+
+	sprocErr := db.Sproc(ctx, logger, conn, sprocCall, sprocParams, exptErrs)
+	if sprocErr != nil {
+		logger.LogAttrs(*ctx, slog.LevelDebug, "call sproc",
+			slog.String("sprocCall" , sprocCall),
+			slog.String("error"     , sprocErr.Error()),
+			slog.Int   ("tntId"     , tntId),
+			slog.Any   ("exptErrs"  , exptErrs),
+		)
+
+		return sprocErr
+	}
+
+	return nil
+}
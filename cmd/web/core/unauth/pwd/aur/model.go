@@ -0,0 +1,86 @@
+package pwd
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+)
+
+import (
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jackc/pgx/v5"
+)
+
+import (
+	"github.com/andrewah64/base-app-client/internal/common/core/db"
+)
+
+type AurInf struct {
+	AurId int
+	AurNm string
+}
+
+// GetAurInf returns the user with the email address aurEa, if the tenant
+// allows users to sign in with a password.
+func GetAurInf (ctx *context.Context, logger *slog.Logger, conn *pgxpool.Conn, tntId int, aurEa string) ([]AurInf, error) {
+	const (
+		dbSchema = "web_core_unauth_pwd_aur_reg"
+		dbFunc   = "aur_inf"
+	)
+
+	results, err := db.DataSet[AurInf](ctx, logger, conn, func(ctx *context.Context, tx *pgx.Tx)(string, string, *pgx.Rows, error){
+		qry := fmt.Sprintf("select %v.%v($1, $2, $3)", dbSchema, dbFunc)
+
+		call, err := (*tx).Query(*ctx, qry, dbFunc, tntId, aurEa)
+		if err != nil {
+			slog.LogAttrs(*ctx, slog.LevelError, "GetAurInf::get dataset",
+				slog.String("error" , err.Error()),
+				slog.String("qry"   , qry),
+				slog.Int   ("tntId" , tntId),
+				slog.String("aurEa" , aurEa),
+			)
+
+			return qry, dbFunc, nil, fmt.Errorf("GetAurInf::call database function: %w", err)
+		}
+
+		return qry, dbFunc, &call, nil
+	})
+
+	return results, err
+}
+
+// PostPwr saves the hash of a password reset token that expires after
+// pwrMins minutes and, in the same transaction, queues the email that sends
+// the token to the user.
+func PostPwr (ctx *context.Context, logger *slog.Logger, conn *pgxpool.Conn, tntId int, aurId int, pwrHsh string, pwrMins int, mobTo string, mobSbj string, mobTxt string, mobHtm string, exptErrs []string) error {
+	var (
+		sprocCall   = "call web_core_unauth_pwd_aur_reg.reg_pwr(@p_tnt_id, @p_aur_id, @p_pwr_hsh, @p_pwr_mins, @p_mob_to, @p_mob_sbj, @p_mob_txt, @p_mob_htm)"
+		sprocParams = pgx.NamedArgs{
+			"p_tnt_id"   : tntId,
+			"p_aur_id"   : aurId,
+			"p_pwr_hsh"  : pwrHsh,
+			"p_pwr_mins" : pwrMins,
+			"p_mob_to"   : mobTo,
+			"p_mob_sbj"  : mobSbj,
+			"p_mob_txt"  : mobTxt,
+			"p_mob_htm"  : mobHtm,
+		}
+	)
+
+	sprocErr := db.Sproc(ctx, logger, conn, sprocCall, sprocParams, exptErrs)
+	if sprocErr != nil {
+		logger.LogAttrs(*ctx, slog.LevelDebug, "call sproc",
+			slog.String("sprocCall" , sprocCall),
+			slog.String("error"     , sprocErr.Error()),
+			slog.Int   ("tntId"     , tntId),
+			slog.Int   ("aurId"     , aurId),
+			slog.Int   ("pwrMins"   , pwrMins),
+			slog.String("mobTo"     , mobTo),
+			slog.Any   ("exptErrs"  , exptErrs),
+		)
+
+		return sprocErr
+	}
+
+	return nil
+}
@@ -10,9 +10,12 @@ import (
 )
 
 import (
+	cm "github.com/andrewah64/base-app-client/internal/common/core/mail"
 	cs "github.com/andrewah64/base-app-client/internal/common/core/session"
 	ws "github.com/andrewah64/base-app-client/internal/web/core/session"
 	   "github.com/andrewah64/base-app-client/internal/web/core/error"
+	   "github.com/andrewah64/base-app-client/internal/web/core/ui/data/page"
+	   "github.com/andrewah64/base-app-client/internal/web/core/verify"
 )
 
 import (
@@ -48,7 +51,7 @@ func Post (rw http.ResponseWriter, r *http.Request) {
 	if len(acsInfRs) == 0 {
 		ssd.Logger.LogAttrs(ctx, slog.LevelDebug, "Post::no acs information retrieved")
 
-		rw.WriteHeader(http.StatusForbidden)
+		error.Status(ctx, rw, http.StatusForbidden)
 
 		return
 	}
@@ -87,7 +90,7 @@ func Post (rw http.ResponseWriter, r *http.Request) {
 	if astInfErr != nil {
 		ssd.Logger.LogAttrs(ctx, slog.LevelDebug, "Post::error retrieving assertion information")
 
-		rw.WriteHeader(http.StatusForbidden)
+		error.Status(ctx, rw, http.StatusForbidden)
 
 		return
 	}
@@ -95,7 +98,7 @@ func Post (rw http.ResponseWriter, r *http.Request) {
 	if astInf.WarningInfo.InvalidTime {
 		ssd.Logger.LogAttrs(ctx, slog.LevelDebug, "Post::invalid time")
 
-		rw.WriteHeader(http.StatusForbidden)
+		error.Status(ctx, rw, http.StatusForbidden)
 
 		return
 	}
@@ -105,7 +108,7 @@ func Post (rw http.ResponseWriter, r *http.Request) {
 			slog.String("acsInfRs[0].S2cEntityId", acsInfRs[0].S2cEntityId),
 		)
 
-		rw.WriteHeader(http.StatusForbidden)
+		error.Status(ctx, rw, http.StatusForbidden)
 
 		return
 	}
@@ -118,11 +121,14 @@ func Post (rw http.ResponseWriter, r *http.Request) {
 			slog.String("aurEaErr.Error()" , aurEaErr.Error()),
 		)
 
-		rw.WriteHeader(http.StatusForbidden)
+		error.Status(ctx, rw, http.StatusForbidden)
 
 		return
 	}
 
+	// a trusted identity provider verifies the email addresses it asserts
+	aurEaVrf := acsInfRs[0].IdpEaVrf
+
 	aurInfRs, aurInfRsErr := GetAurInf(&ctx, ssd.Logger, ssd.Conn, ssd.TntId, aurEa)
 	if aurInfRsErr != nil {
 		error.IntSrv(ctx, rw, aurInfRsErr)
@@ -135,7 +141,34 @@ func Post (rw http.ResponseWriter, r *http.Request) {
 
 	switch len(aurInfRs) {
 		case 0:
-			regErr := RegAur (&ctx, ssd.Logger, ssd.Conn, ssd.TntId, aurEa, nil)
+			var (
+				evrHsh *string
+				msg    *cm.Message
+			)
+
+			if ! aurEaVrf {
+				data, ok := page.FromContext(ctx)
+				if ! ok {
+					error.IntSrv(ctx, rw, fmt.Errorf("Post::get page data"))
+					return
+				}
+
+				evrId, evrTkn, evrErr := verify.Token()
+				if evrErr != nil {
+					error.IntSrv(ctx, rw, evrErr)
+					return
+				}
+
+				evrMsg, msgErr := verify.Message(ctx, r, data, aurEa, aurEa, evrId)
+				if msgErr != nil {
+					error.IntSrv(ctx, rw, msgErr)
+					return
+				}
+
+				evrHsh, msg = &evrTkn, &evrMsg
+			}
+
+			regErr := RegAur (&ctx, ssd.Logger, ssd.Conn, ssd.TntId, aurEa, aurEaVrf, evrHsh, verify.Mins, msg, nil)
 			if regErr != nil {
 				error.IntSrv(ctx, rw, regErr)
 				return
@@ -151,12 +184,42 @@ func Post (rw http.ResponseWriter, r *http.Request) {
 				slog.Int("len(aurInfRs)" , len(aurInfRs)),
 			)
 		case 1:
-			//The user was already registered
+			// an address the provider hasn't verified could belong to anyone, so
+			// it is never used to sign in to an account that already exists
+			if ! aurEaVrf {
+				ssd.Logger.LogAttrs(ctx, slog.LevelDebug, "Post::provider has not verified the email address of an existing user",
+					slog.Int("aurInfRs[0].AurId", aurInfRs[0].AurId),
+				)
+
+				http.Redirect(rw, r, "/web/core/unauth/evr/aur?ntf=web-core-unauth-evr-aur-page.message-idp-unverified", http.StatusFound)
+
+				return
+			}
+
+			if ! aurInfRs[0].AurEaVrf {
+				vrfErr := PatchAurEaVrf(&ctx, ssd.Logger, ssd.Conn, ssd.TntId, aurInfRs[0].AurId, nil)
+				if vrfErr != nil {
+					error.IntSrv(ctx, rw, vrfErr)
+					return
+				}
+
+				aurInfRs[0].AurEaVrf, aurInfRs[0].AurEaVrfOk = true, true
+			}
 		default:
 			error.IntSrv(ctx, rw, fmt.Errorf("Post::%v records were returned when only 0 or 1 are expected", len(aurInfRs)))
 			return
 	}
 
+	if ! aurInfRs[0].AurEaVrfOk {
+		ssd.Logger.LogAttrs(ctx, slog.LevelDebug, "Post::email address is not verified",
+			slog.Int("aurInfRs[0].AurId", aurInfRs[0].AurId),
+		)
+
+		http.Redirect(rw, r, "/web/core/unauth/evr/aur?ntf=web-core-unauth-evr-aur-page.message-pending", http.StatusFound)
+
+		return
+	}
+
 	cookieExpiry := time.Now().Add(aurInfRs[0].SsnDn)
 
 	cs.Identity(&ctx, ssd.Logger, ssd.Conn, "role_web_core_unauth_ssn_aur_reg")
@@ -15,6 +15,8 @@ import (
 	cs "github.com/andrewah64/base-app-client/internal/common/core/session"
 	   "github.com/andrewah64/base-app-client/internal/common/core/token"
 	   "github.com/andrewah64/base-app-client/internal/web/core/error"
+	   "github.com/andrewah64/base-app-client/internal/web/core/lockout"
+	   "github.com/andrewah64/base-app-client/internal/web/core/mfa"
 	   "github.com/andrewah64/base-app-client/internal/web/core/passkey"
 	ws "github.com/andrewah64/base-app-client/internal/web/core/session"
 	   "github.com/andrewah64/base-app-client/internal/web/core/ui/data/form"
@@ -33,6 +35,10 @@ import (
 	"github.com/russellhaering/goxmldsig"
 )
 
+const (
+	pwrMins = 30
+)
+
 func Get(rw http.ResponseWriter, r *http.Request){
 	ctx := r.Context()
 
@@ -77,7 +83,7 @@ func Get(rw http.ResponseWriter, r *http.Request){
 		if len(s2sInfRs) == 0 {
 			ssd.Logger.LogAttrs(ctx, slog.LevelDebug, "Get::no IDP information retrieved")
 
-			rw.WriteHeader(http.StatusForbidden)
+			error.Status(ctx, rw, http.StatusForbidden)
 
 			return
 		}
@@ -123,6 +129,16 @@ func Get(rw http.ResponseWriter, r *http.Request){
 			rs["Pwd"] = &pwdRs
 		}
 
+		if aumRs[0].AukcEnabled {
+			aukcAtnInfRs, aukcAtnInfRsErr := GetAukcAtnInf(&ctx, ssd.Logger, ssd.Conn, ssd.TntId)
+			if aukcAtnInfRsErr != nil {
+				error.IntSrv(ctx, rw, aukcAtnInfRsErr)
+				return
+			}
+
+			rs["Pky"] = &aukcAtnInfRs
+		}
+
 		data.ResultSet = &rs
 
 		html.Tmpl(ctx, ssd.Logger, rw, r, "core/unauth/ssn/aur/content", http.StatusOK, &data)
@@ -171,6 +187,17 @@ func Post(rw http.ResponseWriter, r *http.Request){
 
 			cs.Identity(&ctx, ssd.Logger, ssd.Conn, "role_web_core_unauth_ssn_aur_reg")
 
+			flrRs, flrRsErr := GetFlrInf(&ctx, ssd.Logger, ssd.Conn, ssd.TntId, aurNm)
+			if flrRsErr != nil {
+				error.IntSrv(ctx, rw, flrRsErr)
+				return
+			}
+
+			if len(flrRs) != 1 {
+				error.IntSrv(ctx, rw, fmt.Errorf("Post::failed sign in details not found"))
+				return
+			}
+
 			aurRs, aurRsErr := GetAurPwdInf(&ctx, ssd.Logger, ssd.Conn, ssd.TntId, aurNm)
 			if aurRsErr != nil {
 				error.IntSrv(ctx, rw, aurRsErr)
@@ -178,68 +205,154 @@ func Post(rw http.ResponseWriter, r *http.Request){
 			}
 
 			ssd.Logger.LogAttrs(ctx, slog.LevelDebug, "Post::retrieve details of user attempting to authenticate",
-				slog.Int("len(aurRs)", len(aurRs)),
+				slog.Int ("len(aurRs)"         , len(aurRs)),
+				slog.Int ("flrRs[0].FlrCnt"    , flrRs[0].FlrCnt),
+				slog.Bool("flrRs[0].FlrLocked" , flrRs[0].FlrLocked),
 			)
 
-			switch len(aurRs) {
-			case 1:
-				if password.CheckHash(aurPwd, aurRs[0].AurHshPw) {
-					ssd.Logger.LogAttrs(ctx, slog.LevelDebug, "Post::username/password combination is valid",
-						slog.String("aurNm", aurNm),
-					)
+			if len(aurRs) > 1 {
+				error.IntSrv(ctx, rw, fmt.Errorf("Post::details of more than one user found"))
+				return
+			}
+
+			// an unknown username is checked against a dummy hash, and a locked
+			// one all the same, so that both take as long and fail with the same
+			// message as a wrong password
+			hsh := password.Dummy()
+			if len(aurRs) == 1 {
+				hsh = aurRs[0].AurHshPw
+			}
+
+			if password.CheckHash(aurPwd, hsh) && len(aurRs) == 1 && ! flrRs[0].FlrLocked {
+				ssd.Logger.LogAttrs(ctx, slog.LevelDebug, "Post::username/password combination is valid",
+					slog.String("aurNm", aurNm),
+				)
 
-					if aurRs[0].AupcMfaEnabled {
-						nncNonce, nncNonceErr := token.Token(16)
-						if nncNonceErr != nil {
-							error.IntSrv(ctx, rw, nncNonceErr)
-							return
-						}
+				if flrRs[0].FlrCnt > 0 {
+					delErr := DelFlr(&ctx, ssd.Logger, ssd.Conn, ssd.TntId, aurNm, nil)
+					if delErr != nil {
+						error.IntSrv(ctx, rw, delErr)
+						return
+					}
+				}
 
-						nncNonce = fmt.Sprintf("%v%v", aurRs[0].AurId, nncNonce)
+				if password.NeedsRehash(aurRs[0].AurHshPw) {
+					aurHshPw, hshErr := password.Hash(aurPwd)
+					if hshErr != nil {
+						error.IntSrv(ctx, rw, hshErr)
+						return
+					}
 
-						PostNnc (&ctx, ssd.Logger, ssd.Conn, ssd.TntId, aurRs[0].AurId, nncNonce, time.Now().Add(time.Minute * 5), nil)
+					patchErr := PatchAurHshPw(&ctx, ssd.Logger, ssd.Conn, ssd.TntId, aurRs[0].AurId, aurHshPw, nil)
+					if patchErr != nil {
+						error.IntSrv(ctx, rw, patchErr)
+						return
+					}
 
-						rw.Header().Set("HX-Location", fmt.Sprintf(`{"path":"/web/core/unauth/otp/ssn/aur/%v", "target":"#main", "select":"#content"}`, nncNonce))
-					} else {
-						cookieExpiry := time.Now().Add(aurRs[0].SsnDn)
+					ssd.Logger.LogAttrs(ctx, slog.LevelDebug, "Post::password hash replaced",
+						slog.Int("aurRs[0].AurId", aurRs[0].AurId),
+					)
+				}
 
-						ssnErr := ws.Begin(&ctx, ssd.Logger, ssd.Conn, rw, aurRs[0].AurId, cookieExpiry)
-						if ssnErr != nil {
-							error.IntSrv(ctx, rw, ssnErr)
-							return
-						}
+				// the tenant requires a verified email address and this user
+				// hasn't verified theirs yet
+				if ! aurRs[0].AurEaVrfOk {
+					html.Locate(rw, r, html.Location{
+						Path   : "/web/core/unauth/evr/aur",
+						Target : "#main",
+						Select : "#content",
+						Values : map[string]string{"ntf": "web-core-unauth-evr-aur-page.message-pending"},
+					})
 
-						ssd.Logger.LogAttrs(ctx, slog.LevelDebug, "Post::redirect to user's home page",
-							slog.String("aurRs[0].EppPt", aurRs[0].EppPt),
-						)
+					return
+				}
 
-						rw.Header().Set("HX-Redirect", aurRs[0].EppPt)
+				// the password is older than the tenant allows, so the user
+				// chooses a new one before signing in
+				if aurRs[0].AurPwdExp {
+					pwrId, pwrIdErr := token.Token(32)
+					if pwrIdErr != nil {
+						error.IntSrv(ctx, rw, pwrIdErr)
+						return
 					}
+
+					postErr := PostPwr(&ctx, ssd.Logger, ssd.Conn, ssd.TntId, aurRs[0].AurId, token.Hash(pwrId), pwrMins, nil)
+					if postErr != nil {
+						error.IntSrv(ctx, rw, postErr)
+						return
+					}
+
+					html.Locate(rw, r, html.Location{
+						Path   : fmt.Sprintf("/web/core/unauth/pwd/aur/%v", pwrId),
+						Target : "#main",
+						Select : "#content",
+						Values : map[string]string{"ntf": "web-core-unauth-pwd-aur-mod-page.message-expired"},
+					})
+
+					return
 				}
 
-				fallthrough
-			default:
-				if len(aurRs) <= 1 {
-					ssd.Logger.LogAttrs(ctx, slog.LevelDebug, "Post::username/password combination is invalid",
-						slog.String("aurNm", aurNm),
-					)
+				// the second factor page offers whichever of the user's
+				// factors the tenant accepts
+				if aurRs[0].AupcMfaNm != mfa.MfaNone {
+					nncNonce, nncNonceErr := token.Token(16)
+					if nncNonceErr != nil {
+						error.IntSrv(ctx, rw, nncNonceErr)
+						return
+					}
 
-					msgs := []string{data.T("web-core-unauth-ssn-aur-reg-aupc-form.error-input-aur-nm-pwd-vld")}
+					nncNonce = fmt.Sprintf("%v%v", aurRs[0].AurId, nncNonce)
 
-					notification.Vrl(ctx, ssd.Logger, rw, r,
-						data.T("web-core-unauth-ssn-aur-reg-page.title"),
-						data.T("web-core-unauth-ssn-aur-reg-aupc-form.title-warning-singular", "n", strconv.Itoa(len(msgs))),
-						data.T("web-core-unauth-ssn-aur-reg-aupc-form.title-warning-plural"  , "n", strconv.Itoa(len(msgs))),
-						&msgs,
-						data,
-					)
+					PostNnc (&ctx, ssd.Logger, ssd.Conn, ssd.TntId, aurRs[0].AurId, nncNonce, time.Now().Add(time.Minute * 5), nil)
 
-					return
+					html.Locate(rw, r, html.Location{
+						Path   : fmt.Sprintf("/web/core/unauth/otp/ssn/aur/%v", nncNonce),
+						Target : "#main",
+						Select : "#content",
+					})
 				} else {
-					error.IntSrv(ctx, rw, fmt.Errorf("Post::details of more than one user found"))
-					return
+					cookieExpiry := time.Now().Add(aurRs[0].SsnDn)
+
+					ssnErr := ws.Begin(&ctx, ssd.Logger, ssd.Conn, rw, aurRs[0].AurId, cookieExpiry)
+					if ssnErr != nil {
+						error.IntSrv(ctx, rw, ssnErr)
+						return
+					}
+
+					ssd.Logger.LogAttrs(ctx, slog.LevelDebug, "Post::redirect to user's home page",
+						slog.String("aurRs[0].EppPt", aurRs[0].EppPt),
+					)
+
+					html.Redirect(rw, r, aurRs[0].EppPt)
 				}
+
+				return
 			}
+
+			ssd.Logger.LogAttrs(ctx, slog.LevelDebug, "Post::username/password combination is invalid",
+				slog.String("aurNm"     , aurNm),
+				slog.Bool  ("locked"    , flrRs[0].FlrLocked),
+			)
+
+			flrErr := PostFlr(&ctx, ssd.Logger, ssd.Conn, ssd.TntId, aurNm, nil)
+			if flrErr != nil {
+				error.IntSrv(ctx, rw, flrErr)
+				return
+			}
+
+			lockout.Delay(ctx, ssd.Conn, time.Duration(flrRs[0].AupcDlyMs) * time.Millisecond, flrRs[0].FlrCnt)
+
+			msgs := []string{data.T("web-core-unauth-ssn-aur-reg-aupc-form.error-input-aur-nm-pwd-vld")}
+
+			notification.Vrl(ctx, ssd.Logger, rw, r,
+				data.T("web-core-unauth-ssn-aur-reg-page.title"),
+				data.T("web-core-unauth-ssn-aur-reg-aupc-form.title-warning-singular", "n", strconv.Itoa(len(msgs))),
+				data.T("web-core-unauth-ssn-aur-reg-aupc-form.title-warning-plural"  , "n", strconv.Itoa(len(msgs))),
+				&msgs,
+				data,
+			)
+
+			return
 		case "pky-atn-bgn":
 			aurNm := strings.ToLower(form.VText(r, "ssn-aur-reg-pky-aur-nm"))
 
@@ -341,15 +454,31 @@ func Post(rw http.ResponseWriter, r *http.Request){
 				return
 			}
 
-			_, brErr := passkey.WebAuthn(&ctx, ssd.Logger, ssd.TntId).ValidateLogin(pkyAur, sd, pR)
+			c, brErr := passkey.WebAuthn(&ctx, ssd.Logger, ssd.TntId).ValidateLogin(pkyAur, sd, pR)
 			if brErr != nil {
 				error.IntSrv(ctx, rw, brErr)
 				return
 			}
 
+			pkyErr := PatchPky(&ctx, ssd.Logger, ssd.Conn, ssd.TntId, c.ID, int(c.Authenticator.SignCount), c.Flags.BackupState, c.Authenticator.CloneWarning, nil)
+			if pkyErr != nil {
+				error.IntSrv(ctx, rw, pkyErr)
+				return
+			}
+
 			aurRs, aurRsErr := GetAurPkyInf(&ctx, ssd.Logger, ssd.Conn, ssd.TntId, aurNm)
 			if aurRsErr != nil {
 				error.IntSrv(ctx, rw, aurRsErr)
+				return
+			}
+
+						// the tenant requires a verified email address and this user
+			// hasn't verified theirs yet
+			if ! aurRs[0].AurEaVrfOk {
+				rw.Header().Set("Content-Type", "application/json")
+
+				json.NewEncoder(rw).Encode(struct {EppPt string `json:"eppPt"`}{EppPt : "/web/core/unauth/evr/aur?ntf=web-core-unauth-evr-aur-page.message-pending"})
+
 				return
 			}
 
@@ -367,6 +496,158 @@ func Post(rw http.ResponseWriter, r *http.Request){
 
 			rw.Header().Set("Content-Type", "application/json")
 
+			json.NewEncoder(rw).Encode(struct {EppPt string `json:"eppPt"`}{EppPt : aurRs[0].EppPt})
+
+		case "pky-dsc-bgn":
+			cs.Identity(&ctx, ssd.Logger, ssd.Conn, "role_web_core_unauth_ssn_aur_reg")
+
+			aukcAtnInfRs, aukcAtnInfRsErr := GetAukcAtnInf(&ctx, ssd.Logger, ssd.Conn, ssd.TntId)
+			if aukcAtnInfRsErr != nil {
+				error.IntSrv(ctx, rw, aukcAtnInfRsErr)
+				return
+			}
+
+			if ! aukcAtnInfRs[0].Dsc() {
+				error.Status(ctx, rw, http.StatusNotFound)
+				return
+			}
+
+			// passkeys offered in the browser's autofill are asked for with
+			// conditional mediation, otherwise the browser's own dialog is shown
+			var mdn protocol.CredentialMediationRequirement = protocol.MediationDefault
+
+			if form.VText(r, "ssn-aur-reg-pky-mdn") == string(protocol.MediationConditional) {
+				mdn = protocol.MediationConditional
+			}
+
+			ssd.Logger.LogAttrs(ctx, slog.LevelDebug, "Post::begin discoverable passkey sign in",
+				slog.String("mdn", string(mdn)),
+			)
+
+			var pkh []protocol.PublicKeyCredentialHints = make([]protocol.PublicKeyCredentialHints, len(aukcAtnInfRs[0].PkhNm))
+			for i, v := range aukcAtnInfRs[0].PkhNm {
+				pkh[i] = protocol.PublicKeyCredentialHints(v)
+			}
+
+			atnOpts := []webauthn.LoginOption {
+				webauthn.WithUserVerification(protocol.UserVerificationRequirement(aukcAtnInfRs[0].PuvNm)),
+				webauthn.WithAssertionPublicKeyCredentialHints(pkh),
+			}
+
+			c, s, blErr := passkey.WebAuthn(&ctx, ssd.Logger, ssd.TntId).BeginDiscoverableMediatedLogin(mdn, atnOpts...)
+			if blErr != nil {
+				error.IntSrv(ctx, rw, blErr)
+				return
+			}
+
+			sd, sdErr := json.Marshal(s)
+			if sdErr != nil {
+				error.IntSrv(ctx, rw, sdErr)
+				return
+			}
+
+			regErr := PostPld(&ctx, ssd.Logger, ssd.Conn, ssd.TntId, s.Challenge, sd, nil)
+			if regErr != nil {
+				error.IntSrv(ctx, rw, regErr)
+				return
+			}
+
+			rw.Header().Set("Content-Type", "application/json")
+
+			json.NewEncoder(rw).Encode(c)
+
+		case "pky-dsc-end":
+			cs.Identity(&ctx, ssd.Logger, ssd.Conn, "role_web_core_unauth_ssn_aur_reg")
+
+			pR, pRErr := protocol.ParseCredentialRequestResponse(r);
+			if pRErr != nil {
+				ssd.Logger.LogAttrs(ctx, slog.LevelDebug, "Post::parse discoverable passkey assertion",
+					slog.String("pRErr", pRErr.Error()),
+				)
+
+				notification.ToastStatus(ctx, ssd.Logger, rw, r, "error" , &map[string]string{"Message" : data.T("web-core-unauth-ssn-aur-reg-pky-form.error-pky")}, http.StatusUnprocessableEntity, data)
+				return
+			}
+
+			pldRs, pldRsErr := GetPldInf(&ctx, ssd.Logger, ssd.Conn, ssd.TntId, pR.Response.CollectedClientData.Challenge)
+			if pldRsErr != nil {
+				error.IntSrv(ctx, rw, pldRsErr)
+				return
+			}
+
+			if len(pldRs) == 0 {
+				notification.ToastStatus(ctx, ssd.Logger, rw, r, "error" , &map[string]string{"Message" : data.T("web-core-unauth-ssn-aur-reg-pky-form.error-pky-expired")}, http.StatusUnprocessableEntity, data)
+				return
+			}
+
+			// the challenge is used up whether or not the passkey is accepted
+			delErr := DelPld(&ctx, ssd.Logger, ssd.Conn, ssd.TntId, pR.Response.CollectedClientData.Challenge, nil)
+			if delErr != nil {
+				error.IntSrv(ctx, rw, delErr)
+				return
+			}
+
+			var sd webauthn.SessionData
+			sdErr := json.Unmarshal([]byte(pldRs[0].PldJs), &sd)
+			if sdErr != nil {
+				error.IntSrv(ctx, rw, sdErr)
+				return
+			}
+
+			var aurNm string
+
+			c, brErr := passkey.WebAuthn(&ctx, ssd.Logger, ssd.TntId).ValidateDiscoverableLogin(GetHdlAur(&ctx, ssd.Conn, ssd.TntId, &aurNm), sd, pR)
+			if brErr != nil {
+				ssd.Logger.LogAttrs(ctx, slog.LevelDebug, "Post::validate discoverable passkey assertion",
+					slog.String("brErr", brErr.Error()),
+				)
+
+				notification.ToastStatus(ctx, ssd.Logger, rw, r, "error" , &map[string]string{"Message" : data.T("web-core-unauth-ssn-aur-reg-pky-form.error-pky")}, http.StatusUnprocessableEntity, data)
+				return
+			}
+
+			pkyErr := PatchPky(&ctx, ssd.Logger, ssd.Conn, ssd.TntId, c.ID, int(c.Authenticator.SignCount), c.Flags.BackupState, c.Authenticator.CloneWarning, nil)
+			if pkyErr != nil {
+				error.IntSrv(ctx, rw, pkyErr)
+				return
+			}
+
+			aurRs, aurRsErr := GetAurPkyInf(&ctx, ssd.Logger, ssd.Conn, ssd.TntId, aurNm)
+			if aurRsErr != nil {
+				error.IntSrv(ctx, rw, aurRsErr)
+				return
+			}
+
+			if len(aurRs) == 0 {
+				error.Status(ctx, rw, http.StatusNotFound)
+				return
+			}
+
+						// the tenant requires a verified email address and this user
+			// hasn't verified theirs yet
+			if ! aurRs[0].AurEaVrfOk {
+				rw.Header().Set("Content-Type", "application/json")
+
+				json.NewEncoder(rw).Encode(struct {EppPt string `json:"eppPt"`}{EppPt : "/web/core/unauth/evr/aur?ntf=web-core-unauth-evr-aur-page.message-pending"})
+
+				return
+			}
+
+			cookieExpiry := time.Now().Add(aurRs[0].SsnDn)
+
+			ssnErr := ws.Begin(&ctx, ssd.Logger, ssd.Conn, rw, aurRs[0].AurId, cookieExpiry)
+			if ssnErr != nil {
+				error.IntSrv(ctx, rw, ssnErr)
+				return
+			}
+
+			ssd.Logger.LogAttrs(ctx, slog.LevelDebug, "Post::redirect to user's home page",
+				slog.String("aurNm"          , aurNm),
+				slog.String("aurRs[0].EppPt" , aurRs[0].EppPt),
+			)
+
+			rw.Header().Set("Content-Type", "application/json")
+
 			json.NewEncoder(rw).Encode(struct {EppPt string `json:"eppPt"`}{EppPt : aurRs[0].EppPt})
 	}
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"strings"
 	"time"
 )
 
@@ -24,9 +25,16 @@ import (
 
 type AukcAtnInf struct {
 	PuvNm   string
+	PdcNm   string
 	PkhNm []string
 }
 
+// Dsc reports whether the tenant's resident key setting has passkeys made
+// discoverable, so that users can sign in with one without a username.
+func (a AukcAtnInf) Dsc() bool {
+	return a.PdcNm == string(protocol.ResidentKeyRequirementRequired) || a.PdcNm == string(protocol.ResidentKeyRequirementPreferred)
+}
+
 func GetAukcAtnInf (ctx *context.Context, logger *slog.Logger, conn *pgxpool.Conn, tntId int) ([]AukcAtnInf, error) {
 	const (
 		dbSchema = "web_core_unauth_ssn_aur_reg"
@@ -89,9 +97,10 @@ func GetAumInf (ctx *context.Context, logger *slog.Logger, conn *pgxpool.Conn, t
 }
 
 type AurPkyInf struct {
-	AurId int
-	SsnDn time.Duration
-	EppPt string
+	AurId      int
+	SsnDn      time.Duration
+	EppPt      string
+	AurEaVrfOk bool
 }
 
 func GetAurPkyInf (ctx *context.Context, logger *slog.Logger, conn *pgxpool.Conn, tntId int, aurNm string) ([]AurPkyInf, error) {
@@ -122,11 +131,13 @@ func GetAurPkyInf (ctx *context.Context, logger *slog.Logger, conn *pgxpool.Conn
 }
 
 type AurPwdInf struct {
-	AurId          int
-	AurHshPw       string
-	SsnDn          time.Duration
-	EppPt          string
-	AupcMfaEnabled bool
+	AurId      int
+	AurHshPw   string
+	SsnDn      time.Duration
+	EppPt      string
+	AupcMfaNm  string
+	AurEaVrfOk bool
+	AurPwdExp  bool
 }
 
 func GetAurPwdInf (ctx *context.Context, logger *slog.Logger, conn *pgxpool.Conn, tntId int, aurNm string) ([]AurPwdInf, error) {
@@ -156,6 +167,41 @@ func GetAurPwdInf (ctx *context.Context, logger *slog.Logger, conn *pgxpool.Conn
 	return results, err
 }
 
+// FlrInf is the count of failed sign ins made with a username, whether or
+// not a user has it, so that locked and unknown usernames look the same.
+type FlrInf struct {
+	FlrCnt    int
+	FlrLocked bool
+	AupcDlyMs int
+}
+
+func GetFlrInf (ctx *context.Context, logger *slog.Logger, conn *pgxpool.Conn, tntId int, aurNm string) ([]FlrInf, error) {
+	const (
+		dbSchema = "web_core_unauth_ssn_aur_reg"
+		dbFunc   = "flr_inf"
+	)
+
+	results, err := db.DataSet[FlrInf](ctx, logger, conn, func(ctx *context.Context, tx *pgx.Tx)(string, string, *pgx.Rows, error){
+		qry := fmt.Sprintf("select %v.%v($1, $2, $3)", dbSchema, dbFunc)
+
+		call, err := (*tx).Query(*ctx, qry, dbFunc, tntId, aurNm)
+		if err != nil {
+			slog.LogAttrs(*ctx, slog.LevelError, "GetFlrInf::get dataset",
+				slog.String("error"   , err.Error()),
+				slog.String("qry"     , qry),
+				slog.Int   ("tntId"   , tntId),
+				slog.String("aurNm"   , aurNm),
+			)
+
+			return qry, dbFunc, nil, fmt.Errorf("GetFlrInf::call database function: %w", err)
+		}
+
+		return qry, dbFunc, &call, nil
+	})
+
+	return results, err
+}
+
 type AurNmInf struct {
 	AurNmPass bool
 }
@@ -260,6 +306,37 @@ func GetPlsInf (ctx *context.Context, logger *slog.Logger, conn *pgxpool.Conn, t
 	return results, err
 }
 
+type PldInf struct {
+	PldJs []byte
+}
+
+func GetPldInf (ctx *context.Context, logger *slog.Logger, conn *pgxpool.Conn, tntId int, pkyChallenge string) ([]PldInf, error) {
+	const (
+		dbSchema = "web_core_unauth_ssn_aur_reg"
+		dbFunc   = "pld_inf"
+	)
+
+	results, err := db.DataSet[PldInf](ctx, logger, conn, func(ctx *context.Context, tx *pgx.Tx)(string, string, *pgx.Rows, error){
+		qry := fmt.Sprintf("select %v.%v($1, $2, $3)", dbSchema, dbFunc)
+
+		call, err := (*tx).Query(*ctx, qry, dbFunc, tntId, pkyChallenge)
+		if err != nil {
+			slog.LogAttrs(*ctx, slog.LevelError, "GetPldInf::get dataset",
+				slog.String("error"        , err.Error()),
+				slog.String("qry"          , qry),
+				slog.Int   ("tntId"        , tntId),
+				slog.String("pkyChallenge" , pkyChallenge),
+			)
+
+			return qry, dbFunc, nil, fmt.Errorf("GetPldInf::call database function: %w", err)
+		}
+
+		return qry, dbFunc, &call, nil
+	})
+
+	return results, err
+}
+
 type PwdInf struct {
 	AupcAurPwdMinLen int
 	AupcAurPwdMaxLen int
@@ -347,6 +424,17 @@ func GetPkyAur (ctx *context.Context, conn *pgxpool.Conn, tntId int, aurNm strin
 	return user, nil
 }
 
+// GetHdlAur looks up the user a discoverable passkey belongs to from its
+// user handle, which is the username it was made for, and keeps the
+// username in aurNm.
+func GetHdlAur (ctx *context.Context, conn *pgxpool.Conn, tntId int, aurNm *string) webauthn.DiscoverableUserHandler {
+	return func(rawId []byte, userHandle []byte) (webauthn.User, error) {
+		*aurNm = strings.ToLower(strings.TrimSpace(string(userHandle)))
+
+		return GetPkyAur(ctx, conn, tntId, *aurNm)
+	}
+}
+
 type S2sInf struct {
 	SsoUrl      string
 	S2cEntityId string
@@ -432,3 +520,200 @@ func PostPls (ctx *context.Context, logger *slog.Logger, conn *pgxpool.Conn, tnt
 
 	return nil
 }
+
+// PostPld keeps the session data of a sign in with a discoverable passkey,
+// which isn't tied to a username until the passkey is used.
+func PostPld (ctx *context.Context, logger *slog.Logger, conn *pgxpool.Conn, tntId int, pldChallenge string, pldJs []byte, exptErrs []string) error {
+	var (
+		sprocCall   = "call web_core_unauth_ssn_aur_reg.reg_pld(@p_tnt_id, @p_pld_challenge, @p_pld_js)"
+		sprocParams = pgx.NamedArgs{
+			"p_tnt_id"        : tntId,
+			"p_pld_challenge" : pldChallenge,
+			"p_pld_js"        : pldJs,
+		}
+	)
+
+	sprocErr := db.Sproc(ctx, logger, conn, sprocCall, sprocParams, exptErrs)
+	if sprocErr != nil {
+		logger.LogAttrs(*ctx, slog.LevelDebug, "call sproc",
+			slog.String("sprocCall"     , sprocCall),
+			slog.String("error"         , sprocErr.Error()),
+			slog.Int   ("tntId"         , tntId),
+			slog.String("pldChallenge"  , pldChallenge),
+			slog.Any   ("pldJs"         , pldJs),
+			slog.Any   ("exptErrs"      , exptErrs),
+		)
+
+		return sprocErr
+	}
+
+	return nil
+}
+
+func PostFlr (ctx *context.Context, logger *slog.Logger, conn *pgxpool.Conn, tntId int, aurNm string, exptErrs []string) error {
+	var (
+		sprocCall   = "call web_core_unauth_ssn_aur_reg.reg_flr(@p_tnt_id, @p_aur_nm)"
+		sprocParams = pgx.NamedArgs{
+			"p_tnt_id" : tntId,
+			"p_aur_nm" : aurNm,
+		}
+	)
+
+	sprocErr := db.Sproc(ctx, logger, conn, sprocCall, sprocParams, exptErrs)
+	if sprocErr != nil {
+		logger.LogAttrs(*ctx, slog.LevelDebug, "call sproc",
+			slog.String("sprocCall" , sprocCall),
+			slog.String("error"     , sprocErr.Error()),
+			slog.Int   ("tntId"     , tntId),
+			slog.String("aurNm"     , aurNm),
+			slog.Any   ("exptErrs"  , exptErrs),
+		)
+
+		return sprocErr
+	}
+
+	return nil
+}
+
+func DelFlr (ctx *context.Context, logger *slog.Logger, conn *pgxpool.Conn, tntId int, aurNm string, exptErrs []string) error {
+	var (
+		sprocCall   = "call web_core_unauth_ssn_aur_reg.del_flr(@p_tnt_id, @p_aur_nm)"
+		sprocParams = pgx.NamedArgs{
+			"p_tnt_id" : tntId,
+			"p_aur_nm" : aurNm,
+		}
+	)
+
+	sprocErr := db.Sproc(ctx, logger, conn, sprocCall, sprocParams, exptErrs)
+	if sprocErr != nil {
+		logger.LogAttrs(*ctx, slog.LevelDebug, "call sproc",
+			slog.String("sprocCall" , sprocCall),
+			slog.String("error"     , sprocErr.Error()),
+			slog.Int   ("tntId"     , tntId),
+			slog.String("aurNm"     , aurNm),
+			slog.Any   ("exptErrs"  , exptErrs),
+		)
+
+		return sprocErr
+	}
+
+	return nil
+}
+
+// DelPld uses up the session data of a sign in with a discoverable passkey,
+// so that its challenge can only be answered once.
+func DelPld (ctx *context.Context, logger *slog.Logger, conn *pgxpool.Conn, tntId int, pldChallenge string, exptErrs []string) error {
+	var (
+		sprocCall   = "call web_core_unauth_ssn_aur_reg.del_pld(@p_tnt_id, @p_pld_challenge)"
+		sprocParams = pgx.NamedArgs{
+			"p_tnt_id"        : tntId,
+			"p_pld_challenge" : pldChallenge,
+		}
+	)
+
+	sprocErr := db.Sproc(ctx, logger, conn, sprocCall, sprocParams, exptErrs)
+	if sprocErr != nil {
+		logger.LogAttrs(*ctx, slog.LevelDebug, "call sproc",
+			slog.String("sprocCall"    , sprocCall),
+			slog.String("error"        , sprocErr.Error()),
+			slog.Int   ("tntId"        , tntId),
+			slog.String("pldChallenge" , pldChallenge),
+			slog.Any   ("exptErrs"     , exptErrs),
+		)
+
+		return sprocErr
+	}
+
+	return nil
+}
+
+// PatchPky records that the passkey was just used to sign in, with the sign
+// count, backup state and clone warning its authenticator reported.
+func PatchPky (ctx *context.Context, logger *slog.Logger, conn *pgxpool.Conn, tntId int, pkyCredentialId []byte, pkySignCount int, pkyBackupState bool, pkyCloneWarning bool, exptErrs []string) error {
+	var (
+		sprocCall   = "call web_core_unauth_ssn_aur_reg.mod_pky(@p_tnt_id, @p_pky_credential_id, @p_pky_sign_count, @p_pky_backup_state, @p_pky_clone_warning)"
+		sprocParams = pgx.NamedArgs{
+			"p_tnt_id"            : tntId,
+			"p_pky_credential_id" : pkyCredentialId,
+			"p_pky_sign_count"    : pkySignCount,
+			"p_pky_backup_state"  : pkyBackupState,
+			"p_pky_clone_warning" : pkyCloneWarning,
+		}
+	)
+
+	sprocErr := db.Sproc(ctx, logger, conn, sprocCall, sprocParams, exptErrs)
+	if sprocErr != nil {
+		logger.LogAttrs(*ctx, slog.LevelDebug, "call sproc",
+			slog.String("sprocCall"       , sprocCall),
+			slog.String("error"           , sprocErr.Error()),
+			slog.Int   ("tntId"           , tntId),
+			slog.Any   ("pkyCredentialId" , pkyCredentialId),
+			slog.Int   ("pkySignCount"    , pkySignCount),
+			slog.Bool  ("pkyBackupState"  , pkyBackupState),
+			slog.Bool  ("pkyCloneWarning" , pkyCloneWarning),
+			slog.Any   ("exptErrs"        , exptErrs),
+		)
+
+		return sprocErr
+	}
+
+	return nil
+}
+
+// PatchAurHshPw replaces the password hash of a user who has just signed in
+// with one made with the current algorithm and parameters.
+func PatchAurHshPw (ctx *context.Context, logger *slog.Logger, conn *pgxpool.Conn, tntId int, aurId int, aurHshPw string, exptErrs []string) error {
+	var (
+		sprocCall   = "call web_core_unauth_ssn_aur_reg.mod_aur_hsh_pw(@p_tnt_id, @p_aur_id, @p_aur_hsh_pw)"
+		sprocParams = pgx.NamedArgs{
+			"p_tnt_id"     : tntId,
+			"p_aur_id"     : aurId,
+			"p_aur_hsh_pw" : aurHshPw,
+		}
+	)
+
+	sprocErr := db.Sproc(ctx, logger, conn, sprocCall, sprocParams, exptErrs)
+	if sprocErr != nil {
+		logger.LogAttrs(*ctx, slog.LevelDebug, "call sproc",
+			slog.String("sprocCall" , sprocCall),
+			slog.String("error"     , sprocErr.Error()),
+			slog.Int   ("tntId"     , tntId),
+			slog.Int   ("aurId"     , aurId),
+			slog.Any   ("exptErrs"  , exptErrs),
+		)
+
+		return sprocErr
+	}
+
+	return nil
+}
+
+// PostPwr saves the hash of a password reset token that expires after
+// pwrMins minutes, for a user whose password has expired.
+func PostPwr (ctx *context.Context, logger *slog.Logger, conn *pgxpool.Conn, tntId int, aurId int, pwrHsh string, pwrMins int, exptErrs []string) error {
+	var (
+		sprocCall   = "call web_core_unauth_ssn_aur_reg.reg_pwr(@p_tnt_id, @p_aur_id, @p_pwr_hsh, @p_pwr_mins)"
+		sprocParams = pgx.NamedArgs{
+			"p_tnt_id"   : tntId,
+			"p_aur_id"   : aurId,
+			"p_pwr_hsh"  : pwrHsh,
+			"p_pwr_mins" : pwrMins,
+		}
+	)
+
+	sprocErr := db.Sproc(ctx, logger, conn, sprocCall, sprocParams, exptErrs)
+	if sprocErr != nil {
+		logger.LogAttrs(*ctx, slog.LevelDebug, "call sproc",
+			slog.String("sprocCall" , sprocCall),
+			slog.String("error"     , sprocErr.Error()),
+			slog.Int   ("tntId"     , tntId),
+			slog.Int   ("aurId"     , aurId),
+			slog.Int   ("pwrMins"   , pwrMins),
+			slog.Any   ("exptErrs"  , exptErrs),
+		)
+
+		return sprocErr
+	}
+
+	return nil
+}
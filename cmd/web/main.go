@@ -12,13 +12,20 @@ import (
 )
 
 import (
-	"github.com/andrewah64/base-app-client/internal/common/core/db"
-	"github.com/andrewah64/base-app-client/internal/common/core/session"
-	"github.com/andrewah64/base-app-client/internal/common/core/startup"
-	"github.com/andrewah64/base-app-client/internal/web/core/passkey"
-	"github.com/andrewah64/base-app-client/internal/web/core/route"
-	"github.com/andrewah64/base-app-client/internal/web/core/ui/html"
-	"github.com/andrewah64/base-app-client/internal/web/core/ui/i18n"
+	   "github.com/andrewah64/base-app-client/internal/common/core/db"
+	   "github.com/andrewah64/base-app-client/internal/common/core/mail"
+	   "github.com/andrewah64/base-app-client/internal/common/core/session"
+	   "github.com/andrewah64/base-app-client/internal/common/core/startup"
+	   "github.com/andrewah64/base-app-client/internal/web/core/brand"
+	   "github.com/andrewah64/base-app-client/internal/web/core/feature"
+	   "github.com/andrewah64/base-app-client/internal/web/core/passkey"
+	   "github.com/andrewah64/base-app-client/internal/web/core/refresh"
+	   "github.com/andrewah64/base-app-client/internal/web/core/route"
+	ws "github.com/andrewah64/base-app-client/internal/web/core/session"
+	   "github.com/andrewah64/base-app-client/internal/web/core/ui/asset"
+	   "github.com/andrewah64/base-app-client/internal/web/core/ui/html"
+	   "github.com/andrewah64/base-app-client/internal/web/core/ui/i18n"
+	   "github.com/andrewah64/base-app-client/ui"
 )
 
 import (
@@ -28,7 +35,12 @@ import (
 	authaurgrptnt    "github.com/andrewah64/base-app-client/cmd/web/core/auth/aur/grp/tnt"
 	authaurtnt       "github.com/andrewah64/base-app-client/cmd/web/core/auth/aur/tnt"
 	authaurtntid     "github.com/andrewah64/base-app-client/cmd/web/core/auth/aur/tnt/id"
+	authaurtntidlck  "github.com/andrewah64/base-app-client/cmd/web/core/auth/aur/tnt/id/lck"
+	authaurtntidotp  "github.com/andrewah64/base-app-client/cmd/web/core/auth/aur/tnt/id/otp"
 	authaurtntval    "github.com/andrewah64/base-app-client/cmd/web/core/auth/aur/tnt/val"
+	authcfgtnt       "github.com/andrewah64/base-app-client/cmd/web/core/auth/cfg/tnt"
+	authflgtnt       "github.com/andrewah64/base-app-client/cmd/web/core/auth/flg/tnt"
+	authflgtntid     "github.com/andrewah64/base-app-client/cmd/web/core/auth/flg/tnt/id"
 	authgrpaurtnt    "github.com/andrewah64/base-app-client/cmd/web/core/auth/grp/aur/tnt"
 	authgrptnt       "github.com/andrewah64/base-app-client/cmd/web/core/auth/grp/tnt"
 	authgrptntid     "github.com/andrewah64/base-app-client/cmd/web/core/auth/grp/tnt/id"
@@ -43,6 +55,9 @@ import (
 	authocctnt       "github.com/andrewah64/base-app-client/cmd/web/core/auth/occ/tnt"
 	authpwdaurtnt    "github.com/andrewah64/base-app-client/cmd/web/core/auth/pwd/aur/tnt"
 	authpwdaurtntval "github.com/andrewah64/base-app-client/cmd/web/core/auth/pwd/aur/tnt/val"
+	authpkyaur       "github.com/andrewah64/base-app-client/cmd/web/core/auth/pky/aur"
+	authpkyaurid     "github.com/andrewah64/base-app-client/cmd/web/core/auth/pky/aur/id"
+	authrcvaur       "github.com/andrewah64/base-app-client/cmd/web/core/auth/rcv/aur"
 	authrolgrptnt    "github.com/andrewah64/base-app-client/cmd/web/core/auth/rol/grp/tnt"
 	authrolkeyaur    "github.com/andrewah64/base-app-client/cmd/web/core/auth/rol/key/aur"
 	auths2ctnt       "github.com/andrewah64/base-app-client/cmd/web/core/auth/s2c/tnt"
@@ -50,11 +65,17 @@ import (
 	auths2ctntval    "github.com/andrewah64/base-app-client/cmd/web/core/auth/s2c/tnt/val"
 	authssntnt       "github.com/andrewah64/base-app-client/cmd/web/core/auth/ssn/tnt"
 	authssnaur       "github.com/andrewah64/base-app-client/cmd/web/core/auth/ssn/aur"
+	authtnt          "github.com/andrewah64/base-app-client/cmd/web/core/auth/tnt"
+	authtntid        "github.com/andrewah64/base-app-client/cmd/web/core/auth/tnt/id"
 	unauthaurtnt     "github.com/andrewah64/base-app-client/cmd/web/core/unauth/aur/tnt"
 	unauthaurtntval  "github.com/andrewah64/base-app-client/cmd/web/core/unauth/aur/tnt/val"
+	unauthevraur     "github.com/andrewah64/base-app-client/cmd/web/core/unauth/evr/aur"
+	unauthevraurid   "github.com/andrewah64/base-app-client/cmd/web/core/unauth/evr/aur/id"
 	unauthoidc       "github.com/andrewah64/base-app-client/cmd/web/core/unauth/oidc"
 	unauthotpaur     "github.com/andrewah64/base-app-client/cmd/web/core/unauth/otp/aur"
 	unauthotpssnaur  "github.com/andrewah64/base-app-client/cmd/web/core/unauth/otp/ssn/aur"
+	unauthpwdaur     "github.com/andrewah64/base-app-client/cmd/web/core/unauth/pwd/aur"
+	unauthpwdaurid   "github.com/andrewah64/base-app-client/cmd/web/core/unauth/pwd/aur/id"
 	unauthsaml2acs   "github.com/andrewah64/base-app-client/cmd/web/core/unauth/saml2/acs"
 	unauthssnaur     "github.com/andrewah64/base-app-client/cmd/web/core/unauth/ssn/aur"
 )
@@ -73,10 +94,36 @@ func main() {
 
 	startup.SetupDefaultLogger(*rtp.LogLvl)
 
+	startup.SetupPassword(ctx, rtp)
+
 	pool := startup.SetupPGConnectionPool(ctx, rtp)
 
 	defer pool.Close()
 
+	ssnCacheErr := ws.InitCache(&ctx, pool, *rtp.SsnCacheSize, *rtp.SsnCacheTtl)
+	if ssnCacheErr != nil {
+		slog.LogAttrs(ctx, slog.LevelError, "initialise the session cache",
+			slog.String("error", ssnCacheErr.Error()),
+		)
+
+		panic(ssnCacheErr)
+	}
+
+	if *rtp.Dev {
+		slog.LogAttrs(ctx, slog.LevelWarn, "dev mode, serve the UI from disk")
+
+		ui.UseDir("ui")
+	}
+
+	astCacheErr := asset.InitCache(ctx)
+	if astCacheErr != nil {
+		slog.LogAttrs(ctx, slog.LevelError, "initialise the asset cache",
+			slog.String("error", astCacheErr.Error()),
+		)
+
+		panic(astCacheErr)
+	}
+
 	html.InitCache(ctx)
 
 	i18nCacheErr := i18n.InitCache(ctx, language.English)
@@ -110,6 +157,60 @@ func main() {
 		panic(pkeyCacheErr)
 	}
 
+	pkyAaguidErr := passkey.LoadAaguids(&ctx, *rtp.PkyAaguid)
+	if pkyAaguidErr != nil {
+		slog.LogAttrs(ctx, slog.LevelError, "load the passkey authenticator names",
+			slog.String("error", pkyAaguidErr.Error()),
+		)
+
+		panic(pkyAaguidErr)
+	}
+
+	pkyMdsErr := passkey.LoadMds(&ctx, *rtp.PkyMds)
+	if pkyMdsErr != nil {
+		slog.LogAttrs(ctx, slog.LevelError, "load the FIDO metadata",
+			slog.String("error", pkyMdsErr.Error()),
+		)
+
+		panic(pkyMdsErr)
+	}
+
+	brdIdErr := session.Identity(&ctx, slog.Default(), conn, "role_web_core_unauth_brd_tnt_inf")
+	if brdIdErr != nil {
+		slog.LogAttrs(ctx, slog.LevelError, "initialise the brand cache",
+			slog.String("error", brdIdErr.Error()),
+		)
+
+		panic(brdIdErr)
+	}
+
+	brdCacheErr := brand.InitCache(&ctx, conn)
+	if brdCacheErr != nil {
+		slog.LogAttrs(ctx, slog.LevelError, "initialise the brand cache",
+			slog.String("error", brdCacheErr.Error()),
+		)
+
+		panic(brdCacheErr)
+	}
+
+	flgIdErr := session.Identity(&ctx, slog.Default(), conn, "role_web_core_unauth_flg_tnt_inf")
+	if flgIdErr != nil {
+		slog.LogAttrs(ctx, slog.LevelError, "initialise the feature flag cache",
+			slog.String("error", flgIdErr.Error()),
+		)
+
+		panic(flgIdErr)
+	}
+
+	flgCacheErr := feature.InitCache(&ctx, conn)
+	if flgCacheErr != nil {
+		slog.LogAttrs(ctx, slog.LevelError, "initialise the feature flag cache",
+			slog.String("error", flgCacheErr.Error()),
+		)
+
+		panic(flgCacheErr)
+	}
+
 	rtsIdErr := session.Identity(&ctx, slog.Default(), conn, "role_web_core_unauth_rts_web_inf")
 	if rtsIdErr != nil {
 		slog.LogAttrs(ctx, slog.LevelError, "initialise the route cache",
@@ -128,6 +229,12 @@ func main() {
 		panic(rtsCacheErr)
 	}
 
+	refresh.Listen(&ctx, pool)
+
+	feature.Listen(&ctx, pool)
+
+	mail.Relay(&ctx, pool, startup.SetupMailTransport(ctx, rtp))
+
 	tlsConfig := &tls.Config{
 		CurvePreferences: []tls.CurveID{tls.X25519, tls.CurveP256},
 		MinVersion      : tls.VersionTLS13,
@@ -135,78 +242,116 @@ func main() {
 
 	var (
 		handlers = map[string]http.HandlerFunc{
-			"web.core.auth.aukc.tnt.Get"        : authaukctnt.Get,
-			"web.core.auth.aukc.tnt.Patch"      : authaukctnt.Patch,
-			"web.core.auth.aupc.tnt.Get"        : authaupctnt.Get,
-			"web.core.auth.aupc.tnt.Patch"      : authaupctnt.Patch,
-			"web.core.auth.aur.grp.tnt.Get"     : authaurgrptnt.Get,
-			"web.core.auth.aur.grp.tnt.Patch"   : authaurgrptnt.Patch,
-			"web.core.auth.aur.tnt.Delete"      : authaurtnt.Delete,
-			"web.core.auth.aur.tnt.Get"         : authaurtnt.Get,
-			"web.core.auth.aur.tnt.Post"        : authaurtnt.Post,
-			"web.core.auth.aur.tnt.id.Get"      : authaurtntid.Get,
-			"web.core.auth.aur.tnt.id.Patch"    : authaurtntid.Patch,
-			"web.core.auth.aur.tnt.val.Get"     : authaurtntval.Get,
-			"web.core.auth.grp.aur.tnt.Get"     : authgrpaurtnt.Get,
-			"web.core.auth.grp.aur.tnt.Patch"   : authgrpaurtnt.Patch,
-			"web.core.auth.grp.tnt.Delete"      : authgrptnt.Delete,
-			"web.core.auth.grp.tnt.Get"         : authgrptnt.Get,
-			"web.core.auth.grp.tnt.Post"        : authgrptnt.Post,
-			"web.core.auth.grp.tnt.id.Get"      : authgrptntid.Get,
-			"web.core.auth.grp.tnt.id.Patch"    : authgrptntid.Patch,
-			"web.core.auth.grp.tnt.val.Get"     : authgrptntval.Get,
-			"web.core.auth.home.Index"          : authhome.Get,
-			"web.core.auth.log.aur.tnt.Get"     : authlogaurtnt.Get,
-			"web.core.auth.log.aur.tnt.Put"     : authlogaurtnt.Put,
-			"web.core.auth.log.aur.tnt.id.Get"  : authlogaurtntid.Get,
-			"web.core.auth.log.aur.tnt.id.Patch": authlogaurtntid.Patch,
-			"web.core.auth.log.ep.tnt.Get"      : authlogeptnt.Get,
-			"web.core.auth.log.ep.tnt.Put"      : authlogeptnt.Put,
-			"web.core.auth.log.ep.tnt.id.Get"   : authlogeptntid.Get,
-			"web.core.auth.log.ep.tnt.id.Patch" : authlogeptntid.Patch,
-			"web.core.auth.key.aur.Get"         : authkeyaur.Get,
-			"web.core.auth.key.aur.Post"        : authkeyaur.Post,
-			"web.core.auth.key.aur.Delete"      : authkeyaur.Delete,
-			"web.core.auth.key.aur.id.Get"      : authkeyaurid.Get,
-			"web.core.auth.key.aur.id.Patch"    : authkeyaurid.Patch,
-			"web.core.auth.key.aur.val.Get"     : authkeyaurval.Get,
-			"web.core.auth.occ.tnt.Get"         : authocctnt.Get,
-			"web.core.auth.occ.tnt.Patch"       : authocctnt.Patch,
-			"web.core.auth.pwd.aur.tnt.Get"     : authpwdaurtnt.Get,
-			"web.core.auth.pwd.aur.tnt.Patch"   : authpwdaurtnt.Patch,
-			"web.core.auth.pwd.aur.tnt.val.Get" : authpwdaurtntval.Get,
-			"web.core.auth.rol.grp.tnt.Get"     : authrolgrptnt.Get,
-			"web.core.auth.rol.grp.tnt.Patch"   : authrolgrptnt.Patch,
-			"web.core.auth.rol.key.aur.Get"     : authrolkeyaur.Get,
-			"web.core.auth.rol.key.aur.Patch"   : authrolkeyaur.Patch,
-			"web.core.auth.s2c.tnt.Delete"      : auths2ctnt.Delete,
-			"web.core.auth.s2c.tnt.Get"         : auths2ctnt.Get,
-			"web.core.auth.s2c.tnt.id.Get"      : auths2ctntid.Get,
-			"web.core.auth.s2c.tnt.id.Patch"    : auths2ctntid.Patch,
-			"web.core.auth.s2c.tnt.val.Get"     : auths2ctntval.Get,
-			"web.core.auth.s2c.tnt.Patch"       : auths2ctnt.Patch,
-			"web.core.auth.s2c.tnt.Post"        : auths2ctnt.Post,
-			"web.core.auth.ssn.tnt.Get"         : authssntnt.Get,
-			"web.core.auth.ssn.tnt.Delete"      : authssntnt.Delete,
-			"web.core.auth.ssn.aur.Delete"      : authssnaur.Delete,
-			"web.core.unauth.aur.tnt.Get"       : unauthaurtnt.Get,
-			"web.core.unauth.aur.tnt.Post"      : unauthaurtnt.Post,
-			"web.core.unauth.aur.tnt.val.Get"   : unauthaurtntval.Get,
-			"web.core.unauth.oidc.Call"         : unauthoidc.Call,
-			"web.core.unauth.oidc.Callback"     : unauthoidc.Callback,
-			"web.core.unauth.otp.aur.Get"       : unauthotpaur.Get,
-			"web.core.unauth.otp.aur.Post"      : unauthotpaur.Post,
-			"web.core.unauth.otp.ssn.aur.Get"   : unauthotpssnaur.Get,
-			"web.core.unauth.otp.ssn.aur.Post"  : unauthotpssnaur.Post,
-			"web.core.unauth.saml2.acs.Post"    : unauthsaml2acs.Post,
-			"web.core.unauth.ssn.aur.Get"       : unauthssnaur.Get,
-			"web.core.unauth.ssn.aur.Post"      : unauthssnaur.Post,
+			"web.core.auth.aukc.tnt.Get"          : authaukctnt.Get,
+			"web.core.auth.aukc.tnt.Patch"        : authaukctnt.Patch,
+			"web.core.auth.aupc.tnt.Get"          : authaupctnt.Get,
+			"web.core.auth.aupc.tnt.Patch"        : authaupctnt.Patch,
+			"web.core.auth.aur.grp.tnt.Get"       : authaurgrptnt.Get,
+			"web.core.auth.aur.grp.tnt.Patch"     : authaurgrptnt.Patch,
+			"web.core.auth.aur.tnt.Delete"        : authaurtnt.Delete,
+			"web.core.auth.aur.tnt.Get"           : authaurtnt.Get,
+			"web.core.auth.aur.tnt.Post"          : authaurtnt.Post,
+			"web.core.auth.aur.tnt.id.Get"        : authaurtntid.Get,
+			"web.core.auth.aur.tnt.id.Patch"      : authaurtntid.Patch,
+			"web.core.auth.aur.tnt.id.lck.Delete" : authaurtntidlck.Delete,
+			"web.core.auth.aur.tnt.id.otp.Delete" : authaurtntidotp.Delete,
+			"web.core.auth.aur.tnt.val.Get"       : authaurtntval.Get,
+			"web.core.auth.cfg.tnt.Get"           : authcfgtnt.Get,
+			"web.core.auth.cfg.tnt.Post"          : authcfgtnt.Post,
+			"web.core.auth.cfg.tnt.Put"           : authcfgtnt.Put,
+			"web.core.auth.flg.tnt.Delete"        : authflgtnt.Delete,
+			"web.core.auth.flg.tnt.Get"           : authflgtnt.Get,
+			"web.core.auth.flg.tnt.Post"          : authflgtnt.Post,
+			"web.core.auth.flg.tnt.id.Get"        : authflgtntid.Get,
+			"web.core.auth.flg.tnt.id.Patch"      : authflgtntid.Patch,
+			"web.core.auth.grp.aur.tnt.Get"       : authgrpaurtnt.Get,
+			"web.core.auth.grp.aur.tnt.Patch"     : authgrpaurtnt.Patch,
+			"web.core.auth.grp.tnt.Delete"        : authgrptnt.Delete,
+			"web.core.auth.grp.tnt.Get"           : authgrptnt.Get,
+			"web.core.auth.grp.tnt.Post"          : authgrptnt.Post,
+			"web.core.auth.grp.tnt.id.Get"        : authgrptntid.Get,
+			"web.core.auth.grp.tnt.id.Patch"      : authgrptntid.Patch,
+			"web.core.auth.grp.tnt.val.Get"       : authgrptntval.Get,
+			"web.core.auth.home.Index"            : authhome.Get,
+			"web.core.auth.log.aur.tnt.Get"       : authlogaurtnt.Get,
+			"web.core.auth.log.aur.tnt.Put"       : authlogaurtnt.Put,
+			"web.core.auth.log.aur.tnt.id.Get"    : authlogaurtntid.Get,
+			"web.core.auth.log.aur.tnt.id.Patch"  : authlogaurtntid.Patch,
+			"web.core.auth.log.ep.tnt.Get"        : authlogeptnt.Get,
+			"web.core.auth.log.ep.tnt.Put"        : authlogeptnt.Put,
+			"web.core.auth.log.ep.tnt.id.Get"     : authlogeptntid.Get,
+			"web.core.auth.log.ep.tnt.id.Patch"   : authlogeptntid.Patch,
+			"web.core.auth.key.aur.Get"           : authkeyaur.Get,
+			"web.core.auth.key.aur.Post"          : authkeyaur.Post,
+			"web.core.auth.key.aur.Delete"        : authkeyaur.Delete,
+			"web.core.auth.key.aur.id.Get"        : authkeyaurid.Get,
+			"web.core.auth.key.aur.id.Patch"      : authkeyaurid.Patch,
+			"web.core.auth.key.aur.val.Get"       : authkeyaurval.Get,
+			"web.core.auth.occ.tnt.Get"           : authocctnt.Get,
+			"web.core.auth.occ.tnt.Patch"         : authocctnt.Patch,
+			"web.core.auth.pwd.aur.tnt.Get"       : authpwdaurtnt.Get,
+			"web.core.auth.pwd.aur.tnt.Patch"     : authpwdaurtnt.Patch,
+			"web.core.auth.pwd.aur.tnt.val.Get"   : authpwdaurtntval.Get,
+			"web.core.auth.pky.aur.Get"           : authpkyaur.Get,
+			"web.core.auth.pky.aur.Post"          : authpkyaur.Post,
+			"web.core.auth.pky.aur.Delete"        : authpkyaur.Delete,
+			"web.core.auth.pky.aur.id.Get"        : authpkyaurid.Get,
+			"web.core.auth.pky.aur.id.Patch"      : authpkyaurid.Patch,
+			"web.core.auth.rcv.aur.Get"           : authrcvaur.Get,
+			"web.core.auth.rcv.aur.Post"          : authrcvaur.Post,
+			"web.core.auth.rol.grp.tnt.Get"       : authrolgrptnt.Get,
+			"web.core.auth.rol.grp.tnt.Patch"     : authrolgrptnt.Patch,
+			"web.core.auth.rol.key.aur.Get"       : authrolkeyaur.Get,
+			"web.core.auth.rol.key.aur.Patch"     : authrolkeyaur.Patch,
+			"web.core.auth.s2c.tnt.Delete"        : auths2ctnt.Delete,
+			"web.core.auth.s2c.tnt.Get"           : auths2ctnt.Get,
+			"web.core.auth.s2c.tnt.id.Get"        : auths2ctntid.Get,
+			"web.core.auth.s2c.tnt.id.Patch"      : auths2ctntid.Patch,
+			"web.core.auth.s2c.tnt.val.Get"       : auths2ctntval.Get,
+			"web.core.auth.s2c.tnt.Patch"         : auths2ctnt.Patch,
+			"web.core.auth.s2c.tnt.Post"          : auths2ctnt.Post,
+			"web.core.auth.ssn.tnt.Get"           : authssntnt.Get,
+			"web.core.auth.ssn.tnt.Delete"        : authssntnt.Delete,
+			"web.core.auth.ssn.aur.Delete"        : authssnaur.Delete,
+			"web.core.auth.tnt.Get"               : authtnt.Get,
+			"web.core.auth.tnt.Post"              : authtnt.Post,
+			"web.core.auth.tnt.id.Get"            : authtntid.Get,
+			"web.core.auth.tnt.id.Patch"          : authtntid.Patch,
+			"web.core.unauth.aur.tnt.Get"         : unauthaurtnt.Get,
+			"web.core.unauth.aur.tnt.Post"        : unauthaurtnt.Post,
+			"web.core.unauth.aur.tnt.val.Get"     : unauthaurtntval.Get,
+			"web.core.unauth.evr.aur.Get"         : unauthevraur.Get,
+			"web.core.unauth.evr.aur.Post"        : unauthevraur.Post,
+			"web.core.unauth.evr.aur.id.Get"      : unauthevraurid.Get,
+			"web.core.unauth.evr.aur.id.Post"     : unauthevraurid.Post,
+			"web.core.unauth.oidc.Call"           : unauthoidc.Call,
+			"web.core.unauth.oidc.Callback"       : unauthoidc.Callback,
+			"web.core.unauth.otp.aur.Get"         : unauthotpaur.Get,
+			"web.core.unauth.otp.aur.Post"        : unauthotpaur.Post,
+			"web.core.unauth.otp.ssn.aur.Get"     : unauthotpssnaur.Get,
+			"web.core.unauth.otp.ssn.aur.Post"    : unauthotpssnaur.Post,
+			"web.core.unauth.pwd.aur.Get"         : unauthpwdaur.Get,
+			"web.core.unauth.pwd.aur.Post"        : unauthpwdaur.Post,
+			"web.core.unauth.pwd.aur.id.Get"      : unauthpwdaurid.Get,
+			"web.core.unauth.pwd.aur.id.Post"     : unauthpwdaurid.Post,
+			"web.core.unauth.saml2.acs.Post"      : unauthsaml2acs.Post,
+			"web.core.unauth.ssn.aur.Get"         : unauthssnaur.Get,
+			"web.core.unauth.ssn.aur.Post"        : unauthssnaur.Post,
 		}
 	)
 
+	mux, muxErr := route.Mux(&ctx, handlers, route.Chains(*rtp.CspRptOnly, startup.SetupRateLimitStore(ctx, rtp, pool)))
+	if muxErr != nil {
+		slog.LogAttrs(ctx, slog.LevelError, "register the routes",
+			slog.String("error", muxErr.Error()),
+		)
+
+		panic(muxErr)
+	}
+
 	server := &http.Server{
 		Addr        :	fmt.Sprintf(":%d", *rtp.HttpPort),
-		Handler     :	route.Mux(&ctx, handlers),
+		Handler     :	mux,
 		BaseContext :	func(_ net.Listener) context.Context {
 					return db.NewContext(
 						context.Background(),
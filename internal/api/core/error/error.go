@@ -49,6 +49,10 @@ func NotAuth(ctx context.Context, rw http.ResponseWriter, err error) {
 	manage(ctx, rw, http.StatusUnauthorized, err)
 }
 
+func TooMany(ctx context.Context, rw http.ResponseWriter, err error) {
+	manage(ctx, rw, http.StatusTooManyRequests, err)
+}
+
 func ValErr(ctx context.Context, rw http.ResponseWriter, errors map[string]string) {
 	manage(ctx, rw, http.StatusUnprocessableEntity, errors)
 }
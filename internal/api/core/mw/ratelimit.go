@@ -0,0 +1,15 @@
+package mw
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
+
+import (
+	"github.com/andrewah64/base-app-client/internal/api/core/error"
+)
+
+func Throttled(rw http.ResponseWriter, r *http.Request, retryAfter time.Duration) {
+	error.TooMany(r.Context(), rw, fmt.Errorf("too many requests, retry after %v", retryAfter.Round(time.Second)))
+}
@@ -13,23 +13,84 @@ import (
 )
 
 import (
-	"github.com/andrewah64/base-app-client/internal/common/core/routes"
-	"github.com/andrewah64/base-app-client/internal/api/core/mw"
+	   "github.com/andrewah64/base-app-client/internal/common/core/chain"
+	cm "github.com/andrewah64/base-app-client/internal/common/core/mw"
+	   "github.com/andrewah64/base-app-client/internal/common/core/ratelimit"
+	   "github.com/andrewah64/base-app-client/internal/common/core/routes"
+	   "github.com/andrewah64/base-app-client/internal/api/core/mw"
 )
 
-func Mux(ctx *context.Context, handlers map[string]http.HandlerFunc) (http.Handler) {
+// Routes without a chain are authorised, as all api routes used to be.
+const (
+	defaultChain = "api/auth"
+)
+
+// Chains returns the middleware and chains that api routes can refer to.
+// API keys are rate limited before they are checked with, e.g.
+// "api/public+ratelimit(ip=60/1m,apikey=30/1m)+authorise".
+func Chains(store ratelimit.Store) *chain.Registry {
+	return chain.NewRegistry().
+		Middleware("recover"   , mw.Recover).
+		Middleware("authorise" , mw.Authorise).
+		Factory   ("ratelimit" , func(args string) (alice.Constructor, error) {
+			rules, err := ratelimit.Parse(args)
+			if err != nil {
+				return nil, err
+			}
+
+			return ratelimit.Handler(store, rules, mw.Throttled), nil
+		}).
+		Chain("api/auth"       , "recover", "authorise").
+		Chain("api/public"     , "recover")
+}
+
+func Mux(ctx *context.Context, handlers map[string]http.HandlerFunc, chains *chain.Registry) (http.Handler, error) {
 	slog.LogAttrs(*ctx, slog.LevelInfo, "load routes")
 
+	if err := chains.Validate(); err != nil {
+		slog.LogAttrs(*ctx, slog.LevelError, "validate middleware chains",
+			slog.String("error" , err.Error()),
+		)
+
+		return nil, err
+	}
+
 	mux    := http.NewServeMux()
-	always := alice.New(mw.Recover, mw.Authorise)
+	always := alice.New(cm.Compress)
 
 	cache := routes.CacheCopy()
 
 	for _, v := range cache {
-		mux.Handle(fmt.Sprintf("%v %v", v.HTTPRequestMethod, v.EndpointPath), handlers[v.Handler])
+		name := v.MiddlewareChain
+		if name == "" {
+			name = defaultChain
+		}
+
+		slog.LogAttrs(*ctx, slog.LevelInfo, "register api route",
+			slog.String("HTTPRequestMethod", v.HTTPRequestMethod),
+			slog.String("EndpointPath"     , v.EndpointPath),
+			slog.String("MiddlewareChain"  , name),
+		)
+
+		c, cErr := chains.Resolve(name)
+		if cErr != nil {
+			slog.LogAttrs(*ctx, slog.LevelError, "resolve middleware chain",
+				slog.String("EndpointPath" , v.EndpointPath),
+				slog.String("error"        , cErr.Error()),
+			)
+
+			return nil, fmt.Errorf("route '%v %v': %w", v.HTTPRequestMethod, v.EndpointPath, cErr)
+		}
+
+		h, ok := handlers[v.Handler]
+		if ! ok {
+			return nil, fmt.Errorf("route '%v %v': unknown handler '%v'", v.HTTPRequestMethod, v.EndpointPath, v.Handler)
+		}
+
+		mux.Handle(fmt.Sprintf("%v %v", v.HTTPRequestMethod, v.EndpointPath), c.Then(h))
 	}
 
-	return always.Then(mux)
+	return always.Then(mux), nil
 }
 
 func InitCache(ctx *context.Context, conn *pgxpool.Conn) error {
@@ -0,0 +1,113 @@
+package chain
+
+import (
+	"fmt"
+	"strings"
+)
+
+import (
+	"github.com/justinas/alice"
+)
+
+// Registry holds named middleware and the chains built from them. A route
+// refers to a chain by name, optionally followed by extra middleware joined
+// with "+", e.g. "web/auth+ratelimited" is the "web/auth" chain with the
+// "ratelimited" middleware added at the end. Middleware made by a factory is
+// written with its arguments, e.g. "web/unauth+ratelimit(ip=20/1m)".
+type Registry struct {
+	middleware map[string]alice.Constructor
+	factories  map[string]Factory
+	chains     map[string][]string
+}
+
+// Factory makes middleware from the arguments given to it in a chain's name.
+type Factory func(args string) (alice.Constructor, error)
+
+func NewRegistry() *Registry {
+	return &Registry{
+		middleware : make(map[string]alice.Constructor),
+		factories  : make(map[string]Factory),
+		chains     : make(map[string][]string),
+	}
+}
+
+// Middleware registers c under name.
+func (reg *Registry) Middleware(name string, c alice.Constructor) *Registry {
+	reg.middleware[name] = c
+
+	return reg
+}
+
+// Factory registers f under name.
+func (reg *Registry) Factory(name string, f Factory) *Registry {
+	reg.factories[name] = f
+
+	return reg
+}
+
+// Chain defines the chain name as the named middleware, outermost first.
+func (reg *Registry) Chain(name string, middleware ...string) *Registry {
+	reg.chains[name] = middleware
+
+	return reg
+}
+
+// Validate checks that every chain only uses registered middleware.
+func (reg *Registry) Validate() error {
+	for name, middleware := range reg.chains {
+		for _, m := range middleware {
+			if _, err := reg.constructor(m); err != nil {
+				return fmt.Errorf("chain '%v': %w", name, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Resolve returns the chain for name, which is a chain's name optionally
+// followed by "+" and the names of more middleware.
+func (reg *Registry) Resolve(name string) (alice.Chain, error) {
+	parts := strings.Split(name, "+")
+
+	middleware, ok := reg.chains[parts[0]]
+	if ! ok {
+		return alice.Chain{}, fmt.Errorf("unknown middleware chain '%v'", parts[0])
+	}
+
+	cs := make([]alice.Constructor, 0, len(middleware) + len(parts) - 1)
+
+	for _, m := range append(append([]string{}, middleware...), parts[1:]...) {
+		c, cErr := reg.constructor(m)
+		if cErr != nil {
+			return alice.Chain{}, fmt.Errorf("chain '%v': %w", name, cErr)
+		}
+
+		cs = append(cs, c)
+	}
+
+	return alice.New(cs...), nil
+}
+
+func (reg *Registry) constructor(m string) (alice.Constructor, error) {
+	if c, ok := reg.middleware[m]; ok {
+		return c, nil
+	}
+
+	name, args, ok := strings.Cut(m, "(")
+	if ! ok || ! strings.HasSuffix(args, ")") {
+		return nil, fmt.Errorf("unknown middleware '%v'", m)
+	}
+
+	f, ok := reg.factories[name]
+	if ! ok {
+		return nil, fmt.Errorf("unknown middleware factory '%v'", name)
+	}
+
+	c, cErr := f(strings.TrimSuffix(args, ")"))
+	if cErr != nil {
+		return nil, fmt.Errorf("middleware '%v': %w", m, cErr)
+	}
+
+	return c, nil
+}
@@ -0,0 +1,119 @@
+package csp
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+)
+
+const (
+	ReportPath = "/csp-report"
+
+	reportGroup = "csp-endpoint"
+	reportMax   = 64 * 1024
+)
+
+// Policy is a Content-Security-Policy. The source "'nonce'" is replaced by the
+// nonce generated for each request.
+type Policy struct {
+	Directives [][]string
+	ReportOnly bool
+}
+
+type key int
+
+var nonceKey key
+
+// Default is the policy for every page rendered by the web application,
+// signed in or not: scripts and styles from this origin only, plus the nonce
+// and the hash of the indicator styles that htmx injects.
+func Default(reportOnly bool) *Policy {
+	return &Policy{
+		Directives : [][]string{
+			{"default-src"     , "'self'"},
+			{"script-src"      , "'self'", "'nonce'"},
+			{"style-src"       , "'self'", "'sha256-bsV5JivYxvGywDAZ22EZJKBFip65Ng9xoJVLbBg7bdo='"},
+			{"img-src"         , "'self'", "data:", "https:"},
+			{"connect-src"     , "'self'"},
+			{"form-action"     , "'self'"},
+			{"frame-ancestors" , "'none'"},
+			{"base-uri"        , "'self'"},
+			{"object-src"      , "'none'"},
+		},
+		ReportOnly : reportOnly,
+	}
+}
+
+func (p *Policy) String(nonce string) string {
+	ds := make([]string, 0, len(p.Directives) + 2)
+
+	for _, d := range p.Directives {
+		ds = append(ds, strings.ReplaceAll(strings.Join(d, " "), "'nonce'", fmt.Sprintf("'nonce-%v'", nonce)))
+	}
+
+	ds = append(ds, fmt.Sprintf("report-uri %v", ReportPath))
+	ds = append(ds, fmt.Sprintf("report-to %v" , reportGroup))
+
+	return strings.Join(ds, "; ")
+}
+
+// Handler sets the policy on every response and makes the request's nonce
+// available through Nonce.
+func Handler(p *Policy) func(http.Handler) http.Handler {
+	header := "Content-Security-Policy"
+
+	if p.ReportOnly {
+		header = "Content-Security-Policy-Report-Only"
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request){
+			b := make([]byte, 16)
+			rand.Read(b)
+
+			nonce := base64.StdEncoding.EncodeToString(b)
+
+			rw.Header().Set(header                , p.String(nonce))
+			rw.Header().Set("Reporting-Endpoints" , fmt.Sprintf(`%v="%v"`, reportGroup, ReportPath))
+
+			next.ServeHTTP(rw, r.WithContext(context.WithValue(r.Context(), nonceKey, nonce)))
+		})
+	}
+}
+
+// Nonce returns the nonce of the request, or an empty string if no policy is
+// in effect.
+func Nonce(ctx context.Context) string {
+	n, _ := ctx.Value(nonceKey).(string)
+
+	return n
+}
+
+// Report logs the violation reports that browsers send to ReportPath.
+func Report(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	body, bodyErr := io.ReadAll(io.LimitReader(r.Body, reportMax))
+	if bodyErr != nil {
+		slog.LogAttrs(ctx, slog.LevelError, "read csp report",
+			slog.String("error", bodyErr.Error()),
+		)
+
+		rw.WriteHeader(http.StatusBadRequest)
+
+		return
+	}
+
+	slog.LogAttrs(ctx, slog.LevelWarn, "content security policy violation",
+		slog.String("contentType" , r.Header.Get("Content-Type")),
+		slog.String("userAgent"   , r.UserAgent()),
+		slog.String("report"      , string(body)),
+	)
+
+	rw.WriteHeader(http.StatusNoContent)
+}
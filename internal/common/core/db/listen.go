@@ -0,0 +1,90 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+import (
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Listen takes a connection out of the pool and waits for notifications on
+// channel until ctx is cancelled. onListen is called each time the channel is
+// (re)listened to, because notifications sent while nobody was listening are
+// lost. onNotify is called with the payload of every notification.
+func Listen(ctx context.Context, pool *pgxpool.Pool, channel string, retry time.Duration, onListen func(), onNotify func(payload string)) {
+	for {
+		err := wait(ctx, pool, channel, onListen, onNotify)
+
+		if ctx.Err() != nil {
+			return
+		}
+
+		slog.LogAttrs(ctx, slog.LevelError, "listen for notifications",
+			slog.String("error"   , err.Error()),
+			slog.String("channel" , channel),
+		)
+
+		time.Sleep(retry)
+	}
+}
+
+func Notify(ctx *context.Context, logger *slog.Logger, conn *pgxpool.Conn, channel string, payload string) error {
+	_, err := conn.Exec(*ctx, "select pg_notify($1, $2)", channel, payload)
+	if err != nil {
+		logger.LogAttrs(*ctx, slog.LevelError, "send notification",
+			slog.String("error"   , err.Error()),
+			slog.String("channel" , channel),
+			slog.String("payload" , payload),
+		)
+
+		return fmt.Errorf("send notification: %w", err)
+	}
+
+	logger.LogAttrs(*ctx, slog.LevelDebug, "send notification",
+		slog.String("channel" , channel),
+		slog.String("payload" , payload),
+	)
+
+	return nil
+}
+
+func wait(ctx context.Context, pool *pgxpool.Pool, channel string, onListen func(), onNotify func(payload string)) error {
+	pc, pcErr := pool.Acquire(ctx)
+	if pcErr != nil {
+		return fmt.Errorf("acquire listener connection: %w", pcErr)
+	}
+
+	conn := pc.Hijack()
+
+	defer conn.Close(context.Background())
+
+	_, lErr := conn.Exec(ctx, fmt.Sprintf("listen %v", pgx.Identifier{channel}.Sanitize()))
+	if lErr != nil {
+		return fmt.Errorf("listen: %w", lErr)
+	}
+
+	onListen()
+
+	slog.LogAttrs(ctx, slog.LevelInfo, "listen for notifications",
+		slog.String("channel", channel),
+	)
+
+	for {
+		n, nErr := conn.WaitForNotification(ctx)
+		if nErr != nil {
+			return fmt.Errorf("wait for notification: %w", nErr)
+		}
+
+		slog.LogAttrs(ctx, slog.LevelDebug, "receive notification",
+			slog.String("channel" , n.Channel),
+			slog.String("payload" , n.Payload),
+		)
+
+		onNotify(n.Payload)
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"io/fs"
 	"log/slog"
 	"path/filepath"
+	"sync"
+	"time"
 )
 
 import (
@@ -21,30 +23,107 @@ import (
 )
 
 var (
-	cache *i18n.Bundle
+	cache    *i18n.Bundle
+	cacheMu  sync.RWMutex
+	lang     language.Tag
+	dir      string
+	loaded   time.Time
+	loadErr  error
 )
 
 func Localiser(ctx context.Context, logger *slog.Logger, l string) (*i18n.Localizer) {
 	logger.LogAttrs(ctx, slog.LevelDebug, "get localiser",
 		slog.String("language", l),
 	)
-	
+
+	if ui.Dev {
+		reload(ctx)
+	}
+
+	cacheMu.RLock()
+	defer cacheMu.RUnlock()
+
 	return i18n.NewLocalizer(cache, l)
 }
 
+// Err returns the error from the last reload of the translations in dev mode.
+// The translations that were loaded before it stay in use.
+func Err() error {
+	cacheMu.RLock()
+	defer cacheMu.RUnlock()
+
+	return loadErr
+}
+
 func InitCache(ctx context.Context, dl language.Tag, root string) (error) {
 	slog.LogAttrs(ctx, slog.LevelInfo, "start")
 
-	translations := i18n.NewBundle(dl)
+	lang = dl
+	dir  = root
+
+	translations, err := load(ctx)
+	if err != nil {
+		if ui.Dev {
+			cacheMu.Lock()
+			cache, loadErr = i18n.NewBundle(dl), err
+			cacheMu.Unlock()
+
+			return nil
+		}
+
+		return err
+	}
+
+	cacheMu.Lock()
+	cache, loaded = translations, time.Now()
+	cacheMu.Unlock()
+
+	return nil
+}
+
+// reload loads the translations again if any of them have changed since they
+// were last loaded.
+func reload(ctx context.Context) {
+	cacheMu.RLock()
+	stale := ui.ModTime(dir).After(loaded)
+	cacheMu.RUnlock()
+
+	if ! stale {
+		return
+	}
+
+	slog.LogAttrs(ctx, slog.LevelInfo, "reload translations",
+		slog.String("root", dir),
+	)
+
+	translations, err := load(ctx)
+
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
+
+	loaded  = time.Now()
+	loadErr = err
+
+	if err == nil {
+		cache = translations
+	}
+}
+
+func load(ctx context.Context) (*i18n.Bundle, error) {
+	translations := i18n.NewBundle(lang)
 	translations.RegisterUnmarshalFunc("toml", toml.Unmarshal)
 
-	wdErr := fs.WalkDir(ui.Files, root, func(path string, d fs.DirEntry, err error) error{
+	wdErr := fs.WalkDir(ui.FS, dir, func(path string, d fs.DirEntry, err error) error{
+		if err != nil {
+			return err
+		}
+
 		if ! d.IsDir() && filepath.Ext(path) == ".toml" {
 			slog.LogAttrs(ctx, slog.LevelInfo, "load i18nFile",
 				slog.String("i18nFile", path),
 			)
 
-			if _, i18nFileErr := translations.LoadMessageFileFS(ui.Files, path); i18nFileErr != nil {
+			if _, i18nFileErr := translations.LoadMessageFileFS(ui.FS, path); i18nFileErr != nil {
 				slog.LogAttrs(ctx, slog.LevelError, "load i18nFile",
 					slog.String("i18nFile", path),
 					slog.String("error"   , i18nFileErr.Error()),
@@ -61,10 +140,8 @@ func InitCache(ctx context.Context, dl language.Tag, root string) (error) {
 			slog.String("error" , wdErr.Error()),
 		)
 
-		return wdErr
+		return nil, wdErr
 	}
 
-	cache = translations
-
-	return nil
+	return translations, nil
 }
@@ -0,0 +1,43 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// File writes every message to a .eml file in a directory, where it can be
+// opened with a mail client, instead of sending it. It is meant for
+// development.
+type File struct {
+	dir  string
+	from string
+}
+
+func NewFile(dir string, from string) *File {
+	return &File{
+		dir  : dir,
+		from : from,
+	}
+}
+
+func (f *File) Send(ctx context.Context, msg Message) error {
+	b, bErr := render(f.from, msg)
+	if bErr != nil {
+		return fmt.Errorf("render message: %w", bErr)
+	}
+
+	if err := os.MkdirAll(f.dir, 0o750); err != nil {
+		return fmt.Errorf("create mail directory: %w", err)
+	}
+
+	nm := filepath.Join(f.dir, fmt.Sprintf("%v.eml", time.Now().Format("20060102-150405.000000000")))
+
+	if err := os.WriteFile(nm, b, 0o640); err != nil {
+		return fmt.Errorf("write message: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,24 @@
+package mail
+
+import (
+	"context"
+	"log/slog"
+)
+
+// Log writes every message to the default logger instead of sending it. It is
+// meant for development, as links such as password resets end up in the log.
+type Log struct{}
+
+func NewLog() *Log {
+	return &Log{}
+}
+
+func (l *Log) Send(ctx context.Context, msg Message) error {
+	slog.LogAttrs(ctx, slog.LevelInfo, "send message",
+		slog.String("to"      , msg.To),
+		slog.String("subject" , msg.Subject),
+		slog.String("text"    , msg.Text),
+	)
+
+	return nil
+}
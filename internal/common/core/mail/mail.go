@@ -0,0 +1,96 @@
+package mail
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/textproto"
+	"strings"
+	"time"
+)
+
+// Message is an email with a plain text body and, optionally, an HTML one.
+type Message struct {
+	To      string
+	Subject string
+	Text    string
+	Html    string
+}
+
+// Transport delivers a message. Messages are not sent with a transport
+// directly, they are queued in the outbox and handed to it by Relay.
+type Transport interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// render renders msg as a multipart/alternative message from the address from.
+func render(from string, msg Message) ([]byte, error) {
+	var (
+		buf bytes.Buffer
+		mp  = multipart.NewWriter(&buf)
+	)
+
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return nil, err
+	}
+
+	fmt.Fprintf(&buf, "From: %v\r\n"             , from)
+	fmt.Fprintf(&buf, "To: %v\r\n"               , msg.To)
+	fmt.Fprintf(&buf, "Subject: %v\r\n"          , mime.QEncoding.Encode("utf-8", msg.Subject))
+	fmt.Fprintf(&buf, "Date: %v\r\n"             , time.Now().Format(time.RFC1123Z))
+	fmt.Fprintf(&buf, "Message-ID: <%v@%v>\r\n"  , hex.EncodeToString(id), domain(from))
+	fmt.Fprintf(&buf, "MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&buf, "Content-Type: multipart/alternative; boundary=%v\r\n\r\n", mp.Boundary())
+
+	parts := []struct {
+		contentType string
+		body        string
+	}{
+		{"text/plain; charset=utf-8" , msg.Text},
+		{"text/html; charset=utf-8"  , msg.Html},
+	}
+
+	for _, v := range parts {
+		if v.body == "" {
+			continue
+		}
+
+		pw, pwErr := mp.CreatePart(textproto.MIMEHeader{
+			"Content-Type"              : {v.contentType},
+			"Content-Transfer-Encoding" : {"quoted-printable"},
+		})
+		if pwErr != nil {
+			return nil, pwErr
+		}
+
+		qw := quotedprintable.NewWriter(pw)
+
+		if _, err := qw.Write([]byte(v.body)); err != nil {
+			return nil, err
+		}
+
+		if err := qw.Close(); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := mp.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func domain(addr string) string {
+	if i := strings.LastIndex(strings.TrimSuffix(addr, ">"), "@"); i != -1 {
+		return strings.TrimSuffix(addr, ">")[i+1:]
+	}
+
+	return "localhost"
+}
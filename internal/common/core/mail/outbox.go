@@ -0,0 +1,203 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+import (
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+import (
+	"github.com/andrewah64/base-app-client/internal/common/core/db"
+	"github.com/andrewah64/base-app-client/internal/common/core/session"
+)
+
+// Messages are written to the outbox in the same transaction as the change
+// they are about, e.g. a password reset token, so a message is never lost, or
+// sent for a change that was rolled back, when the mail server is down. The
+// procedures that queue a message notify mobChannel once they commit.
+const (
+	mobChannel = "all_core_mob_snd"
+	mobRetry   = 5 * time.Second
+	mobPoll    = time.Minute
+	mobBatch   = 20
+
+	dbRole     = "role_all_core_unauth_mob_all_mod"
+	dbSchema   = "all_core_unauth_mob_all_mod"
+)
+
+type MobInf struct {
+	MobId  int
+	MobTo  string
+	MobSbj string
+	MobTxt string
+	MobHtm string
+}
+
+// Relay sends the messages in the outbox with t, as soon as they are queued
+// and, for messages that could not be sent, every mobPoll. The database claims
+// the messages it hands out, so several instances can relay at once, and
+// decides when a failed message is tried again.
+func Relay(ctx *context.Context, pool *pgxpool.Pool, t Transport) {
+	wake := make(chan struct{}, 1)
+
+	poke := func(){
+		select {
+			case wake <- struct{}{}:
+			default:
+		}
+	}
+
+	go db.Listen(*ctx, pool, mobChannel, mobRetry, poke, func(payload string){ poke() })
+
+	go func(){
+		ticker := time.NewTicker(mobPoll)
+		defer ticker.Stop()
+
+		for {
+			select {
+				case <-(*ctx).Done():
+					return
+				case <-ticker.C:
+				case <-wake:
+			}
+
+			if err := Send(ctx, pool, t); err != nil {
+				slog.LogAttrs(*ctx, slog.LevelError, "relay outbox",
+					slog.String("error", err.Error()),
+				)
+			}
+		}
+	}()
+}
+
+// Send sends the messages that are due, a batch at a time, until there are
+// none left.
+func Send(ctx *context.Context, pool *pgxpool.Pool, t Transport) error {
+	conn, connErr := db.Conn(ctx, slog.Default(), pool)
+	if connErr != nil {
+		return connErr
+	}
+
+	defer conn.Release()
+
+	idErr := session.Identity(ctx, slog.Default(), conn, dbRole)
+	if idErr != nil {
+		return idErr
+	}
+
+	for {
+		rs, rsErr := GetMobInf(ctx, slog.Default(), conn, mobBatch)
+		if rsErr != nil {
+			return rsErr
+		}
+
+		for _, v := range rs {
+			sndErr := t.Send(*ctx, Message{
+				To      : v.MobTo,
+				Subject : v.MobSbj,
+				Text    : v.MobTxt,
+				Html    : v.MobHtm,
+			})
+
+			if sndErr != nil {
+				slog.LogAttrs(*ctx, slog.LevelError, "send message",
+					slog.Int   ("mobId" , v.MobId),
+					slog.String("error" , sndErr.Error()),
+				)
+
+				if err := PatchMobErr(ctx, slog.Default(), conn, v.MobId, sndErr.Error(), nil); err != nil {
+					return err
+				}
+
+				continue
+			}
+
+			if err := PatchMobSnd(ctx, slog.Default(), conn, v.MobId, nil); err != nil {
+				return err
+			}
+		}
+
+		if len(rs) < mobBatch {
+			return nil
+		}
+	}
+}
+
+func GetMobInf(ctx *context.Context, logger *slog.Logger, conn *pgxpool.Conn, limit int) ([]MobInf, error) {
+	const (
+		dbFunc = "mob_inf"
+	)
+
+	rs, rsErr := db.DataSet[MobInf](ctx, logger, conn, func(ctx *context.Context, tx *pgx.Tx)(string, string, *pgx.Rows, error){
+		qry := fmt.Sprintf("select %v.%v($1, $2)", dbSchema, dbFunc)
+
+		call, err := (*tx).Query(*ctx, qry, dbFunc, limit)
+		if err != nil {
+			slog.LogAttrs(*ctx, slog.LevelError, "get dataset",
+				slog.String("error" , err.Error()),
+				slog.String("qry"   , qry),
+				slog.Int   ("limit" , limit),
+			)
+
+			return qry, dbFunc, nil, fmt.Errorf("call database function: %w", err)
+		}
+
+		return qry, dbFunc, &call, nil
+	})
+
+	return rs, rsErr
+}
+
+func PatchMobSnd(ctx *context.Context, logger *slog.Logger, conn *pgxpool.Conn, mobId int, exptErrs []string) error {
+	var (
+		sprocCall   = fmt.Sprintf("call %v.snd_mob(@p_mob_id)", dbSchema)
+		sprocParams = pgx.NamedArgs{
+			"p_mob_id" : mobId,
+		}
+	)
+
+	sprocErr := db.Sproc(ctx, logger, conn, sprocCall, sprocParams, exptErrs)
+	if sprocErr != nil {
+		logger.LogAttrs(*ctx, slog.LevelDebug, "call sproc",
+			slog.String("sprocCall" , sprocCall),
+			slog.String("error"     , sprocErr.Error()),
+			slog.Int   ("mobId"     , mobId),
+			slog.Any   ("exptErrs"  , exptErrs),
+		)
+
+		return sprocErr
+	}
+
+	return nil
+}
+
+func PatchMobErr(ctx *context.Context, logger *slog.Logger, conn *pgxpool.Conn, mobId int, mobErr string, exptErrs []string) error {
+	var (
+		sprocCall   = fmt.Sprintf("call %v.err_mob(@p_mob_id, @p_mob_err)", dbSchema)
+		sprocParams = pgx.NamedArgs{
+			"p_mob_id"  : mobId,
+			"p_mob_err" : mobErr,
+		}
+	)
+
+	sprocErr := db.Sproc(ctx, logger, conn, sprocCall, sprocParams, exptErrs)
+	if sprocErr != nil {
+		logger.LogAttrs(*ctx, slog.LevelDebug, "call sproc",
+			slog.String("sprocCall" , sprocCall),
+			slog.String("error"     , sprocErr.Error()),
+			slog.Int   ("mobId"     , mobId),
+			slog.String("mobErr"    , mobErr),
+			slog.Any   ("exptErrs"  , exptErrs),
+		)
+
+		return sprocErr
+	}
+
+	return nil
+}
@@ -0,0 +1,132 @@
+package mail
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strconv"
+	"time"
+)
+
+const (
+	// The longest a message can take to send when ctx has no deadline, so
+	// that a stalled server can't hold up the messages behind it.
+	timeout = 30 * time.Second
+)
+
+// SMTP sends messages through a mail server. The connection is upgraded with
+// STARTTLS when the server offers it, and the credentials are only sent over
+// TLS or to localhost.
+type SMTP struct {
+	host string
+	addr string
+	auth smtp.Auth
+	from string
+}
+
+func NewSMTP(host string, port int, user string, pw string, from string) *SMTP {
+	s := &SMTP{
+		host : host,
+		addr : net.JoinHostPort(host, strconv.Itoa(port)),
+		from : from,
+	}
+
+	if user != "" {
+		s.auth = smtp.PlainAuth("", user, pw, host)
+	}
+
+	return s
+}
+
+func (s *SMTP) Send(ctx context.Context, msg Message) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	b, bErr := render(s.from, msg)
+	if bErr != nil {
+		return fmt.Errorf("render message: %w", bErr)
+	}
+
+	var d net.Dialer
+
+	conn, connErr := d.DialContext(ctx, "tcp", s.addr)
+	if connErr != nil {
+		return fmt.Errorf("dial %v: %w", s.addr, connErr)
+	}
+
+	deadline, ok := ctx.Deadline()
+	if ! ok {
+		deadline = time.Now().Add(timeout)
+	}
+
+	if err := conn.SetDeadline(deadline); err != nil {
+		conn.Close()
+		return fmt.Errorf("set deadline on connection to %v: %w", s.addr, err)
+	}
+
+	// closing the connection ends whatever exchange is waiting on it
+	stop := context.AfterFunc(ctx, func() {
+		conn.Close()
+	})
+	defer stop()
+
+	c, cErr := smtp.NewClient(conn, s.host)
+	if cErr != nil {
+		conn.Close()
+		return fmt.Errorf("greet %v: %w", s.addr, cErr)
+	}
+	defer c.Close()
+
+	if err := s.send(c, msg.To, b); err != nil {
+		return fmt.Errorf("send message to %v: %w", s.addr, err)
+	}
+
+	return nil
+}
+
+// send is the exchange that smtp.SendMail has with the server, on a client
+// whose connection has a deadline.
+func (s *SMTP) send(c *smtp.Client, to string, b []byte) error {
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err := c.StartTLS(&tls.Config{ServerName: s.host}); err != nil {
+			return err
+		}
+	}
+
+	if s.auth != nil {
+		if ok, _ := c.Extension("AUTH"); ! ok {
+			return errors.New("server doesn't support AUTH")
+		}
+
+		if err := c.Auth(s.auth); err != nil {
+			return err
+		}
+	}
+
+	if err := c.Mail(s.from); err != nil {
+		return err
+	}
+
+	if err := c.Rcpt(to); err != nil {
+		return err
+	}
+
+	w, wErr := c.Data()
+	if wErr != nil {
+		return wErr
+	}
+
+	if _, err := w.Write(b); err != nil {
+		return err
+	}
+
+	if err := w.Close(); err != nil {
+		return err
+	}
+
+	return c.Quit()
+}
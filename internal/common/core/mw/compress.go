@@ -0,0 +1,310 @@
+package mware
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	// Responses smaller than this are not worth compressing.
+	compressMin = 1024
+)
+
+// Content types that are already compressed, or are streamed and must reach
+// the client as they are written.
+var uncompressible = []string{
+	"image/",
+	"video/",
+	"audio/",
+	"font/woff",
+	"application/gzip",
+	"application/zip",
+	"application/zstd",
+	"application/octet-stream",
+	"application/pdf",
+	"text/event-stream",
+}
+
+var gzipPool = sync.Pool{
+	New: func() any {
+		zw, _ := gzip.NewWriterLevel(io.Discard, gzip.DefaultCompression)
+		return zw
+	},
+}
+
+type pooledGzip struct {
+	*gzip.Writer
+}
+
+func (z pooledGzip) Close() error {
+	err := z.Writer.Close()
+
+	gzipPool.Put(z.Writer)
+
+	return err
+}
+
+func newGzip(w io.Writer) io.WriteCloser {
+	zw := gzipPool.Get().(*gzip.Writer)
+	zw.Reset(w)
+
+	return pooledGzip{zw}
+}
+
+// Compress gzips responses of at least compressMin bytes for clients that
+// accept it. Responses that already
+// have a Content-Encoding, are partial, have no body, or have a content type
+// in uncompressible are passed through unchanged. It goes before Recover in a
+// chain so that error pages are compressed too.
+func Compress(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request){
+		if ! Accepts(r.Header.Get("Accept-Encoding"), "gzip") || r.Method == http.MethodHead || r.Header.Get("Range") != "" {
+			next.ServeHTTP(rw, r)
+			return
+		}
+
+		cw := &compressWriter{
+			ResponseWriter : rw,
+		}
+
+		next.ServeHTTP(cw, r)
+
+		cw.Close()
+	})
+}
+
+// Accepts reports whether the Accept-Encoding header allows the encoding,
+// either by name or through "*", with a q-value above zero. A name takes
+// precedence over "*" whatever the order they are listed in.
+func Accepts(header string, encoding string) bool {
+	named, star := -1.0, -1.0
+
+	for _, v := range strings.Split(header, ",") {
+		name, params, _ := strings.Cut(v, ";")
+
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name != encoding && name != "*" {
+			continue
+		}
+
+		q := 1.0
+
+		for _, p := range strings.Split(params, ";") {
+			k, v, _ := strings.Cut(p, "=")
+
+			if strings.ToLower(strings.TrimSpace(k)) != "q" {
+				continue
+			}
+
+			f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+			if err != nil || f < 0 || f > 1 {
+				f = 0
+			}
+
+			q = f
+		}
+
+		if name == encoding {
+			named = q
+		} else {
+			star = q
+		}
+	}
+
+	if named >= 0 {
+		return named > 0
+	}
+
+	return star > 0
+}
+
+// compressWriter holds the start of the body back until it knows whether the
+// response is worth compressing. The headers are captured when the status is
+// written, as they would be without it, so headers set afterwards, such as an
+// HX-Trigger after a fragment has been written, are ignored in the same way.
+type compressWriter struct {
+	http.ResponseWriter
+	status      int
+	header      http.Header
+	buf         bytes.Buffer
+	decided     bool
+	compressing bool
+	zw          io.WriteCloser
+}
+
+func (cw *compressWriter) WriteHeader(status int) {
+	if cw.status != 0 {
+		return
+	}
+
+	if status < http.StatusOK {
+		cw.ResponseWriter.WriteHeader(status)
+		return
+	}
+
+	cw.status = status
+	cw.header = cw.ResponseWriter.Header().Clone()
+
+	if status == http.StatusNoContent || status == http.StatusNotModified || status == http.StatusPartialContent {
+		cw.commit(false)
+	}
+}
+
+func (cw *compressWriter) Write(b []byte) (int, error) {
+	if cw.status == 0 {
+		cw.WriteHeader(http.StatusOK)
+	}
+
+	if cw.decided {
+		if cw.compressing {
+			return cw.zw.Write(b)
+		}
+
+		return cw.ResponseWriter.Write(b)
+	}
+
+	cw.buf.Write(b)
+
+	if cw.buf.Len() >= compressMin {
+		if err := cw.commit(cw.eligible()); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(b), nil
+}
+
+// Flush sends what has been written so far, deciding on compression with the
+// body written up to now.
+func (cw *compressWriter) Flush() {
+	if ! cw.decided {
+		if cw.status == 0 {
+			cw.WriteHeader(http.StatusOK)
+		}
+
+		cw.commit(cw.buf.Len() >= compressMin && cw.eligible())
+	}
+
+	if f, ok := cw.zw.(interface{ Flush() error }); ok && cw.compressing {
+		f.Flush()
+	}
+
+	http.NewResponseController(cw.ResponseWriter).Flush()
+}
+
+func (cw *compressWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	if cw.status != 0 {
+		return nil, nil, fmt.Errorf("hijack after the response has started")
+	}
+
+	return http.NewResponseController(cw.ResponseWriter).Hijack()
+}
+
+func (cw *compressWriter) Unwrap() http.ResponseWriter {
+	return cw.ResponseWriter
+}
+
+// Close writes out a body too small to have been committed and finishes the
+// compressed stream.
+func (cw *compressWriter) Close() error {
+	if cw.status == 0 {
+		return nil
+	}
+
+	if ! cw.decided {
+		if err := cw.commit(false); err != nil {
+			return err
+		}
+	}
+
+	if cw.compressing {
+		return cw.zw.Close()
+	}
+
+	return nil
+}
+
+func (cw *compressWriter) eligible() bool {
+	if cw.header.Get("Content-Encoding") != "" || cw.header.Get("Content-Range") != "" {
+		return false
+	}
+
+	ct := strings.ToLower(cw.header.Get("Content-Type"))
+	if ct == "" {
+		ct = http.DetectContentType(cw.buf.Bytes())
+	}
+
+	for _, v := range uncompressible {
+		if strings.HasPrefix(ct, v) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// commit writes the captured headers and status, then the buffered body.
+func (cw *compressWriter) commit(compress bool) error {
+	cw.decided     = true
+	cw.compressing = compress
+
+	h := cw.ResponseWriter.Header()
+
+	for k := range h {
+		if _, ok := cw.header[k]; ! ok {
+			delete(h, k)
+		}
+	}
+
+	for k, v := range cw.header {
+		h[k] = v
+	}
+
+	if h.Get("Content-Type") == "" && cw.buf.Len() > 0 {
+		h.Set("Content-Type", http.DetectContentType(cw.buf.Bytes()))
+	}
+
+	if compress {
+		h.Del("Content-Length")
+		h.Set("Content-Encoding", "gzip")
+		h.Add("Vary"            , "Accept-Encoding")
+
+		if etag := h.Get("ETag"); etag != "" && ! strings.HasPrefix(etag, "W/") {
+			h.Set("ETag", "W/" + etag)
+		}
+	}
+
+	cw.ResponseWriter.WriteHeader(cw.status)
+
+	if cw.buf.Len() == 0 {
+		if compress {
+			cw.zw = newGzip(cw.ResponseWriter)
+		}
+
+		return nil
+	}
+
+	if compress {
+		cw.zw = newGzip(cw.ResponseWriter)
+
+		_, err := cw.zw.Write(cw.buf.Bytes())
+
+		cw.buf.Reset()
+
+		return err
+	}
+
+	_, err := cw.ResponseWriter.Write(cw.buf.Bytes())
+
+	cw.buf.Reset()
+
+	return err
+}
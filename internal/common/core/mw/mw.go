@@ -8,13 +8,19 @@ import (
 	"github.com/justinas/nosurf"
 )
 
-func CSRFHandler(next http.Handler) http.Handler {
+// CSRFHandler rejects unsafe requests that do not carry the token issued to
+// the client, either in the X-CSRF-Token header or the csrf_token form field.
+// Requests for which exempt returns true are not checked.
+func CSRFHandler(next http.Handler, exempt func(*http.Request) bool, failure http.Handler) http.Handler {
 	csrfHandler := nosurf.New(next)
 	csrfHandler.SetBaseCookie(http.Cookie{
 		HttpOnly: true,
 		Path    : "/",
 		Secure  : true,
+		SameSite: http.SameSiteLaxMode,
 	})
+	csrfHandler.ExemptFunc(exempt)
+	csrfHandler.SetFailureHandler(failure)
 
 	return csrfHandler
 }
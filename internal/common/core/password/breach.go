@@ -0,0 +1,61 @@
+package password
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+var (
+	brcDir string
+)
+
+// UseBreachList sets the directory of the breached password list. It holds
+// the SHA-1 hashes of breached passwords, split by their first 5 hex digits
+// into files named after them, such as 5BAA6.txt, with one SUFFIX:COUNT line
+// per hash, as the Pwned Passwords range API and downloader provide them. An
+// empty dir turns the check off.
+func UseBreachList(dir string) {
+	brcDir = dir
+}
+
+// Breached reports whether password is in the breached password list. Only
+// the file for the hash's prefix is read.
+func Breached(ctx context.Context, password string) bool {
+	if brcDir == "" {
+		return false
+	}
+
+	h      := sha1.Sum([]byte(password))
+	hx     := strings.ToUpper(hex.EncodeToString(h[:]))
+	prefix := hx[:5]
+	suffix := hx[5:]
+
+	f, err := os.Open(filepath.Join(brcDir, prefix + ".txt"))
+	if err != nil {
+		if ! os.IsNotExist(err) {
+			slog.LogAttrs(ctx, slog.LevelError, "open breached password list",
+				slog.String("error" , err.Error()),
+			)
+		}
+
+		return false
+	}
+
+	defer f.Close()
+
+	s := bufio.NewScanner(f)
+
+	for s.Scan() {
+		if sfx, _, _ := strings.Cut(s.Text(), ":"); strings.EqualFold(strings.TrimSpace(sfx), suffix) {
+			return true
+		}
+	}
+
+	return false
+}
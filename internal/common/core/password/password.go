@@ -1,40 +1,168 @@
 package password
 
 import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
 	"strings"
-	"unicode/utf8"
+	"sync"
 )
 
 import (
+	"golang.org/x/crypto/argon2"
 	"golang.org/x/crypto/bcrypt"
 )
 
+const (
+	Argon2id = "argon2id"
+	Bcrypt   = "bcrypt"
+)
+
+// Params are the algorithm, and its parameters, that new hashes are made
+// with. Memory is in KiB.
+type Params struct {
+	Alg     string
+	Time    uint32
+	Memory  uint32
+	Threads uint8
+	Cost    int
+}
+
+const (
+	saltLen = 16
+	keyLen  = 32
+)
+
+var (
+	current = Params{
+		Alg     : Argon2id,
+		Time    : 3,
+		Memory  : 64 * 1024,
+		Threads : 2,
+		Cost    : 12,
+	}
+
+	dummyOnce sync.Once
+	dummy     string
+)
+
+// Configure sets the parameters of new hashes. It is called once at startup,
+// before any password is hashed.
+func Configure(p Params) {
+	current = p
+}
+
+// CheckHash reports whether password matches hash, which may be an argon2id
+// hash in the PHC string format or a bcrypt hash.
 func CheckHash(password, hash string) bool {
-	err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
-	return err == nil
+	if ! strings.HasPrefix(hash, "$" + Argon2id + "$") {
+		err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
+		return err == nil
+	}
+
+	p, salt, key, err := decode(hash)
+	if err != nil {
+		return false
+	}
+
+	k := argon2.IDKey([]byte(password), salt, p.Time, p.Memory, p.Threads, uint32(len(key)))
+
+	return subtle.ConstantTimeCompare(k, key) == 1
+}
+
+// Dummy returns a hash, made with the current parameters, of a password that
+// no one has. A sign in with an unknown username is checked against it so
+// that it takes as long as one with a wrong password.
+func Dummy() string {
+	dummyOnce.Do(func() {
+		dummy, _ = Hash(rand.Text())
+	})
+
+	return dummy
 }
 
 func Hash(password string) (string, error) {
-	bytes, err := bcrypt.GenerateFromPassword([]byte(password), 10)
-	return string(bytes), err
+	if current.Alg == Bcrypt {
+		bytes, err := bcrypt.GenerateFromPassword([]byte(password), current.Cost)
+		return string(bytes), err
+	}
+
+	salt := make([]byte, saltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+
+	key := argon2.IDKey([]byte(password), salt, current.Time, current.Memory, current.Threads, keyLen)
+
+	return encode(current, salt, key), nil
+}
+
+// NeedsRehash reports whether hash was made with an algorithm or parameters
+// other than the current ones, so that it is replaced the next time the user
+// signs in.
+func NeedsRehash(hash string) bool {
+	if ! strings.HasPrefix(hash, "$" + Argon2id + "$") {
+		cost, err := bcrypt.Cost([]byte(hash))
+		return err != nil || current.Alg != Bcrypt || cost != current.Cost
+	}
+
+	p, _, key, err := decode(hash)
+	if err != nil {
+		return true
+	}
+
+	return current.Alg != Argon2id || p.Time != current.Time || p.Memory != current.Memory || p.Threads != current.Threads || len(key) != keyLen
+}
+
+func encode(p Params, salt []byte, key []byte) string {
+	return fmt.Sprintf("$%v$v=%v$m=%v,t=%v,p=%v$%v$%v", Argon2id, argon2.Version, p.Memory, p.Time, p.Threads, base64.RawStdEncoding.EncodeToString(salt), base64.RawStdEncoding.EncodeToString(key))
 }
 
-func Validate(password string, minLen int, maxLen int, incSym bool, incNum bool) (bool, bool, bool) {
-	lenPass := false
-	symPass := false
-	numPass := false
+func decode(hash string) (Params, []byte, []byte, error) {
+	var (
+		p Params
+		v int
+	)
 
-	if utf8.RuneCountInString(password) >= minLen && utf8.RuneCountInString(password) <= maxLen {
-		lenPass = true
+	parts := strings.Split(hash, "$")
+	if len(parts) != 6 {
+		return p, nil, nil, fmt.Errorf("argon2id hash has %v parts", len(parts))
 	}
 
-	if strings.ContainsAny(password, "!*-,.$\"&()+=") {
-		symPass = true
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &v); err != nil {
+		return p, nil, nil, err
 	}
 
-	if strings.ContainsAny(password, "0123456789") {
-		numPass = true
+	if v != argon2.Version {
+		return p, nil, nil, fmt.Errorf("argon2 version %v is not supported", v)
 	}
 
-	return lenPass, symPass, numPass
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &p.Memory, &p.Time, &p.Threads); err != nil {
+		return p, nil, nil, err
+	}
+
+	// argon2 panics on no passes or threads, and quietly raises memory below
+	// 8 KiB a thread, so a hash with them is refused before it gets there
+	if p.Time < 1 || p.Threads < 1 || p.Memory < 8 * uint32(p.Threads) {
+		return p, nil, nil, fmt.Errorf("argon2id parameters m=%v,t=%v,p=%v are out of range", p.Memory, p.Time, p.Threads)
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return p, nil, nil, err
+	}
+
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil {
+		return p, nil, nil, err
+	}
+
+	if len(salt) == 0 || len(key) == 0 {
+		return p, nil, nil, fmt.Errorf("argon2id hash has a salt of %v bytes and a key of %v bytes", len(salt), len(key))
+	}
+
+	p.Alg = Argon2id
+
+	return p, salt, key, nil
 }
@@ -0,0 +1,169 @@
+package password
+
+import (
+	"encoding/base64"
+	"fmt"
+	"testing"
+)
+
+import (
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// cheap parameters keep the tests fast, the hashes are never stored
+var (
+	testArgon2id = Params{
+		Alg     : Argon2id,
+		Time    : 1,
+		Memory  : 64,
+		Threads : 1,
+		Cost    : bcrypt.MinCost,
+	}
+
+	testBcrypt = Params{
+		Alg     : Bcrypt,
+		Time    : 1,
+		Memory  : 64,
+		Threads : 1,
+		Cost    : bcrypt.MinCost,
+	}
+)
+
+func configure(t *testing.T, p Params) {
+	t.Helper()
+
+	previous := current
+
+	Configure(p)
+
+	t.Cleanup(func(){
+		Configure(previous)
+	})
+}
+
+func TestHashCheckHash(t *testing.T) {
+	for _, p := range []Params{testArgon2id, testBcrypt} {
+		t.Run(p.Alg, func(t *testing.T){
+			configure(t, p)
+
+			hash, err := Hash("correct horse battery staple")
+			if err != nil {
+				t.Fatalf("Hash: %v", err)
+			}
+
+			if ! CheckHash("correct horse battery staple", hash) {
+				t.Errorf("CheckHash rejected the password %v was made from", hash)
+			}
+
+			if CheckHash("correct horse battery stapler", hash) {
+				t.Errorf("CheckHash accepted a wrong password for %v", hash)
+			}
+
+			if NeedsRehash(hash) {
+				t.Errorf("NeedsRehash wants %v made with the current parameters replaced", hash)
+			}
+		})
+	}
+}
+
+func TestHashSalted(t *testing.T) {
+	configure(t, testArgon2id)
+
+	a, aErr := Hash("password")
+	b, bErr := Hash("password")
+	if aErr != nil || bErr != nil {
+		t.Fatalf("Hash: %v, %v", aErr, bErr)
+	}
+
+	if a == b {
+		t.Errorf("Hash made %v twice for the same password", a)
+	}
+}
+
+func TestNeedsRehash(t *testing.T) {
+	configure(t, testArgon2id)
+
+	argon2idHash, err := Hash("password")
+	if err != nil {
+		t.Fatalf("Hash: %v", err)
+	}
+
+	configure(t, testBcrypt)
+
+	bcryptHash, err := Hash("password")
+	if err != nil {
+		t.Fatalf("Hash: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		current Params
+		hash    string
+		want    bool
+	}{
+		{"argon2id unchanged"    , testArgon2id                                 , argon2idHash , false},
+		{"argon2id more passes"  , Params{Argon2id, 2, 64 , 1, bcrypt.MinCost}  , argon2idHash , true },
+		{"argon2id more memory"  , Params{Argon2id, 1, 128, 1, bcrypt.MinCost}  , argon2idHash , true },
+		{"argon2id more threads" , Params{Argon2id, 1, 64 , 2, bcrypt.MinCost}  , argon2idHash , true },
+		{"argon2id to bcrypt"    , testBcrypt                                   , argon2idHash , true },
+		{"bcrypt unchanged"      , testBcrypt                                   , bcryptHash   , false},
+		{"bcrypt higher cost"    , Params{Bcrypt, 1, 64, 1, bcrypt.MinCost + 1} , bcryptHash   , true },
+		{"bcrypt to argon2id"    , testArgon2id                                 , bcryptHash   , true },
+		{"not a hash"            , testArgon2id                                 , "password"   , true },
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T){
+			configure(t, tt.current)
+
+			if got := NeedsRehash(tt.hash); got != tt.want {
+				t.Errorf("NeedsRehash(%v) = %v, want %v", tt.hash, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeOutOfRange(t *testing.T) {
+	var (
+		salt = base64.RawStdEncoding.EncodeToString(make([]byte, saltLen))
+		key  = base64.RawStdEncoding.EncodeToString(make([]byte, keyLen))
+	)
+
+	hash := func(memory, passes, threads int, salt, key string) string {
+		return fmt.Sprintf("$%v$v=%v$m=%v,t=%v,p=%v$%v$%v", Argon2id, argon2.Version, memory, passes, threads, salt, key)
+	}
+
+	tests := []struct {
+		name string
+		hash string
+	}{
+		{"no passes"     , hash(64, 0, 1, salt, key)},
+		{"no threads"    , hash(64, 1, 0, salt, key)},
+		{"too little m"  , hash(15, 1, 2, salt, key)},
+		{"no salt"       , hash(64, 1, 1, "", key)  },
+		{"no key"        , hash(64, 1, 1, salt, "") },
+		{"other version" , fmt.Sprintf("$%v$v=%v$m=64,t=1,p=1$%v$%v", Argon2id, argon2.Version - 1, salt, key)},
+		{"too few parts" , fmt.Sprintf("$%v$v=%v$m=64,t=1,p=1$%v", Argon2id, argon2.Version, salt)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T){
+			if _, _, _, err := decode(tt.hash); err == nil {
+				t.Errorf("decode(%v) accepted it", tt.hash)
+			}
+
+			if CheckHash("password", tt.hash) {
+				t.Errorf("CheckHash(%v) accepted it", tt.hash)
+			}
+
+			if ! NeedsRehash(tt.hash) {
+				t.Errorf("NeedsRehash(%v) wants it kept", tt.hash)
+			}
+		})
+	}
+
+	if _, _, _, err := decode(hash(16, 1, 2, salt, key)); err != nil {
+		t.Errorf("decode rejected the least memory two threads can use: %v", err)
+	}
+}
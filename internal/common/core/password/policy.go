@@ -0,0 +1,81 @@
+package password
+
+import (
+	"context"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// The rules of a Policy. A violation is reported as one of these, which is
+// also the suffix of the translation key that describes it.
+const (
+	VrlLen = "len"
+	VrlUpr = "upr"
+	VrlLwr = "lwr"
+	VrlNum = "num"
+	VrlSym = "sym"
+	VrlBrc = "brc"
+	VrlHst = "hst"
+)
+
+// SymSet is the symbols a password can include when the policy names none.
+const (
+	SymSet = "!*-,.$\"&()+="
+)
+
+// Policy is a tenant's password policy, from its aupc settings.
+type Policy struct {
+	MinLen int
+	MaxLen int
+	IncUpr bool
+	IncLwr bool
+	IncNum bool
+	IncSym bool
+	SymSet string
+	Brc    bool
+}
+
+// Check returns the rules that password breaks. hsts are the hashes of the
+// passwords the user may not reuse, none for a new user.
+func (p Policy) Check(ctx context.Context, password string, hsts []string) []string {
+	var vrls []string
+
+	if n := utf8.RuneCountInString(password); n < p.MinLen || n > p.MaxLen {
+		vrls = append(vrls, VrlLen)
+	}
+
+	if p.IncUpr && ! strings.ContainsFunc(password, unicode.IsUpper) {
+		vrls = append(vrls, VrlUpr)
+	}
+
+	if p.IncLwr && ! strings.ContainsFunc(password, unicode.IsLower) {
+		vrls = append(vrls, VrlLwr)
+	}
+
+	if p.IncNum && ! strings.ContainsFunc(password, unicode.IsDigit) {
+		vrls = append(vrls, VrlNum)
+	}
+
+	symSet := p.SymSet
+	if symSet == "" {
+		symSet = SymSet
+	}
+
+	if p.IncSym && ! strings.ContainsAny(password, symSet) {
+		vrls = append(vrls, VrlSym)
+	}
+
+	if p.Brc && Breached(ctx, password) {
+		vrls = append(vrls, VrlBrc)
+	}
+
+	for _, hst := range hsts {
+		if CheckHash(password, hst) {
+			vrls = append(vrls, VrlHst)
+			break
+		}
+	}
+
+	return vrls
+}
@@ -0,0 +1,37 @@
+package password
+
+import (
+	"time"
+)
+
+import (
+	"golang.org/x/crypto/argon2"
+)
+
+// Tune returns the argon2id parameters for this hardware: the given memory
+// and threads, and the fewest passes that take at least target to hash a
+// password.
+func Tune(target time.Duration, memory uint32, threads uint8) Params {
+	var (
+		salt = make([]byte, saltLen)
+		p    = Params{
+			Alg     : Argon2id,
+			Time    : 1,
+			Memory  : memory,
+			Threads : threads,
+			Cost    : current.Cost,
+		}
+	)
+
+	for {
+		start := time.Now()
+
+		argon2.IDKey([]byte("password"), salt, p.Time, p.Memory, p.Threads, keyLen)
+
+		if time.Since(start) >= target || p.Time >= 100 {
+			return p
+		}
+
+		p.Time++
+	}
+}
@@ -0,0 +1,59 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const (
+	sweepEvery = time.Minute
+)
+
+type window struct {
+	count int
+	end   time.Time
+}
+
+// Memory keeps counts in this process, so each instance enforces its limits
+// on its own.
+type Memory struct {
+	mu      sync.Mutex
+	windows map[string]*window
+	swept   time.Time
+}
+
+func NewMemory() *Memory {
+	return &Memory{
+		windows : make(map[string]*window),
+		swept   : time.Now(),
+	}
+}
+
+func (m *Memory) Hit(ctx context.Context, key string, d time.Duration) (int, time.Duration, error) {
+	now := time.Now()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if now.Sub(m.swept) > sweepEvery {
+		for k, w := range m.windows {
+			if ! now.Before(w.end) {
+				delete(m.windows, k)
+			}
+		}
+
+		m.swept = now
+	}
+
+	w, ok := m.windows[key]
+	if ! ok || ! now.Before(w.end) {
+		w = &window{end: now.Add(d)}
+
+		m.windows[key] = w
+	}
+
+	w.count++
+
+	return w.count, w.end.Sub(now), nil
+}
@@ -0,0 +1,97 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+import (
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+import (
+	"github.com/andrewah64/base-app-client/internal/common/core/db"
+	"github.com/andrewah64/base-app-client/internal/common/core/session"
+)
+
+// Postgres keeps counts in the database so that limits hold across every
+// instance of the application. A request that already holds a connection,
+// from the middleware in front of the limit, is counted on it rather than
+// taking a second one from the pool.
+type Postgres struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgres(pool *pgxpool.Pool) *Postgres {
+	return &Postgres{
+		pool : pool,
+	}
+}
+
+func (p *Postgres) Hit(ctx context.Context, key string, d time.Duration) (int, time.Duration, error) {
+	const (
+		dbRole   = "role_all_core_unauth_rlm_all_mod"
+		dbSchema = "all_core_unauth_rlm_all_mod"
+		dbFunc   = "rlm_hit"
+	)
+
+	type hit struct {
+		RlmCnt  int
+		RlmLeft float64
+	}
+
+	var conn *pgxpool.Conn
+
+	if ssd, ok := session.FromContext(ctx); ok && ssd.Conn != nil {
+		conn = ssd.Conn
+	} else {
+		c, connErr := db.Conn(&ctx, slog.Default(), p.pool)
+		if connErr != nil {
+			return 0, 0, connErr
+		}
+
+		defer c.Release()
+
+		conn = c
+	}
+
+	rs, rsErr := db.DataSet[hit](&ctx, slog.Default(), conn, func(ctx *context.Context, tx *pgx.Tx)(string, string, *pgx.Rows, error){
+		qry := fmt.Sprintf("select %v.%v($1, $2, $3)", dbSchema, dbFunc)
+
+		// the role is only taken for the transaction, so that a request's
+		// connection keeps the one its middleware set
+		_, roleErr := (*tx).Exec(*ctx, fmt.Sprintf("set local role %v", dbRole))
+		if roleErr != nil {
+			slog.LogAttrs(*ctx, slog.LevelError, "set identity",
+				slog.String("error" , roleErr.Error()),
+				slog.String("role"  , dbRole),
+			)
+
+			return qry, dbFunc, nil, fmt.Errorf("set identity: %w", roleErr)
+		}
+
+		call, err := (*tx).Query(*ctx, qry, dbFunc, key, d.Seconds())
+		if err != nil {
+			slog.LogAttrs(*ctx, slog.LevelError, "count request",
+				slog.String("error", err.Error()),
+			)
+
+			return qry, dbFunc, nil, fmt.Errorf("call database function: %w", err)
+		}
+
+		return qry, dbFunc, &call, nil
+	})
+
+	if rsErr != nil {
+		return 0, 0, rsErr
+	}
+
+	if len(rs) != 1 {
+		return 0, 0, fmt.Errorf("expected 1 count, got %v", len(rs))
+	}
+
+	return rs[0].RlmCnt, time.Duration(rs[0].RlmLeft * float64(time.Second)), nil
+}
@@ -0,0 +1,229 @@
+package ratelimit
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"math"
+	"net"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"strings"
+	"time"
+)
+
+import (
+	"github.com/andrewah64/base-app-client/internal/common/core/key"
+	"github.com/andrewah64/base-app-client/internal/common/core/tenant"
+)
+
+const (
+	KeyIP     = "ip"
+	KeyAPIKey = "apikey"
+	KeyForm   = "form:"
+)
+
+// Reverse proxies whose X-Forwarded-For is believed, set by TrustProxies.
+var proxies []netip.Prefix
+
+// Rule allows Limit requests in each Window for every value of Key, which is
+// the client's IP address, the API key it sent or a form field, such as a
+// username.
+type Rule struct {
+	Key    string
+	Limit  int
+	Window time.Duration
+}
+
+// Store counts the requests made with a key in the current window. It
+// returns the count, including this request, and the time left in the window.
+type Store interface {
+	Hit(ctx context.Context, key string, window time.Duration) (int, time.Duration, error)
+}
+
+// Deny responds to a request that has gone over a limit. Retry-After has
+// already been set.
+type Deny func(rw http.ResponseWriter, r *http.Request, retryAfter time.Duration)
+
+// Parse reads rules written as a comma separated list of key=limit/window,
+// e.g. "ip=20/1m,form:ssn-aur-reg-aupc-aur-nm=5/15m".
+func Parse(args string) ([]Rule, error) {
+	var rules []Rule
+
+	for _, v := range strings.Split(args, ",") {
+		k, lw, ok := strings.Cut(strings.TrimSpace(v), "=")
+		if ! ok {
+			return nil, fmt.Errorf("rule '%v' is not key=limit/window", v)
+		}
+
+		if k != KeyIP && k != KeyAPIKey && (! strings.HasPrefix(k, KeyForm) || k == KeyForm) {
+			return nil, fmt.Errorf("rule '%v' has an unknown key '%v'", v, k)
+		}
+
+		l, w, ok := strings.Cut(lw, "/")
+		if ! ok {
+			return nil, fmt.Errorf("rule '%v' is not key=limit/window", v)
+		}
+
+		limit, lErr := strconv.Atoi(l)
+		if lErr != nil || limit < 1 {
+			return nil, fmt.Errorf("rule '%v' has an invalid limit '%v'", v, l)
+		}
+
+		window, wErr := time.ParseDuration(w)
+		if wErr != nil || window < time.Second {
+			return nil, fmt.Errorf("rule '%v' has an invalid window '%v'", v, w)
+		}
+
+		rules = append(rules, Rule{Key: k, Limit: limit, Window: window})
+	}
+
+	return rules, nil
+}
+
+// Handler counts each request against every rule and calls deny once any of
+// them is over its limit. Counts are kept per route and tenant. A store that
+// fails is logged and the request let through.
+func Handler(store Store, rules []Rule, deny Deny) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request){
+			ctx := r.Context()
+
+			var retryAfter time.Duration
+
+			for _, rule := range rules {
+				subject := Subject(r, rule.Key)
+				if subject == "" {
+					continue
+				}
+
+				k := hex.EncodeToString(key.Hash(strings.Join([]string{tenant.Origin(r), r.Pattern, rule.Key, subject}, "\n")))
+
+				count, left, hitErr := store.Hit(ctx, k, rule.Window)
+				if hitErr != nil {
+					slog.LogAttrs(ctx, slog.LevelError, "count request",
+						slog.String("key"   , rule.Key),
+						slog.String("error" , hitErr.Error()),
+					)
+
+					continue
+				}
+
+				if count > rule.Limit && left > retryAfter {
+					retryAfter = left
+				}
+			}
+
+			if retryAfter > 0 {
+				slog.LogAttrs(ctx, slog.LevelWarn, "throttle request",
+					slog.String  ("pattern"    , r.Pattern),
+					slog.Duration("retryAfter" , retryAfter),
+				)
+
+				rw.Header().Set("Retry-After", strconv.Itoa(Seconds(retryAfter)))
+
+				deny(rw, r, retryAfter)
+
+				return
+			}
+
+			next.ServeHTTP(rw, r)
+		})
+	}
+}
+
+// TrustProxies sets the reverse proxies, a comma separated list of CIDRs,
+// whose X-Forwarded-For gives the client's address. Without them every
+// client behind a proxy would share its limit. It is called once at startup,
+// before any request is served.
+func TrustProxies(cidrs string) error {
+	var p []netip.Prefix
+
+	for _, v := range strings.Split(cidrs, ",") {
+		if strings.TrimSpace(v) == "" {
+			continue
+		}
+
+		prefix, err := netip.ParsePrefix(strings.TrimSpace(v))
+		if err != nil {
+			return fmt.Errorf("proxy '%v' is not a CIDR: %w", v, err)
+		}
+
+		p = append(p, prefix.Masked())
+	}
+
+	proxies = p
+
+	return nil
+}
+
+func trusted(addr string) bool {
+	a, err := netip.ParseAddr(addr)
+	if err != nil {
+		return false
+	}
+
+	for _, v := range proxies {
+		if v.Contains(a.Unmap()) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ClientIP returns the address of the client that made r. When r came from
+// a trusted proxy, it is the last address in X-Forwarded-For that isn't one,
+// as anything before it could have been sent by the client.
+func ClientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+
+	if ! trusted(host) {
+		return host
+	}
+
+	var hops []string
+
+	for _, v := range r.Header.Values("X-Forwarded-For") {
+		hops = append(hops, strings.Split(v, ",")...)
+	}
+
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop := strings.TrimSpace(hops[i])
+
+		if ! trusted(hop) {
+			return hop
+		}
+
+		host = hop
+	}
+
+	return host
+}
+
+// Subject returns the value that a rule's key takes for r, or "" if r has
+// none.
+func Subject(r *http.Request, k string) string {
+	switch {
+		case k == KeyIP:
+			return ClientIP(r)
+		case k == KeyAPIKey:
+			_, token, _ := strings.Cut(r.Header.Get("Authorization"), " ")
+
+			return strings.TrimSpace(token)
+		case strings.HasPrefix(k, KeyForm):
+			return strings.ToLower(strings.TrimSpace(r.PostFormValue(strings.TrimPrefix(k, KeyForm))))
+	}
+
+	return ""
+}
+
+// Seconds rounds d up to whole seconds, as used by Retry-After.
+func Seconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"sync"
 )
 
 import (
@@ -21,17 +22,25 @@ type Route struct {
 	MiddlewareChain     string
 	HTTPRequestMethod   string
 	Role              []*string
+	CSRFExempt          bool
 }
 
 var (
-	cache map[string]*Route = make(map[string]*Route)
+	cacheMu sync.RWMutex
+	cache   map[string]*Route = make(map[string]*Route)
 )
 
 func Add(ctx *context.Context, logger *slog.Logger, key string, route *Route){
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
+
 	cache[key] = route
 }
 
 func CacheCopy () map[string]*Route {
+	cacheMu.RLock()
+	defer cacheMu.RUnlock()
+
 	cp := make(map[string]*Route)
 
 	for k, v := range cache {
@@ -42,6 +51,9 @@ func CacheCopy () map[string]*Route {
 }
 
 func Count () int {
+	cacheMu.RLock()
+	defer cacheMu.RUnlock()
+
 	return len(cache)
 }
 
@@ -50,7 +62,11 @@ func EndpointRoute(ctx *context.Context, logger *slog.Logger, endpoint string) (
 		slog.String("endpoint", endpoint),
 	)
 
-	if route, ok := cache[endpoint]; ok {
+	cacheMu.RLock()
+	route, ok := cache[endpoint]
+	cacheMu.RUnlock()
+
+	if ok {
 		return route, nil
 	} else {
 		return nil, fmt.Errorf("endpoint '%v' not found", endpoint)
@@ -82,19 +98,25 @@ func InitCache(ctx *context.Context, conn *pgxpool.Conn, dbSchema string, dbFunc
 		return rsErr
 	}
 
+	c := make(map[string]*Route, len(rs))
+
 	for _, v := range rs {
-		Add(ctx, slog.Default(), Key(v.HTTPRequestMethod, v.EndpointPath), &v)
+		c[Key(v.HTTPRequestMethod, v.EndpointPath)] = &v
 	}
 
-	if Count() == 0 || Count() != len(rs) {
+	if len(c) == 0 || len(c) != len(rs) {
 		slog.LogAttrs(*ctx, slog.LevelError, "route cache is empty",
-			slog.Int("Count()" , Count()),
+			slog.Int("len(c)"  , len(c)),
 			slog.Int("len(rs)" , len(rs)),
 		)
 
-		panic("The route cache was not initialised correctly")
+		return fmt.Errorf("the route cache was not initialised correctly")
 	}
 
+	cacheMu.Lock()
+	cache = c
+	cacheMu.Unlock()
+
 	return nil
 }
 
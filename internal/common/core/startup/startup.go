@@ -8,17 +8,22 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 )
 
 import (
 	"github.com/andrewah64/base-app-client/internal/common/core/db"
 	"github.com/andrewah64/base-app-client/internal/common/core/log"
+	"github.com/andrewah64/base-app-client/internal/common/core/mail"
+	"github.com/andrewah64/base-app-client/internal/common/core/password"
+	"github.com/andrewah64/base-app-client/internal/common/core/ratelimit"
 	"github.com/andrewah64/base-app-client/internal/common/core/session"
 	"github.com/andrewah64/base-app-client/internal/common/core/tenant"
 )
 
 import (
 	"github.com/jackc/pgx/v5/pgxpool"
+	"golang.org/x/crypto/bcrypt"
 )
 
 import (
@@ -28,50 +33,116 @@ import (
 )
 
 type RuntimeParams struct {
-	HttpPort    *int
-	LogLvl      *string
-	PgHost      *string
-	PgPort      *int
-	PgUser      *string
-	PgPw        *string
-	PgDb        *string
-	PgSslMode   *string
-	PgCacheSize *int
-	PgApp       *string
-	PgCred      *string
-	AwsProfile  *string
-	AwsSecretNm *string
+	HttpPort     *int
+	LogLvl       *string
+	PgHost       *string
+	PgPort       *int
+	PgUser       *string
+	PgPw         *string
+	PgDb         *string
+	PgSslMode    *string
+	PgCacheSize  *int
+	PgApp        *string
+	PgCred       *string
+	AwsProfile   *string
+	AwsSecretNm  *string
+	SsnCacheSize *int
+	SsnCacheTtl  *time.Duration
+	CspRptOnly   *bool
+	Dev          *bool
+	RlmStore     *string
+	RlmProxies   *string
+	MailTrn      *string
+	MailFrom     *string
+	MailDir      *string
+	SmtpHost     *string
+	SmtpPort     *int
+	SmtpUser     *string
+	SmtpPw       *string
+	PwdAlg       *string
+	PwdTime      *int
+	PwdMem       *int
+	PwdThreads   *int
+	PwdCost      *int
+	PwdTune      *time.Duration
+	PwdBrcDir    *string
+	PkyAaguid    *string
+	PkyMds       *string
 }
 
 func GetRuntimeParams () *RuntimeParams {
-	httpPort    := flag.Int   ("port"        , 8081        , "Port")
-	logLvl      := flag.String("loglvl"      , "info"      , "Level of default logger (debug|info|error)")
-	pgHost      := flag.String("pghost"      , "localhost" , "Host of PostgreSQL")
-	pgPort      := flag.Int   ("pgport"      , 5432        , "Port of PostgreSQL")
-	pgUser      := flag.String("pguser"      , "postgres"  , "Name of PostgreSQL user")
-	pgPw        := flag.String("pgpw"        , ""          , "Password for 'pguser'")
-	pgDb        := flag.String("pgdb"        , "base-app"  , "Database name")
-	pgSslMode   := flag.String("pgsslmode"   , "disable"   , "Secure connections to PG with SSL (enable|disable")
-	pgCacheSize := flag.Int   ("pgcachesize" , 0           , "Size of the PG statement cache")
-	pgApp       := flag.String("pgapp"       , "myapp"     , "Name of the application")
-	pgCred      := flag.String("pgcred"      , "systemd"   , "PostgreSQL password retrieval method (systemd)")
-	awsProfile  := flag.String("awsprofile"  , ""          , "AWS profile used to retrieve pgpw from secret's manager")
-	awsSecretNm := flag.String("awssecretnm" , ""          , "Name of AWS secret")
+	httpPort     := flag.Int     ("port"         , 8081             , "Port")
+	logLvl       := flag.String  ("loglvl"       , "info"           , "Level of default logger (debug|info|error)")
+	pgHost       := flag.String  ("pghost"       , "localhost"      , "Host of PostgreSQL")
+	pgPort       := flag.Int     ("pgport"       , 5432             , "Port of PostgreSQL")
+	pgUser       := flag.String  ("pguser"       , "postgres"       , "Name of PostgreSQL user")
+	pgPw         := flag.String  ("pgpw"         , ""               , "Password for 'pguser'")
+	pgDb         := flag.String  ("pgdb"         , "base-app"       , "Database name")
+	pgSslMode    := flag.String  ("pgsslmode"    , "disable"        , "Secure connections to PG with SSL (enable|disable")
+	pgCacheSize  := flag.Int     ("pgcachesize"  , 0                , "Size of the PG statement cache")
+	pgApp        := flag.String  ("pgapp"        , "myapp"          , "Name of the application")
+	pgCred       := flag.String  ("pgcred"       , "systemd"        , "PostgreSQL password retrieval method (systemd)")
+	awsProfile   := flag.String  ("awsprofile"   , ""               , "AWS profile used to retrieve pgpw from secret's manager")
+	awsSecretNm  := flag.String  ("awssecretnm"  , ""               , "Name of AWS secret")
+	ssnCacheSize := flag.Int     ("ssncachesize" , 1000             , "Maximum number of HTTP sessions held in the session cache (0 disables the cache)")
+	ssnCacheTtl  := flag.Duration("ssncachettl"  , 30 * time.Second , "Time an HTTP session is held in the session cache (0 disables the cache)")
+	cspRptOnly   := flag.Bool    ("csprptonly"   , false            , "Report Content-Security-Policy violations without enforcing the policy")
+	dev          := flag.Bool    ("dev"          , false            , "Serve the UI from ./ui on disk and reload templates and translations when they change")
+	rlmStore     := flag.String  ("rlmstore"     , "memory"         , "Where rate limit counts are kept (memory|postgres), postgres shares them between instances")
+	rlmProxies   := flag.String  ("rlmproxies"   , ""               , "Comma separated CIDRs of the reverse proxies whose X-Forwarded-For gives the client's address for rate limits, empty trusts none")
+	mailTrn      := flag.String  ("mailtrn"      , ""               , "How outbound email is delivered (smtp|file|log), file and log write out the links in it and are for development only")
+	mailFrom     := flag.String  ("mailfrom"     , "mail@localhost" , "Address outbound email is sent from")
+	mailDir      := flag.String  ("maildir"      , "mail"           , "Directory outbound email is written to when mailtrn is file")
+	smtpHost     := flag.String  ("smtphost"     , ""               , "Host of the SMTP server")
+	smtpPort     := flag.Int     ("smtpport"     , 587              , "Port of the SMTP server")
+	smtpUser     := flag.String  ("smtpuser"     , ""               , "Name of SMTP user, no authentication when empty")
+	smtpPw       := flag.String  ("smtppw"       , ""               , "Password for 'smtpuser'")
+	pwdAlg       := flag.String  ("pwdalg"       , "argon2id"       , "Algorithm new password hashes are made with (argon2id|bcrypt), older hashes are replaced at sign in")
+	pwdTime      := flag.Int     ("pwdtime"      , 3                , "Passes over memory made by argon2id")
+	pwdMem       := flag.Int     ("pwdmem"       , 64 * 1024        , "Memory used by argon2id (KiB)")
+	pwdThreads   := flag.Int     ("pwdthreads"   , 2                , "Threads used by argon2id")
+	pwdCost      := flag.Int     ("pwdcost"      , 12               , "Cost of bcrypt")
+	pwdTune      := flag.Duration("pwdtune"      , 0                , "Log the argon2id passes that take this long to hash a password with pwdmem and pwdthreads, then exit")
+	pwdBrcDir    := flag.String  ("pwdbrcdir"    , ""               , "Directory of the breached password list, SHA-1 hashes in files named after their 5 digit prefix, empty disables the check")
+	pkyAaguid    := flag.String  ("pkyaaguid"    , ""               , "File of passkey authenticator names by AAGUID, in the passkey-authenticator-aaguids JSON format, empty leaves them unnamed")
+	pkyMds       := flag.String  ("pkymds"       , ""               , "File of the FIDO Metadata Service BLOB that passkey attestation is verified against, empty leaves it unverifiable")
 
 	p := &RuntimeParams {
-		HttpPort    : httpPort,
-		LogLvl      : logLvl,
-		PgHost      : pgHost,
-		PgPort      : pgPort,
-		PgUser      : pgUser,
-		PgPw        : pgPw,
-		PgDb        : pgDb,
-		PgSslMode   : pgSslMode,
-		PgCacheSize : pgCacheSize,
-		PgApp       : pgApp,
-		PgCred      : pgCred,
-		AwsProfile  : awsProfile,
-		AwsSecretNm : awsSecretNm,
+		HttpPort     : httpPort,
+		LogLvl       : logLvl,
+		PgHost       : pgHost,
+		PgPort       : pgPort,
+		PgUser       : pgUser,
+		PgPw         : pgPw,
+		PgDb         : pgDb,
+		PgSslMode    : pgSslMode,
+		PgCacheSize  : pgCacheSize,
+		PgApp        : pgApp,
+		PgCred       : pgCred,
+		AwsProfile   : awsProfile,
+		AwsSecretNm  : awsSecretNm,
+		SsnCacheSize : ssnCacheSize,
+		SsnCacheTtl  : ssnCacheTtl,
+		CspRptOnly   : cspRptOnly,
+		Dev          : dev,
+		RlmStore     : rlmStore,
+		RlmProxies   : rlmProxies,
+		MailTrn      : mailTrn,
+		MailFrom     : mailFrom,
+		MailDir      : mailDir,
+		SmtpHost     : smtpHost,
+		SmtpPort     : smtpPort,
+		SmtpUser     : smtpUser,
+		SmtpPw       : smtpPw,
+		PwdAlg       : pwdAlg,
+		PwdTime      : pwdTime,
+		PwdMem       : pwdMem,
+		PwdThreads   : pwdThreads,
+		PwdCost      : pwdCost,
+		PwdTune      : pwdTune,
+		PwdBrcDir    : pwdBrcDir,
+		PkyAaguid    : pkyAaguid,
+		PkyMds       : pkyMds,
 	}
 
 	flag.Parse()
@@ -82,6 +153,30 @@ func GetRuntimeParams () *RuntimeParams {
 		provided[f.Name] = true
 	})
 
+	if *p.RlmStore != "memory" && *p.RlmStore != "postgres" {
+		panic(fmt.Sprintf("'rlmstore' can be (memory|postgres). '%v' is an invalid choice", *p.RlmStore))
+	}
+
+	if *p.MailTrn != "" && *p.MailTrn != "smtp" && *p.MailTrn != "file" && *p.MailTrn != "log" {
+		panic(fmt.Sprintf("'mailtrn' can be (smtp|file|log). '%v' is an invalid choice", *p.MailTrn))
+	}
+
+	if *p.MailTrn == "smtp" && ! provided["smtphost"] {
+		panic("smtphost must be supplied when mailtrn is smtp")
+	}
+
+	if *p.PwdAlg != password.Argon2id && *p.PwdAlg != password.Bcrypt {
+		panic(fmt.Sprintf("'pwdalg' can be (argon2id|bcrypt). '%v' is an invalid choice", *p.PwdAlg))
+	}
+
+	if *p.PwdTime < 1 || *p.PwdMem < 8 * *p.PwdThreads || *p.PwdThreads < 1 || *p.PwdThreads > 255 {
+		panic("pwdtime and pwdthreads must be at least 1, pwdthreads at most 255, and pwdmem at least 8 KiB per thread")
+	}
+
+	if *p.PwdCost < bcrypt.MinCost || *p.PwdCost > bcrypt.MaxCost {
+		panic(fmt.Sprintf("pwdcost must be between %v and %v", bcrypt.MinCost, bcrypt.MaxCost))
+	}
+
 	if ! provided["pgcred"] {
 		panic("pgcred must be supplied and can be (password-plain|password-systemd)")
 	} else {
@@ -203,3 +298,80 @@ func SetupTenantCache (ctx context.Context, conn *pgxpool.Conn) {
 		panic(tntCacheErr)
 	}
 }
+
+func SetupRateLimitStore (ctx context.Context, rtp *RuntimeParams, pool *pgxpool.Pool) ratelimit.Store {
+	slog.LogAttrs(ctx, slog.LevelInfo, "setup rate limit store",
+		slog.String("rlmStore"   , *rtp.RlmStore),
+		slog.String("rlmProxies" , *rtp.RlmProxies),
+	)
+
+	proxiesErr := ratelimit.TrustProxies(*rtp.RlmProxies)
+	if proxiesErr != nil {
+		slog.LogAttrs(ctx, slog.LevelError, "trust the reverse proxies",
+			slog.String("error", proxiesErr.Error()),
+		)
+
+		panic(proxiesErr)
+	}
+
+	if *rtp.RlmStore == "postgres" {
+		return ratelimit.NewPostgres(pool)
+	}
+
+	return ratelimit.NewMemory()
+}
+
+func SetupMailTransport (ctx context.Context, rtp *RuntimeParams) mail.Transport {
+	slog.LogAttrs(ctx, slog.LevelInfo, "setup mail transport",
+		slog.String("mailTrn", *rtp.MailTrn),
+	)
+
+	switch *rtp.MailTrn {
+		case "smtp":
+			return mail.NewSMTP(*rtp.SmtpHost, *rtp.SmtpPort, *rtp.SmtpUser, *rtp.SmtpPw, *rtp.MailFrom)
+		case "file":
+			return mail.NewFile(*rtp.MailDir, *rtp.MailFrom)
+		case "":
+			// there is no default, as file and log write out the password reset
+			// and verification links that must not end up on disk or in the log
+			// in production
+			panic("mailtrn must be supplied and can be (smtp|file|log)")
+	}
+
+	slog.LogAttrs(ctx, slog.LevelWarn, "outbound email is logged, not sent")
+
+	return mail.NewLog()
+}
+
+func SetupPassword (ctx context.Context, rtp *RuntimeParams) {
+	if *rtp.PwdTune > 0 {
+		p := password.Tune(*rtp.PwdTune, uint32(*rtp.PwdMem), uint8(*rtp.PwdThreads))
+
+		slog.LogAttrs(ctx, slog.LevelInfo, "tune password hashing",
+			slog.Duration("pwdTune"    , *rtp.PwdTune),
+			slog.Int     ("pwdtime"    , int(p.Time)),
+			slog.Int     ("pwdmem"     , int(p.Memory)),
+			slog.Int     ("pwdthreads" , int(p.Threads)),
+		)
+
+		os.Exit(0)
+	}
+
+	slog.LogAttrs(ctx, slog.LevelInfo, "setup password hashing",
+		slog.String("pwdAlg"    , *rtp.PwdAlg),
+		slog.String("pwdBrcDir" , *rtp.PwdBrcDir),
+	)
+
+	password.UseBreachList(*rtp.PwdBrcDir)
+
+	password.Configure(password.Params{
+		Alg     : *rtp.PwdAlg,
+		Time    : uint32(*rtp.PwdTime),
+		Memory  : uint32(*rtp.PwdMem),
+		Threads : uint8(*rtp.PwdThreads),
+		Cost    : *rtp.PwdCost,
+	})
+
+	// made now, rather than by the first sign in with an unknown username
+	password.Dummy()
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"log/slog"
 	"net/http"
+	"sync"
 )
 
 import (
@@ -17,7 +18,8 @@ import (
 )
 
 var (
-	cache map[string]int = make(map[string]int)
+	cacheMu sync.RWMutex
+	cache   map[string]int = make(map[string]int)
 )
 
 func InitCache(ctx *context.Context, conn *pgxpool.Conn) error {
@@ -58,16 +60,22 @@ func InitCache(ctx *context.Context, conn *pgxpool.Conn) error {
 		return rsErr
 	}
 
+	c := make(map[string]int, len(rs))
+
 	for _, v := range rs {
-		cache[v.TntOrigin] = v.TntId
+		c[v.TntOrigin] = v.TntId
 	}
 
-	if len(cache) == 0 {
+	if len(c) == 0 {
 		slog.LogAttrs(*ctx, slog.LevelError, "tenant cache is empty")
 
-		panic("The tenant cache is empty")
+		return fmt.Errorf("the tenant cache is empty")
 	}
 
+	cacheMu.Lock()
+	cache = c
+	cacheMu.Unlock()
+
 	return nil
 }
 
@@ -80,7 +88,11 @@ func Tenant(ctx *context.Context, logger *slog.Logger, origin string) int {
 		slog.String("origin", origin),
 	)
 
-	if tntId, ok := cache[origin]; ok {
+	cacheMu.RLock()
+	tntId, ok := cache[origin]
+	cacheMu.RUnlock()
+
+	if ok {
 		return tntId
 	} else {
 		slog.LogAttrs(*ctx, slog.LevelError, "tenant not found",
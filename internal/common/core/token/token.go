@@ -2,7 +2,9 @@ package token
 
 import (
 	"crypto/rand"
+	"crypto/sha256"
 	"encoding/base64"
+	"encoding/hex"
 )
 
 func Token(length int) (string, error) {
@@ -12,3 +14,11 @@ func Token(length int) (string, error) {
 	}
 	return base64.URLEncoding.EncodeToString(bytes), nil
 }
+
+// Hash returns the SHA-256 of t, for tokens that are sent to a user, such as
+// a password reset link, and must not be usable by anyone who can read the
+// database.
+func Hash(t string) string {
+	h := sha256.Sum256([]byte(t))
+	return hex.EncodeToString(h[:])
+}
@@ -0,0 +1,176 @@
+package brand
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"sync"
+)
+
+import (
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jackc/pgx/v5"
+)
+
+import (
+	"github.com/andrewah64/base-app-client/internal/common/core/db"
+)
+
+type Link struct {
+	Name string
+	Url  string
+}
+
+type Brand struct {
+	TntId      int
+	AppNm      string
+	LogoUrl    string
+	FaviconUrl string
+	PriClr     string
+	SecClr     string
+	Links      []Link
+}
+
+const (
+	defaultAppNm      = "Base App"
+	defaultLogoUrl    = "/static/img/logo.svg"
+	defaultFaviconUrl = "/static/img/logo.svg"
+	defaultPriClr     = "#4f46e5"
+	defaultSecClr     = "#374151"
+)
+
+var (
+	cacheMu sync.RWMutex
+	cache   map[int]*Brand = make(map[int]*Brand)
+	clrRe   *regexp.Regexp = regexp.MustCompile(`^#([0-9a-fA-F]{3}|[0-9a-fA-F]{6}|[0-9a-fA-F]{8})$`)
+)
+
+func InitCache(ctx *context.Context, conn *pgxpool.Conn) error {
+	slog.LogAttrs(*ctx, slog.LevelInfo, "initialise brand cache")
+
+	const (
+		dbSchema = "web_core_unauth_brd_tnt_inf"
+		dbFunc   = "brd_inf"
+	)
+
+	type brand struct {
+		TntId      int
+		AppNm      string
+		LogoUrl    *string
+		FaviconUrl *string
+		PriClr     *string
+		SecClr     *string
+		LnkNm      []string
+		LnkUrl     []string
+	}
+
+	rs, rsErr := db.DataSet[brand](ctx, slog.Default(), conn, func(ctx *context.Context, tx *pgx.Tx)(string, string, *pgx.Rows, error){
+		qry := fmt.Sprintf("select %v.%v($1)", dbSchema, dbFunc)
+
+		call, err := (*tx).Query(*ctx, qry, dbFunc)
+		if err != nil {
+			slog.LogAttrs(*ctx, slog.LevelError, "get brand data",
+				slog.String("error", err.Error()),
+			)
+
+			return qry, dbFunc, nil, fmt.Errorf("call database function: %w", err)
+		}
+
+		return qry, dbFunc, &call, nil
+	})
+
+	if rsErr != nil {
+		slog.LogAttrs(*ctx, slog.LevelError, "get brand info",
+			slog.String("error", rsErr.Error()),
+		)
+		return rsErr
+	}
+
+	c := make(map[int]*Brand, len(rs))
+
+	for _, v := range rs {
+		if len(v.LnkNm) != len(v.LnkUrl) {
+			return fmt.Errorf("tenant %v has %v footer link names and %v footer link urls", v.TntId, len(v.LnkNm), len(v.LnkUrl))
+		}
+
+		b := &Brand{
+			TntId      : v.TntId,
+			AppNm      : v.AppNm,
+			LogoUrl    : value(v.LogoUrl    , defaultLogoUrl),
+			FaviconUrl : value(v.FaviconUrl , defaultFaviconUrl),
+			PriClr     : colour(ctx, v.TntId, v.PriClr, defaultPriClr),
+			SecClr     : colour(ctx, v.TntId, v.SecClr, defaultSecClr),
+		}
+
+		for i := range v.LnkNm {
+			b.Links = append(b.Links, Link{Name: v.LnkNm[i], Url: v.LnkUrl[i]})
+		}
+
+		c[v.TntId] = b
+	}
+
+	cacheMu.Lock()
+	cache = c
+	cacheMu.Unlock()
+
+	slog.LogAttrs(*ctx, slog.LevelInfo, "initialised brand cache",
+		slog.Int("len(c)", len(c)),
+	)
+
+	return nil
+}
+
+// Tenant returns the brand of the tenant. Tenants without a brand are given
+// the application's default name, logo and colours.
+func Tenant(ctx *context.Context, logger *slog.Logger, tntId int) *Brand {
+	logger.LogAttrs(*ctx, slog.LevelDebug, "get brand",
+		slog.Int("tntId", tntId),
+	)
+
+	cacheMu.RLock()
+	b, ok := cache[tntId]
+	cacheMu.RUnlock()
+
+	if ok {
+		return b
+	}
+
+	logger.LogAttrs(*ctx, slog.LevelDebug, "brand not found, use default",
+		slog.Int("tntId", tntId),
+	)
+
+	return &Brand{
+		TntId      : tntId,
+		AppNm      : defaultAppNm,
+		LogoUrl    : defaultLogoUrl,
+		FaviconUrl : defaultFaviconUrl,
+		PriClr     : defaultPriClr,
+		SecClr     : defaultSecClr,
+	}
+}
+
+func value(v *string, d string) string {
+	if v == nil || *v == "" {
+		return d
+	}
+
+	return *v
+}
+
+// colour only accepts hex colours because the value is written verbatim into
+// the tenant's stylesheet.
+func colour(ctx *context.Context, tntId int, v *string, d string) string {
+	c := value(v, d)
+
+	if ! clrRe.MatchString(c) {
+		slog.LogAttrs(*ctx, slog.LevelError, "invalid brand colour, use default",
+			slog.Int   ("tntId"  , tntId),
+			slog.String("colour" , c),
+		)
+
+		return d
+	}
+
+	return c
+}
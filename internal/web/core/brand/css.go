@@ -0,0 +1,35 @@
+package brand
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+)
+
+import (
+	"github.com/andrewah64/base-app-client/internal/common/core/tenant"
+)
+
+// Stylesheet serves the tenant's colours as CSS custom properties together
+// with the utility classes the layouts use to apply them.
+func Stylesheet(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	tntId := tenant.Tenant(&ctx, slog.Default(), tenant.Origin(r))
+
+	b := Tenant(&ctx, slog.Default(), tntId)
+
+	rw.Header().Set("Content-Type" , "text/css; charset=utf-8")
+	rw.Header().Set("Cache-Control", "public, max-age=300")
+
+	fmt.Fprintf(rw, `:root {
+	--brand-primary   : %v;
+	--brand-secondary : %v;
+}
+
+.brand-bg-primary     { background-color : var(--brand-primary);   }
+.brand-border-primary { border-color     : var(--brand-primary);   }
+.brand-text-primary   { color            : var(--brand-primary);   }
+.brand-text-secondary { color            : var(--brand-secondary); }
+`, b.PriClr, b.SecClr)
+}
@@ -2,14 +2,119 @@ package error
 
 import (
 	"context"
+	"fmt"
 	"log/slog"
 	"net/http"
+	"slices"
 )
 
+import (
+	   "github.com/andrewah64/base-app-client/internal/common/core/csp"
+	   "github.com/andrewah64/base-app-client/internal/common/core/i18n"
+	cs "github.com/andrewah64/base-app-client/internal/common/core/session"
+	   "github.com/andrewah64/base-app-client/internal/web/core/brand"
+	   "github.com/andrewah64/base-app-client/internal/web/core/ui/data/page"
+	   "github.com/andrewah64/base-app-client/internal/web/core/ui/html"
+)
+
+import (
+	"github.com/google/uuid"
+)
+
+// Statuses that have a page of their own, any other status is shown as a 500.
+var statuses = []int{
+	http.StatusBadRequest,
+	http.StatusForbidden,
+	http.StatusNotFound,
+	http.StatusConflict,
+	http.StatusTooManyRequests,
+	http.StatusInternalServerError,
+}
+
+type request struct {
+	htmx  bool
+	lngCd string
+}
+
+type key int
+
+var requestKey key
+
+// NewContext returns a new Context that carries the details of r needed to
+// choose how an error is rendered.
+func NewContext(ctx context.Context, r *http.Request) context.Context {
+	return context.WithValue(ctx, requestKey, &request{
+		htmx  : html.Htmx(r),
+		lngCd : r.Header.Get("Accept-Language"),
+	})
+}
+
 func IntSrv(ctx context.Context, rw http.ResponseWriter, err error){
 	slog.LogAttrs(ctx, slog.LevelError, "Unexpected error",
 		slog.String("error" , err.Error()),
 	)
 
-	http.Error(rw, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+	Status(ctx, rw, http.StatusInternalServerError)
+}
+
+// Status renders the localised error page for status, quoting the request id
+// so the user can refer to it. htmx requests are given the page's content
+// only, swapped into the main element.
+func Status(ctx context.Context, rw http.ResponseWriter, status int){
+	if ! slices.Contains(statuses, status) {
+		status = http.StatusInternalServerError
+	}
+
+	req, ok := ctx.Value(requestKey).(*request)
+	if ! ok {
+		req = &request{}
+	}
+
+	reqId := uuid.NewString()
+	tntId := 0
+
+	if ssd, ok := cs.FromContext(ctx); ok {
+		reqId = ssd.RequestId
+		tntId = ssd.TntId
+	}
+
+	slog.LogAttrs(ctx, slog.LevelDebug, "render error",
+		slog.Int   ("status" , status),
+		slog.String("reqId"  , reqId),
+		slog.Bool  ("htmx"   , req.htmx),
+	)
+
+	var data page.Data
+
+	if d, ok := page.FromContext(ctx); ok {
+		data = *d
+	} else {
+		data = page.Data{
+			Brand     : brand.Tenant(&ctx, slog.Default(), tntId),
+			CSPNonce  : csp.Nonce(ctx),
+			Localiser : i18n.Localiser(ctx, slog.Default(), req.lngCd),
+		}
+	}
+
+	data.ResultSet = &map[string]any{
+		"Status"    : fmt.Sprintf("%d", status),
+		"RequestId" : reqId,
+	}
+
+	if req.htmx {
+		rw.Header().Set("HX-Retarget" , "#main")
+		rw.Header().Set("HX-Reswap"   , "innerHTML")
+
+		html.Fragment(ctx, slog.Default(), rw, nil, "core/all/err/fragment/err", status, &data)
+
+		return
+	}
+
+	cacheKey := "core/unauth/err/content"
+
+	if data.User != nil {
+		cacheKey = "core/auth/err/content"
+	}
+
+	html.Tmpl(ctx, slog.Default(), rw, nil, cacheKey, status, &data)
 }
@@ -0,0 +1,343 @@
+package feature
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+import (
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+import (
+	"github.com/andrewah64/base-app-client/internal/common/core/db"
+	"github.com/andrewah64/base-app-client/internal/common/core/session"
+)
+
+const (
+	TypeBool    = "bool"
+	TypePercent = "pct"
+	TypeList    = "lst"
+)
+
+const (
+	// Anonymous is the user id of a visitor who hasn't signed in.
+	Anonymous  = 0
+
+	// TenantWide is the group id of a flag value that applies to the whole
+	// tenant rather than to the members of one group.
+	TenantWide = 0
+)
+
+const (
+	flgChannel = "web_core_auth_flg_rfr"
+	flgRetry   = 5 * time.Second
+)
+
+// Flag is the value of a feature flag for a tenant, or for the members of a
+// group within the tenant.
+type Flag struct {
+	Name    string
+	Type    string
+	Bool    bool
+	Percent int
+	List    []string
+}
+
+type tenant struct {
+	flags  map[string]Flag
+	groups map[int]map[string]Flag
+}
+
+var (
+	cacheMu sync.RWMutex
+	cache   map[int]*tenant = make(map[int]*tenant)
+)
+
+// Parse converts the stored text of a flag into its typed value. Booleans are
+// "true" or "false", percentages are whole numbers from 0 to 100 and lists
+// are comma separated.
+func Parse(name string, typ string, val string) (Flag, bool) {
+	f := Flag{Name: name, Type: typ}
+
+	switch typ {
+		case TypeBool:
+			b, bErr := strconv.ParseBool(strings.TrimSpace(val))
+			if bErr != nil {
+				return f, false
+			}
+
+			f.Bool = b
+		case TypePercent:
+			p, pErr := strconv.Atoi(strings.TrimSpace(val))
+			if pErr != nil || p < 0 || p > 100 {
+				return f, false
+			}
+
+			f.Percent = p
+		case TypeList:
+			for _, v := range strings.Split(val, ",") {
+				if v = strings.TrimSpace(v); v != "" && ! slices.Contains(f.List, v) {
+					f.List = append(f.List, v)
+				}
+			}
+		default:
+			return f, false
+	}
+
+	return f, true
+}
+
+// String returns the text that Parse accepts for the flag's value.
+func (f Flag) String() string {
+	switch f.Type {
+		case TypeBool:
+			return strconv.FormatBool(f.Bool)
+		case TypePercent:
+			return strconv.Itoa(f.Percent)
+		case TypeList:
+			return strings.Join(f.List, ", ")
+	}
+
+	return ""
+}
+
+func InitCache(ctx *context.Context, conn *pgxpool.Conn) error {
+	slog.LogAttrs(*ctx, slog.LevelInfo, "initialise feature flag cache")
+
+	const (
+		dbSchema = "web_core_unauth_flg_tnt_inf"
+		dbFunc   = "flg_inf"
+	)
+
+	type flag struct {
+		TntId  int
+		GrpId  *int
+		FlgNm  string
+		FlgTyp string
+		FgvVal string
+	}
+
+	rs, rsErr := db.DataSet[flag](ctx, slog.Default(), conn, func(ctx *context.Context, tx *pgx.Tx)(string, string, *pgx.Rows, error){
+		qry := fmt.Sprintf("select %v.%v($1)", dbSchema, dbFunc)
+
+		call, err := (*tx).Query(*ctx, qry, dbFunc)
+		if err != nil {
+			slog.LogAttrs(*ctx, slog.LevelError, "get feature flag data",
+				slog.String("error", err.Error()),
+			)
+
+			return qry, dbFunc, nil, fmt.Errorf("call database function: %w", err)
+		}
+
+		return qry, dbFunc, &call, nil
+	})
+
+	if rsErr != nil {
+		slog.LogAttrs(*ctx, slog.LevelError, "get feature flag info",
+			slog.String("error", rsErr.Error()),
+		)
+		return rsErr
+	}
+
+	c := make(map[int]*tenant)
+
+	for _, v := range rs {
+		f, ok := Parse(v.FlgNm, v.FlgTyp, v.FgvVal)
+		if ! ok {
+			slog.LogAttrs(*ctx, slog.LevelError, "invalid feature flag value, ignore",
+				slog.Int   ("tntId"  , v.TntId),
+				slog.Any   ("grpId"  , v.GrpId),
+				slog.String("flgNm"  , v.FlgNm),
+				slog.String("flgTyp" , v.FlgTyp),
+				slog.String("fgvVal" , v.FgvVal),
+			)
+
+			continue
+		}
+
+		t, ok := c[v.TntId]
+		if ! ok {
+			t = &tenant{flags: make(map[string]Flag), groups: make(map[int]map[string]Flag)}
+			c[v.TntId] = t
+		}
+
+		if v.GrpId == nil {
+			t.flags[v.FlgNm] = f
+			continue
+		}
+
+		if _, ok := t.groups[*v.GrpId]; ! ok {
+			t.groups[*v.GrpId] = make(map[string]Flag)
+		}
+
+		t.groups[*v.GrpId][v.FlgNm] = f
+	}
+
+	cacheMu.Lock()
+	cache = c
+	cacheMu.Unlock()
+
+	slog.LogAttrs(*ctx, slog.LevelInfo, "initialised feature flag cache",
+		slog.Int("len(c)", len(c)),
+	)
+
+	return nil
+}
+
+// Listen reloads the feature flag cache whenever any process announces that
+// a tenant's flags have changed.
+func Listen(ctx *context.Context, pool *pgxpool.Pool) {
+	reload := func(){
+		conn, connErr := db.Conn(ctx, slog.Default(), pool)
+		if connErr != nil {
+			slog.LogAttrs(*ctx, slog.LevelError, "refresh feature flag cache",
+				slog.String("error", connErr.Error()),
+			)
+
+			return
+		}
+
+		defer conn.Release()
+
+		idErr := session.Identity(ctx, slog.Default(), conn, "role_web_core_unauth_flg_tnt_inf")
+		if idErr != nil {
+			slog.LogAttrs(*ctx, slog.LevelError, "refresh feature flag cache",
+				slog.String("error", idErr.Error()),
+			)
+
+			return
+		}
+
+		initErr := InitCache(ctx, conn)
+		if initErr != nil {
+			slog.LogAttrs(*ctx, slog.LevelError, "refresh feature flag cache",
+				slog.String("error", initErr.Error()),
+			)
+		}
+	}
+
+	go db.Listen(*ctx, pool, flgChannel, flgRetry, reload, func(payload string){ reload() })
+}
+
+// Notify announces that the feature flags of the tenant have changed.
+func Notify(ctx *context.Context, logger *slog.Logger, conn *pgxpool.Conn, tntId int) error {
+	return db.Notify(ctx, logger, conn, flgChannel, strconv.Itoa(tntId))
+}
+
+// Set is the view of the feature flags that applies to one user.
+type Set struct {
+	tntId int
+	aurId int
+	aurNm string
+	flags map[string]Flag
+}
+
+// For resolves the flags for a user of the tenant. When any of the user's
+// groups overrides a flag the tenant's value is ignored and the overrides are
+// combined: a boolean is on if any group turns it on, the highest percentage
+// wins and lists are merged. Visitors who haven't signed in have an aurId of
+// Anonymous and no groups.
+func For(tntId int, aurId int, aurNm string, grpIds []int) *Set {
+	s := &Set{
+		tntId : tntId,
+		aurId : aurId,
+		aurNm : aurNm,
+		flags : make(map[string]Flag),
+	}
+
+	cacheMu.RLock()
+	t, ok := cache[tntId]
+	cacheMu.RUnlock()
+
+	if ! ok {
+		return s
+	}
+
+	ovr := make(map[string]Flag)
+
+	for _, g := range grpIds {
+		for k, v := range t.groups[g] {
+			o, ok := ovr[k]
+
+			switch {
+				case ! ok:
+					o = Flag{Name: v.Name, Type: v.Type, Bool: v.Bool, Percent: v.Percent, List: slices.Clone(v.List)}
+				case v.Type == TypeBool:
+					o.Bool = o.Bool || v.Bool
+				case v.Type == TypePercent:
+					o.Percent = max(o.Percent, v.Percent)
+				case v.Type == TypeList:
+					for _, i := range v.List {
+						if ! slices.Contains(o.List, i) {
+							o.List = append(o.List, i)
+						}
+					}
+			}
+
+			ovr[k] = o
+		}
+	}
+
+	for k, v := range t.flags {
+		s.flags[k] = v
+	}
+
+	for k, v := range ovr {
+		s.flags[k] = v
+	}
+
+	return s
+}
+
+// Enabled reports whether the flag is on for the user. A percentage flag is
+// on for a stable share of signed in users and a list flag is on for the
+// users named in the list. Unknown flags are off.
+func (s *Set) Enabled(name string) bool {
+	f, ok := s.flags[name]
+	if ! ok {
+		return false
+	}
+
+	switch f.Type {
+		case TypeBool:
+			return f.Bool
+		case TypePercent:
+			if f.Percent >= 100 {
+				return true
+			}
+
+			if s.aurId == Anonymous {
+				return false
+			}
+
+			h := fnv.New32a()
+			fmt.Fprintf(h, "%v:%v:%v", name, s.tntId, s.aurId)
+
+			return int(h.Sum32() % 100) < f.Percent
+		case TypeList:
+			return s.aurNm != "" && slices.Contains(f.List, s.aurNm)
+	}
+
+	return false
+}
+
+func (s *Set) Percent(name string) int {
+	return s.flags[name].Percent
+}
+
+func (s *Set) List(name string) []string {
+	return slices.Clone(s.flags[name].List)
+}
+
+func (s *Set) Contains(name string, v string) bool {
+	return slices.Contains(s.flags[name].List, v)
+}
@@ -0,0 +1,43 @@
+package lockout
+
+import (
+	"context"
+	"time"
+)
+
+import (
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const (
+	// The longest a response to a failed sign in is held back.
+	maxDelay = 10 * time.Second
+)
+
+// Delay holds back the response to a failed sign in. The delay starts at
+// base and doubles with each of the earlier failures, up to maxDelay.
+//
+// conn, the request's database connection, is released first so that a
+// burst of failed sign ins can't tie up the pool while they wait. It can't
+// be used once Delay has been called.
+func Delay(ctx context.Context, conn *pgxpool.Conn, base time.Duration, failures int) {
+	conn.Release()
+
+	if base <= 0 {
+		return
+	}
+
+	d := base
+
+	for i := 0; i < failures && d < maxDelay; i++ {
+		d = d * 2
+	}
+
+	t := time.NewTimer(min(d, maxDelay))
+	defer t.Stop()
+
+	select {
+		case <-ctx.Done():
+		case <-t.C:
+	}
+}
@@ -0,0 +1,121 @@
+package mfa
+
+import (
+	"crypto/subtle"
+	"time"
+)
+
+import (
+	"github.com/pquerna/otp"
+	"github.com/pquerna/otp/totp"
+)
+
+const (
+	// Period is how many seconds a code is valid for, the time step.
+	Period = 30
+
+	// MfaNone signs a user in with their password alone, MfaOtp requires a
+	// TOTP code, MfaPky a passkey and MfaAny whichever of them the user has
+	// enrolled.
+	MfaNone = "none"
+	MfaOtp  = "otp"
+	MfaPky  = "pky"
+	MfaAny  = "any"
+
+	// MaxSkew is the most time steps either side of now a tenant can accept
+	// a code for.
+	MaxSkew = 3
+
+	// AlgSha1 and AlgSha256 are the algorithms authenticator apps make codes
+	// with.
+	AlgSha1   = "SHA1"
+	AlgSha256 = "SHA256"
+)
+
+// Otp reports whether a tenant's MFA policy mfaNm accepts a TOTP code as the
+// second factor.
+func Otp(mfaNm string) bool {
+	return mfaNm == MfaOtp || mfaNm == MfaAny
+}
+
+// Pky reports whether a tenant's MFA policy mfaNm accepts a passkey as the
+// second factor.
+func Pky(mfaNm string) bool {
+	return mfaNm == MfaPky || mfaNm == MfaAny
+}
+
+// Opts are a tenant's TOTP settings, from its aupc settings. Skew is how
+// many time steps either side of now a code is accepted for and Alg is
+// SHA1 or SHA256.
+type Opts struct {
+	Skew   int
+	Digits int
+	Alg    string
+}
+
+// Valid reports whether the digits and algorithm are ones authenticator apps
+// can make codes with, and the skew is between none and MaxSkew.
+func (o Opts) Valid() bool {
+	return o.Skew >= 0 && o.Skew <= MaxSkew && (o.Digits == 6 || o.Digits == 8) && (o.Alg == AlgSha1 || o.Alg == AlgSha256)
+}
+
+func (o Opts) algorithm() otp.Algorithm {
+	if o.Alg == AlgSha256 {
+		return otp.AlgorithmSHA256
+	}
+
+	return otp.AlgorithmSHA1
+}
+
+func (o Opts) digits() otp.Digits {
+	if o.Digits == 8 {
+		return otp.DigitsEight
+	}
+
+	return otp.DigitsSix
+}
+
+// Key returns the key whose otpauth URI and QR code are shown to a user
+// setting up their authenticator app.
+func Key(issuer string, aurNm string, secret []byte, o Opts) (*otp.Key, error) {
+	return totp.Generate(totp.GenerateOpts{
+		Issuer      : issuer,
+		AccountName : aurNm,
+		Secret      : secret,
+		Period      : Period,
+		Digits      : o.digits(),
+		Algorithm   : o.algorithm(),
+	})
+}
+
+// Validate reports whether code is valid now, within the skew, and returns
+// the time step it was made for. A code for step last or an earlier one is
+// rejected, so that the same code can't be accepted twice.
+func Validate(code string, secret string, o Opts, last int64) (int64, bool) {
+	var (
+		now  = time.Now().Unix() / Period
+		skew = int64(o.Skew)
+		opts = totp.ValidateOpts{
+			Period    : Period,
+			Digits    : o.digits(),
+			Algorithm : o.algorithm(),
+		}
+	)
+
+	for stp := now - skew; stp <= now + skew; stp++ {
+		if stp <= last {
+			continue
+		}
+
+		c, err := totp.GenerateCodeCustom(secret, time.Unix(stp * Period, 0).UTC(), opts)
+		if err != nil {
+			return 0, false
+		}
+
+		if subtle.ConstantTimeCompare([]byte(c), []byte(code)) == 1 {
+			return stp, true
+		}
+	}
+
+	return 0, false
+}
@@ -8,14 +8,18 @@ import (
 )
 
 import (
+	   "github.com/andrewah64/base-app-client/internal/common/core/csp"
 	   "github.com/andrewah64/base-app-client/internal/common/core/i18n"
 	   "github.com/andrewah64/base-app-client/internal/common/core/log"
 	   "github.com/andrewah64/base-app-client/internal/common/core/mw/auth"
 	   "github.com/andrewah64/base-app-client/internal/common/core/routes"
 	cs "github.com/andrewah64/base-app-client/internal/common/core/session"
+	   "github.com/andrewah64/base-app-client/internal/web/core/brand"
 	   "github.com/andrewah64/base-app-client/internal/web/core/error"
+	   "github.com/andrewah64/base-app-client/internal/web/core/feature"
 	ws "github.com/andrewah64/base-app-client/internal/web/core/session"
 	   "github.com/andrewah64/base-app-client/internal/web/core/ui/data/page"
+	   "github.com/andrewah64/base-app-client/internal/web/core/ui/html"
 )
 
 import (
@@ -46,16 +50,34 @@ func WebAuth(next http.Handler) http.Handler {
 
 			slog.LogAttrs(ctx, slog.LevelDebug, "validate http session info")
 
-			idErr := cs.Identity(&ctx, slog.Default(), ssd.Conn, "role_web_core_auth_ssn_aur_inf")
-			if idErr != nil {
-				error.IntSrv(ctx, rw, idErr)
-				return
-			}
+			var (
+				rs []ws.AuthSessionUser
+			)
+
+			if u, ok := ws.CachedAuthSessionUser(ssd.TntId, ssnTkn.Value, *eppPt, *hrmNm); ok {
+				slog.LogAttrs(ctx, slog.LevelDebug, "http session info found in cache")
+
+				rs = []ws.AuthSessionUser{*u}
+			} else {
+				gen := ws.CacheGeneration()
+
+				idErr := cs.Identity(&ctx, slog.Default(), ssd.Conn, "role_web_core_auth_ssn_aur_inf")
+				if idErr != nil {
+					error.IntSrv(ctx, rw, idErr)
+					return
+				}
+
+				asuiRs, asuiErr := ws.AuthSessionUserInfo(&ctx, slog.Default(), ssd.Conn, ssd.TntId, ssnTkn.Value, *eppPt, *hrmNm)
+				if asuiErr != nil{
+					error.IntSrv(ctx, rw, asuiErr)
+					return
+				}
+
+				rs = asuiRs
 
-			rs, rsErr := ws.AuthSessionUserInfo(&ctx, slog.Default(), ssd.Conn, ssd.TntId, ssnTkn.Value, *eppPt, *hrmNm)
-			if rsErr != nil{
-				error.IntSrv(ctx, rw, rsErr)
-				return
+				if len(rs) == 1 {
+					ws.CacheAuthSessionUser(gen, ssd.TntId, ssnTkn.Value, *eppPt, *hrmNm, rs[0])
+				}
 			}
 
 			switch len(rs){
@@ -73,13 +95,13 @@ func WebAuth(next http.Handler) http.Handler {
 						return
 					}
 
-					endErr := ws.End(&ctx, slog.Default(), ssd.Conn, rw, ssnTkn)
+					endErr := ws.End(&ctx, slog.Default(), ssd.Conn, rw, ssd.TntId, ssnTkn)
 					if endErr != nil {
-						error.IntSrv(ctx, rw, idErr)
+						error.IntSrv(ctx, rw, endErr)
 						return
 					}
 
-					rw.Header().Set("HX-Redirect", "/")
+					html.Redirect(rw, r, "/")
 
 					return
 				case 1:
@@ -94,6 +116,7 @@ func WebAuth(next http.Handler) http.Handler {
 						slog.Int   ("rs[0].LvlNb"                   , rs[0].LvlNb),
 						slog.String("rs[0].EppPt"                   , rs[0].EppPt),
 						slog.String("rs[0].HrmNm"                   , rs[0].HrmNm),
+						slog.Any   ("rs[0].GrpIds"                  , rs[0].GrpIds),
 					)
 
 					idErr := cs.Identity(&ctx, ssd.Logger, ssd.Conn, rs[0].RolName)
@@ -104,7 +127,7 @@ func WebAuth(next http.Handler) http.Handler {
 
 					rt, rtErr := routes.EndpointRoute(&ctx, ssd.Logger, routes.Key(*hrmNm, *eppPt))
 					if rtErr != nil {
-						error.IntSrv(ctx, rw, rsErr)
+						error.IntSrv(ctx, rw, rtErr)
 						return
 					}
 
@@ -122,6 +145,19 @@ func WebAuth(next http.Handler) http.Handler {
 							}
 					}
 
+					ctx = page.NewContext(
+						cs.NewContext(ctx, ssd),
+						&page.Data{
+							Brand     : brand.Tenant(&ctx, ssd.Logger, ssd.TntId),
+							CSRFToken : nosurf.Token(r),
+							CSPNonce  : csp.Nonce(ctx),
+							Flags     : feature.For(ssd.TntId, rs[0].AurId, rs[0].AurNm, rs[0].GrpIds),
+							User      : &rs[0],
+							Localiser : i18n.Localiser(ctx, ssd.Logger, rs[0].LngCd),
+							Query     : r.URL.Query(),
+						},
+					)
+
 					if a {
 						ssd.Logger.LogAttrs(ctx, slog.LevelDebug, "authorise access",
 							slog.Bool  ("rt.Role == nil", rt.Role == nil),
@@ -130,17 +166,7 @@ func WebAuth(next http.Handler) http.Handler {
 							slog.String("hrmNm"         , *hrmNm),
 						)
 
-						next.ServeHTTP(rw, r.WithContext(
-							page.NewContext(
-								cs.NewContext(ctx, ssd),
-									&page.Data{
-										CSRFToken : nosurf.Token(r),
-										User      : &rs[0],
-										Localiser : i18n.Localiser(ctx, ssd.Logger, rs[0].LngCd),
-									},
-								),
-							),
-						)
+						next.ServeHTTP(rw, r.WithContext(ctx))
 					} else {
 						ssd.Logger.LogAttrs(ctx, slog.LevelDebug, "deny access",
 							slog.Int   ("rs[0].AurId" , rs[0].AurId),
@@ -149,7 +175,7 @@ func WebAuth(next http.Handler) http.Handler {
 							slog.Any   ("rt.Role"     , rt.Role),
 						)
 
-						rw.Header().Set("HX-Location", fmt.Sprintf(`{"path":"%v", "target":"#main", "select":"#content"}`, rs[0].EppPt))
+						error.Status(ctx, rw, http.StatusForbidden)
 
 						return
 					}
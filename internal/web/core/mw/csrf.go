@@ -0,0 +1,73 @@
+package mware
+
+import (
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+import (
+	"github.com/andrewah64/base-app-client/internal/common/core/i18n"
+	"github.com/andrewah64/base-app-client/internal/common/core/routes"
+	"github.com/andrewah64/base-app-client/internal/web/core/ui/data/page"
+	"github.com/andrewah64/base-app-client/internal/web/core/ui/notification"
+)
+
+import (
+	"github.com/justinas/nosurf"
+)
+
+// CSRFExempt reports whether the route that mux would serve r with is
+// declared as exempt from CSRF checks, e.g. endpoints that receive posts from
+// an identity provider, or is one of the given paths that are not routes.
+func CSRFExempt(mux *http.ServeMux, paths ...string) func(*http.Request) bool {
+	return func(r *http.Request) bool {
+		ctx := r.Context()
+
+		if slices.Contains(paths, r.URL.Path) {
+			return true
+		}
+
+		_, pattern := mux.Handler(r)
+
+		hrmNm, eppPt, ok := strings.Cut(pattern, " ")
+		if ! ok {
+			return false
+		}
+
+		rt, rtErr := routes.EndpointRoute(&ctx, slog.Default(), routes.Key(hrmNm, eppPt))
+		if rtErr != nil {
+			return false
+		}
+
+		return rt.CSRFExempt
+	}
+}
+
+// CSRFFailure tells the user, in their language, that the request was
+// rejected. htmx requests are answered with a notification and no swap.
+func CSRFFailure(rw http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	slog.LogAttrs(ctx, slog.LevelWarn, "reject request, CSRF check failed",
+		slog.String("method" , r.Method),
+		slog.String("path"   , r.URL.Path),
+		slog.Any   ("reason" , nosurf.Reason(r)),
+	)
+
+	data := &page.Data{
+		CSRFToken : nosurf.Token(r),
+		Localiser : i18n.Localiser(ctx, slog.Default(), r.Header.Get("Accept-Language")),
+	}
+
+	if r.Header.Get("HX-Request") != "true" {
+		http.Error(rw, data.T("web-core-all-csrf.message-rejected"), http.StatusForbidden)
+		return
+	}
+
+	rw.Header().Set("HX-Reswap", "none")
+	rw.WriteHeader(http.StatusForbidden)
+
+	notification.Toast(ctx, slog.Default(), rw, r, "error" , &map[string]string{"Message" : data.T("web-core-all-csrf.message-rejected")}, data)
+}
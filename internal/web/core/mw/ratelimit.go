@@ -0,0 +1,37 @@
+package mware
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+import (
+	   "github.com/andrewah64/base-app-client/internal/common/core/ratelimit"
+	cs "github.com/andrewah64/base-app-client/internal/common/core/session"
+	   "github.com/andrewah64/base-app-client/internal/web/core/error"
+	   "github.com/andrewah64/base-app-client/internal/web/core/ui/data/page"
+	   "github.com/andrewah64/base-app-client/internal/web/core/ui/html"
+	   "github.com/andrewah64/base-app-client/internal/web/core/ui/notification"
+)
+
+// Throttled tells the user of an htmx request that they must wait with a
+// toast, any other request is given the 429 page.
+func Throttled(rw http.ResponseWriter, r *http.Request, retryAfter time.Duration) {
+	ctx := r.Context()
+
+	data, ok := page.FromContext(ctx)
+	if ! ok || ! html.Htmx(r) {
+		error.Status(ctx, rw, http.StatusTooManyRequests)
+		return
+	}
+
+	logger := slog.Default()
+
+	if ssd, ok := cs.FromContext(ctx); ok && ssd.Logger != nil {
+		logger = ssd.Logger
+	}
+
+	notification.ToastStatus(ctx, logger, rw, r, "error", &map[string]string{"Message" : data.T("web-core-all-err.toast-429", "Seconds", strconv.Itoa(ratelimit.Seconds(retryAfter)))}, http.StatusTooManyRequests, data)
+}
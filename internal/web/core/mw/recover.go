@@ -11,6 +11,8 @@ import (
 
 func Recover (next http.Handler) http.Handler {
 	return http.HandlerFunc(func (rw http.ResponseWriter, r *http.Request){
+		r = r.WithContext(error.NewContext(r.Context(), r))
+
 		defer func (){
 			if err := recover(); err != nil {
 				rw.Header().Set("Connection", "close")
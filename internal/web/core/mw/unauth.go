@@ -8,14 +8,18 @@ import (
 )
 
 import (
+	   "github.com/andrewah64/base-app-client/internal/common/core/csp"
 	   "github.com/andrewah64/base-app-client/internal/common/core/i18n"
 	   "github.com/andrewah64/base-app-client/internal/common/core/log"
 	   "github.com/andrewah64/base-app-client/internal/common/core/mw/auth"
 	   "github.com/andrewah64/base-app-client/internal/common/core/routes"
 	cs "github.com/andrewah64/base-app-client/internal/common/core/session"
+	   "github.com/andrewah64/base-app-client/internal/web/core/brand"
 	   "github.com/andrewah64/base-app-client/internal/web/core/error"
+	   "github.com/andrewah64/base-app-client/internal/web/core/feature"
 	ws "github.com/andrewah64/base-app-client/internal/web/core/session"
 	   "github.com/andrewah64/base-app-client/internal/web/core/ui/data/page"
+	   "github.com/andrewah64/base-app-client/internal/web/core/ui/html"
 )
 
 import (
@@ -63,8 +67,12 @@ func WebUnauth(next http.Handler) http.Handler {
 				page.NewContext(
 					ctx,
 					&page.Data{
+						Brand     : brand.Tenant(&ctx, ssd.Logger, ssd.TntId),
 						CSRFToken : nosurf.Token(r),
+						CSPNonce  : csp.Nonce(ctx),
+						Flags     : feature.For(ssd.TntId, feature.Anonymous, "", nil),
 						Localiser : i18n.Localiser(ctx, ssd.Logger, lngCd),
+						Query     : r.URL.Query(),
 					},
 				),
 			))
@@ -157,13 +165,13 @@ func WebUnauth(next http.Handler) http.Handler {
 						return
 					}
 
-					endErr := ws.End(&ctx, ssd.Logger, ssd.Conn, rw, ssnTkn)
+					endErr := ws.End(&ctx, ssd.Logger, ssd.Conn, rw, ssd.TntId, ssnTkn)
 					if endErr != nil {
-						error.IntSrv(ctx, rw, idErr)
+						error.IntSrv(ctx, rw, endErr)
 						return
 					}
 
-					rw.Header().Set("HX-Redirect", "/")
+					html.Redirect(rw, r, "/")
 
 					return
 				default:
@@ -0,0 +1,72 @@
+package passkey
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"os"
+	"sync"
+)
+
+import (
+	"github.com/go-webauthn/webauthn/metadata"
+)
+
+import (
+	"github.com/google/uuid"
+)
+
+var (
+	aaguidMu sync.RWMutex
+	aaguids  metadata.PasskeyAuthenticator = make(metadata.PasskeyAuthenticator)
+)
+
+// LoadAaguids reads the names of authenticators from file, a copy of the
+// combined.json listing of the passkey-authenticator-aaguids project. An
+// empty file leaves authenticators unnamed.
+func LoadAaguids(ctx *context.Context, file string) error {
+	if file == "" {
+		return nil
+	}
+
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+
+	a := make(metadata.PasskeyAuthenticator)
+
+	if err := json.Unmarshal(b, &a); err != nil {
+		return err
+	}
+
+	aaguidMu.Lock()
+	aaguids = a
+	aaguidMu.Unlock()
+
+	slog.LogAttrs(*ctx, slog.LevelInfo, "loaded authenticator names",
+		slog.String("file" , file),
+		slog.Int   ("len"  , len(a)),
+	)
+
+	return nil
+}
+
+// Name returns the name of the authenticator with the given AAGUID, or an
+// empty string when it isn't known.
+func Name(aaguid []byte) string {
+	id, err := uuid.FromBytes(aaguid)
+	if err != nil {
+		return ""
+	}
+
+	aaguidMu.RLock()
+	a, ok := aaguids[id.String()]
+	aaguidMu.RUnlock()
+
+	if ! ok {
+		return ""
+	}
+
+	return a.Name
+}
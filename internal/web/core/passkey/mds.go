@@ -0,0 +1,195 @@
+package passkey
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+import (
+	"github.com/go-webauthn/webauthn/metadata"
+	"github.com/go-webauthn/webauthn/metadata/providers/memory"
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/webauthn"
+)
+
+import (
+	"github.com/google/uuid"
+)
+
+const (
+	// PapNone accepts any authenticator, PapMds only those whose attestation
+	// is verified against the FIDO metadata.
+	PapNone = "none"
+	PapMds  = "mds"
+
+	// PalNone doesn't filter authenticators by AAGUID, PalAllow accepts only
+	// the listed ones and PalDeny all but the listed ones. An authenticator
+	// that isn't attested can claim any AAGUID, so a list is only saved
+	// with PapMds.
+	PalNone  = "none"
+	PalAllow = "allow"
+	PalDeny  = "deny"
+)
+
+var (
+	mdsMu   sync.RWMutex
+	mds     metadata.Provider
+	mdsNext time.Time
+)
+
+// Policy is a tenant's rules, from its aukc settings, for which
+// authenticators a passkey may be registered on.
+type Policy struct {
+	PapNm  string
+	PalNm  string
+	Aaguid []string
+}
+
+// LoadMds reads the FIDO Metadata Service BLOB from file, whose signature is
+// checked against the FIDO root certificate. An empty file leaves attestation
+// unverifiable, so a tenant requiring it can't register passkeys. Nor can
+// they once the BLOB is past its nextUpdate, as it may not list the
+// authenticators revoked since.
+func LoadMds(ctx *context.Context, file string) error {
+	if file == "" {
+		return nil
+	}
+
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+
+	d, err := metadata.NewDecoder(metadata.WithIgnoreEntryParsingErrors())
+	if err != nil {
+		return err
+	}
+
+	pl, err := d.DecodeBytes(b)
+	if err != nil {
+		return err
+	}
+
+	m, err := d.Parse(pl)
+	if err != nil {
+		return err
+	}
+
+	p, err := memory.New(memory.WithMetadata(m.ToMap()))
+	if err != nil {
+		return err
+	}
+
+	mdsMu.Lock()
+	mds, mdsNext = p, m.Parsed.NextUpdate
+	mdsMu.Unlock()
+
+	if time.Now().After(m.Parsed.NextUpdate) {
+		slog.LogAttrs(*ctx, slog.LevelWarn, "FIDO metadata is out of date, passkeys can't be registered where it is required",
+			slog.String("file"       , file),
+			slog.Time  ("nextUpdate" , m.Parsed.NextUpdate),
+		)
+	}
+
+	slog.LogAttrs(*ctx, slog.LevelInfo, "loaded FIDO metadata",
+		slog.String("file"          , file),
+		slog.Int   ("number"        , m.Parsed.Number),
+		slog.Time  ("nextUpdate"    , m.Parsed.NextUpdate),
+		slog.Int   ("len(entries)"  , len(m.Parsed.Entries)),
+		slog.Int   ("len(unparsed)" , len(m.Unparsed)),
+	)
+
+	return nil
+}
+
+// FinishRegistration finishes the registration of a passkey like the
+// tenant's WebAuthn does, rejecting authenticators that policy p doesn't
+// accept with an error that Rejected reports.
+func FinishRegistration(ctx *context.Context, logger *slog.Logger, tntId int, p Policy, user webauthn.User, sd webauthn.SessionData, r *http.Request) (*webauthn.Credential, error) {
+	w   := *WebAuthn(ctx, logger, tntId)
+	cfg := *w.Config
+
+	if p.PapNm == PapMds {
+		mdsMu.RLock()
+		cfg.MDS = mds
+		next := mdsNext
+		mdsMu.RUnlock()
+
+		if cfg.MDS == nil {
+			return nil, fmt.Errorf("FIDO metadata required by tenant %v is not loaded", tntId)
+		}
+
+		if time.Now().After(next) {
+			return nil, fmt.Errorf("FIDO metadata required by tenant %v was due to be replaced at %v", tntId, next)
+		}
+	}
+
+	if p.PalNm == PalAllow || p.PalNm == PalDeny {
+		var a []uuid.UUID = make([]uuid.UUID, len(p.Aaguid))
+		for i, v := range p.Aaguid {
+			id, err := uuid.Parse(v)
+			if err != nil {
+				return nil, fmt.Errorf("AAGUID %q of tenant %v: %w", v, tntId, err)
+			}
+
+			a[i] = id
+		}
+
+		if p.PalNm == PalAllow {
+			cfg.Filtering = &webauthn.FilteringConfig{PermittedAAGUIDs : a}
+		} else {
+			cfg.Filtering = &webauthn.FilteringConfig{ProhibitedAAGUIDs : a}
+		}
+	}
+
+	w.Config = &cfg
+
+	c, err := w.FinishRegistration(user, sd, r)
+	if err != nil {
+		return nil, err
+	}
+
+	// an empty allow list allows no authenticator at all, where the library
+	// would take it as no list
+	if p.PalNm == PalAllow && len(p.Aaguid) == 0 {
+		return nil, protocol.ErrPolicyRestriction.WithInfo("No AAGUID is permitted")
+	}
+
+	// an authenticator that gave no attestation, or attested only to itself,
+	// has nothing that the metadata can vouch for
+	if p.PapNm == PapMds && (c.AttestationType == string(metadata.None) || c.AttestationType == string(metadata.BasicSurrogate)) {
+		return nil, protocol.ErrPolicyRestriction.WithInfo(fmt.Sprintf("Attestation type %v can't be verified", c.AttestationType))
+	}
+
+	logger.LogAttrs(*ctx, slog.LevelDebug, "finish passkey registration",
+		slog.Int   ("tntId"           , tntId),
+		slog.String("papNm"           , p.PapNm),
+		slog.String("palNm"           , p.PalNm),
+		slog.String("attestationType" , c.AttestationType),
+	)
+
+	return c, nil
+}
+
+// Rejected reports whether err is from an authenticator being rejected by
+// the tenant's policy, rather than from the registration going wrong.
+func Rejected(err error) bool {
+	var pe *protocol.Error
+
+	if ! errors.As(err, &pe) {
+		return false
+	}
+
+	switch pe.Type {
+		case protocol.ErrPolicyRestriction.Type, protocol.ErrInvalidAttestation.Type, protocol.ErrMetadata.Type, protocol.ErrAttestationCertificate.Type:
+			return true
+		default:
+			return false
+	}
+}
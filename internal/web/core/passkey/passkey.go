@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"sync"
 )
 
 import (
@@ -20,7 +21,8 @@ import (
 )
 
 var (
-	cache map[int]*webauthn.WebAuthn = make(map[int]*webauthn.WebAuthn)
+	cacheMu sync.RWMutex
+	cache   map[int]*webauthn.WebAuthn = make(map[int]*webauthn.WebAuthn)
 )
 
 func InitCache(ctx *context.Context, conn *pgxpool.Conn) error {
@@ -61,6 +63,8 @@ func InitCache(ctx *context.Context, conn *pgxpool.Conn) error {
 		return rsErr
 	}
 
+	c := make(map[int]*webauthn.WebAuthn, len(rs))
+
 	for _, v := range rs {
 		wa, err := webauthn.New(
 			&webauthn.Config{
@@ -74,13 +78,17 @@ func InitCache(ctx *context.Context, conn *pgxpool.Conn) error {
 				slog.String("error", err.Error()),
 			)
 
-			panic("failed to initiatise webauthn")
+			return fmt.Errorf("initialise webauthn for tenant %v: %w", v.TntId, err)
 		}
-		cache[v.TntId] = wa
+		c[v.TntId] = wa
 	}
 
+	cacheMu.Lock()
+	cache = c
+	cacheMu.Unlock()
+
 	slog.LogAttrs(*ctx, slog.LevelInfo, "initialised webauthn",
-		slog.Any("cache", cache),
+		slog.Any("cache", c),
 	)
 
 	return nil
@@ -91,7 +99,11 @@ func WebAuthn (ctx *context.Context, logger *slog.Logger, tntId int) *webauthn.W
 		slog.Int("tntId", tntId),
 	)
 
-	if webauthn, ok := cache[tntId]; ok {
+	cacheMu.RLock()
+	webauthn, ok := cache[tntId]
+	cacheMu.RUnlock()
+
+	if ok {
 		return webauthn
 	} else {
 		slog.LogAttrs(*ctx, slog.LevelError, "webauthn not found",
@@ -0,0 +1,54 @@
+package recovery
+
+import (
+	"crypto/rand"
+	"encoding/base32"
+	"strings"
+)
+
+import (
+	"github.com/andrewah64/base-app-client/internal/common/core/token"
+)
+
+const (
+	// Count is how many codes a user is given at a time.
+	Count = 10
+	// Len is how many characters a code has, not counting its hyphens.
+	Len   = 16
+)
+
+var (
+	enc = base32.StdEncoding.WithPadding(base32.NoPadding)
+)
+
+// Codes returns Count new recovery codes, which are shown to the user once,
+// and their hashes, which are all the database keeps.
+func Codes() ([]string, []string, error) {
+	var (
+		cds  = make([]string, Count)
+		hshs = make([]string, Count)
+	)
+
+	for i := range cds {
+		b := make([]byte, Len * 5 / 8)
+		if _, err := rand.Read(b); err != nil {
+			return nil, nil, err
+		}
+
+		cd := enc.EncodeToString(b)
+
+		cds[i]  = cd[0:4] + "-" + cd[4:8] + "-" + cd[8:12] + "-" + cd[12:16]
+		hshs[i] = Hash(cd)
+	}
+
+	return cds, hshs, nil
+}
+
+// Hash returns the hash of a code as the user typed it, in any case and with
+// or without its hyphens and spaces.
+func Hash(cd string) string {
+	cd = strings.ToUpper(cd)
+	cd = strings.NewReplacer("-", "", " ", "").Replace(cd)
+
+	return token.Hash(cd)
+}
@@ -0,0 +1,92 @@
+package refresh
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+import (
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+import (
+	"github.com/andrewah64/base-app-client/internal/common/core/db"
+	"github.com/andrewah64/base-app-client/internal/common/core/session"
+	"github.com/andrewah64/base-app-client/internal/common/core/tenant"
+	"github.com/andrewah64/base-app-client/internal/web/core/brand"
+	"github.com/andrewah64/base-app-client/internal/web/core/feature"
+	"github.com/andrewah64/base-app-client/internal/web/core/passkey"
+	"github.com/andrewah64/base-app-client/internal/web/core/route"
+)
+
+const (
+	rfrChannel = "web_core_auth_tnt_rfr"
+	rfrRetry   = 5 * time.Second
+)
+
+var (
+	rfrMu sync.Mutex
+)
+
+// Listen reloads the tenant, passkey, brand, feature flag and route caches whenever another
+// process announces that a tenant has been created or changed.
+func Listen(ctx *context.Context, pool *pgxpool.Pool) {
+	reload := func(){
+		rfrErr := Caches(ctx, pool)
+		if rfrErr != nil {
+			slog.LogAttrs(*ctx, slog.LevelError, "refresh tenant caches",
+				slog.String("error", rfrErr.Error()),
+			)
+		}
+	}
+
+	go db.Listen(*ctx, pool, rfrChannel, rfrRetry, reload, func(payload string){ reload() })
+}
+
+// Notify announces that tenant is new or has changed. Every process,
+// including this one, reloads its caches when the notification arrives.
+func Notify(ctx *context.Context, logger *slog.Logger, conn *pgxpool.Conn, tntFqdn string) error {
+	return db.Notify(ctx, logger, conn, rfrChannel, tntFqdn)
+}
+
+func Caches(ctx *context.Context, pool *pgxpool.Pool) error {
+	rfrMu.Lock()
+	defer rfrMu.Unlock()
+
+	slog.LogAttrs(*ctx, slog.LevelInfo, "refresh tenant caches")
+
+	conn, connErr := db.Conn(ctx, slog.Default(), pool)
+	if connErr != nil {
+		return connErr
+	}
+
+	defer conn.Release()
+
+	caches := []struct {
+		rolNm string
+		init  func(*context.Context, *pgxpool.Conn) error
+	}{
+		{"role_all_core_unauth_tnt_all_inf" , tenant.InitCache},
+		{"role_all_core_unauth_tnt_all_inf" , passkey.InitCache},
+		{"role_web_core_unauth_brd_tnt_inf" , brand.InitCache},
+		{"role_web_core_unauth_flg_tnt_inf" , feature.InitCache},
+		{"role_web_core_unauth_rts_web_inf" , route.InitCache},
+	}
+
+	for _, v := range caches {
+		idErr := session.Identity(ctx, slog.Default(), conn, v.rolNm)
+		if idErr != nil {
+			return fmt.Errorf("set identity %v: %w", v.rolNm, idErr)
+		}
+
+		initErr := v.init(ctx, conn)
+		if initErr != nil {
+			return initErr
+		}
+	}
+
+	return nil
+}
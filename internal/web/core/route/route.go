@@ -13,48 +13,88 @@ import (
 )
 
 import (
+	   "github.com/andrewah64/base-app-client/internal/common/core/chain"
+	   "github.com/andrewah64/base-app-client/internal/common/core/csp"
 	cm "github.com/andrewah64/base-app-client/internal/common/core/mw"
+	   "github.com/andrewah64/base-app-client/internal/common/core/ratelimit"
 	   "github.com/andrewah64/base-app-client/internal/common/core/routes"
+	   "github.com/andrewah64/base-app-client/internal/web/core/brand"
 	wm "github.com/andrewah64/base-app-client/internal/web/core/mw"
+	   "github.com/andrewah64/base-app-client/internal/web/core/ui/asset"
 )
 
-import (
-	"github.com/andrewah64/base-app-client/ui"
-)
+// Chains returns the middleware and chains that web routes can refer to.
+// Routes are rate limited with, e.g. "web/unauth+ratelimit(ip=20/1m)", see
+// ratelimit.Parse.
+//
+// "web/signin" is for the sign in routes, which limit each client as the
+// passkey steps keep a challenge for every sign in that is begun.
+func Chains(cspRptOnly bool, store ratelimit.Store) *chain.Registry {
+	return chain.NewRegistry().
+		Middleware("csp"       , csp.Handler(csp.Default(cspRptOnly))).
+		Middleware("auth"      , wm.WebAuth).
+		Middleware("unauth"    , wm.WebUnauth).
+		Factory   ("ratelimit" , func(args string) (alice.Constructor, error) {
+			rules, err := ratelimit.Parse(args)
+			if err != nil {
+				return nil, err
+			}
+
+			return ratelimit.Handler(store, rules, wm.Throttled), nil
+		}).
+		Chain("web/auth"       , "csp", "auth").
+		Chain("web/unauth"     , "csp", "unauth").
+		Chain("web/signin"     , "csp", "unauth", "ratelimit(ip=30/1m)")
+}
 
-func Mux(ctx *context.Context, handlers map[string]http.HandlerFunc) (http.Handler) {
+func Mux(ctx *context.Context, handlers map[string]http.HandlerFunc, chains *chain.Registry) (http.Handler, error) {
 	slog.LogAttrs(*ctx, slog.LevelInfo, "load routes")
 
+	if err := chains.Validate(); err != nil {
+		slog.LogAttrs(*ctx, slog.LevelError, "validate middleware chains",
+			slog.String("error" , err.Error()),
+		)
+
+		return nil, err
+	}
+
 	mux := http.NewServeMux()
 
-	standard := alice.New(wm.Recover , cm.ResponseHeaders/*, cm.CSRFHandler*/)
-	auth     := alice.New(wm.WebAuth)
-	unauth   := alice.New(wm.WebUnauth)
+	standard := alice.New(cm.Compress, wm.Recover , cm.ResponseHeaders)
 
-	mux.Handle("GET /static/" , http.FileServerFS(ui.Files))
+	mux.Handle("GET /static/"           , asset.Handler())
+	mux.Handle("GET /brand/theme.css"   , http.HandlerFunc(brand.Stylesheet))
+	mux.Handle("POST " + csp.ReportPath , http.HandlerFunc(csp.Report))
 
 	cache := routes.CacheCopy()
 
 	for _, v := range cache {
-		switch v.MiddlewareChain {
-			case "web/auth":
-				slog.LogAttrs(*ctx, slog.LevelInfo, "register web/auth route",
-					slog.String("HTTPRequestMethod", v.HTTPRequestMethod),
-					slog.String("EndpointPath"     , v.EndpointPath),
-				)
-
-				mux.Handle(fmt.Sprintf("%v %v", v.HTTPRequestMethod, v.EndpointPath), auth.Then(handlers[v.Handler]))
-			case "web/unauth":
-				slog.LogAttrs(*ctx, slog.LevelInfo, "register web/unauth route",
-					slog.String("HTTPRequestMethod", v.HTTPRequestMethod),
-					slog.String("EndpointPath"     , v.EndpointPath),
-				)
-
-				mux.Handle(fmt.Sprintf("%v %v", v.HTTPRequestMethod, v.EndpointPath), unauth.Then(handlers[v.Handler]))
+		slog.LogAttrs(*ctx, slog.LevelInfo, "register web route",
+			slog.String("HTTPRequestMethod", v.HTTPRequestMethod),
+			slog.String("EndpointPath"     , v.EndpointPath),
+			slog.String("MiddlewareChain"  , v.MiddlewareChain),
+			slog.Bool  ("CSRFExempt"       , v.CSRFExempt),
+		)
+
+		c, cErr := chains.Resolve(v.MiddlewareChain)
+		if cErr != nil {
+			slog.LogAttrs(*ctx, slog.LevelError, "resolve middleware chain",
+				slog.String("EndpointPath" , v.EndpointPath),
+				slog.String("error"        , cErr.Error()),
+			)
+
+			return nil, fmt.Errorf("route '%v %v': %w", v.HTTPRequestMethod, v.EndpointPath, cErr)
 		}
+
+		h, ok := handlers[v.Handler]
+		if ! ok {
+			return nil, fmt.Errorf("route '%v %v': unknown handler '%v'", v.HTTPRequestMethod, v.EndpointPath, v.Handler)
+		}
+
+		mux.Handle(fmt.Sprintf("%v %v", v.HTTPRequestMethod, v.EndpointPath), c.Then(h))
 	}
 
-	return standard.Then(mux)
+	return standard.Then(cm.CSRFHandler(mux, wm.CSRFExempt(mux, csp.ReportPath), http.HandlerFunc(wm.CSRFFailure))), nil
 }
 
 func InitCache(ctx *context.Context, conn *pgxpool.Conn) error {
@@ -71,7 +111,7 @@ func InitCache(ctx *context.Context, conn *pgxpool.Conn) error {
 			slog.String("error" , err.Error()),
 		)
 
-		panic("The web application route cache was not initialised correctly")
+		return err
 	}
 
 	return nil
@@ -0,0 +1,237 @@
+package session
+
+import (
+	"container/list"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+import (
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+import (
+	"github.com/andrewah64/base-app-client/internal/common/core/db"
+	"github.com/andrewah64/base-app-client/internal/common/core/routes"
+)
+
+const (
+	rvkChannel    = "web_core_auth_ssn_aur_rvk"
+	rvkPayloadMax = 7900
+	rvkRetry      = 5 * time.Second
+)
+
+// Revocation identifies cached session lookups that are no longer valid.
+// When neither SsnTk nor AurId are supplied every entry belonging to the
+// tenant is revoked.
+type Revocation struct {
+	TntId   int    `json:"tntId"`
+	SsnTk []string `json:"ssnTk,omitempty"`
+	AurId []int    `json:"aurId,omitempty"`
+}
+
+type ssnKey struct {
+	tntId int
+	ssnTk string
+}
+
+type ssnEntry struct {
+	key     ssnKey
+	aurId   int
+	expTs   time.Time
+	users   map[string]AuthSessionUser
+}
+
+var (
+	ssnCacheMu   sync.Mutex
+	ssnCache     map[ssnKey]*list.Element = make(map[ssnKey]*list.Element)
+	ssnCacheLru  *list.List               = list.New()
+	ssnCacheSize int                      = 1000
+	ssnCacheTtl  time.Duration            = 30 * time.Second
+	ssnCacheGen  uint64
+)
+
+func InitCache(ctx *context.Context, pool *pgxpool.Pool, size int, ttl time.Duration) error {
+	slog.LogAttrs(*ctx, slog.LevelInfo, "initialise session cache",
+		slog.Int     ("size", size),
+		slog.Duration("ttl" , ttl),
+	)
+
+	if size < 0 || ttl < 0 {
+		return fmt.Errorf("session cache size and ttl must not be negative")
+	}
+
+	ssnCacheMu.Lock()
+	ssnCacheSize = size
+	ssnCacheTtl  = ttl
+	ssnCacheMu.Unlock()
+
+	if size == 0 || ttl == 0 {
+		slog.LogAttrs(*ctx, slog.LevelInfo, "session cache is disabled")
+		return nil
+	}
+
+	go db.Listen(*ctx, pool, rvkChannel, rvkRetry, flush, receive)
+
+	return nil
+}
+
+func CachedAuthSessionUser(tntId int, ssnTk string, eppPt string, hrmNm string) (*AuthSessionUser, bool) {
+	ssnCacheMu.Lock()
+	defer ssnCacheMu.Unlock()
+
+	e, ok := ssnCache[ssnKey{tntId, ssnTk}]
+	if ! ok {
+		return nil, false
+	}
+
+	se := e.Value.(*ssnEntry)
+
+	if time.Now().After(se.expTs) {
+		remove(e)
+		return nil, false
+	}
+
+	u, ok := se.users[routes.Key(hrmNm, eppPt)]
+	if ! ok {
+		return nil, false
+	}
+
+	ssnCacheLru.MoveToFront(e)
+
+	return &u, true
+}
+
+// CacheGeneration is read before a session lookup is made so that results
+// which raced with a revocation are not cached by CacheAuthSessionUser.
+func CacheGeneration() uint64 {
+	ssnCacheMu.Lock()
+	defer ssnCacheMu.Unlock()
+
+	return ssnCacheGen
+}
+
+func CacheAuthSessionUser(gen uint64, tntId int, ssnTk string, eppPt string, hrmNm string, user AuthSessionUser) {
+	ssnCacheMu.Lock()
+	defer ssnCacheMu.Unlock()
+
+	if ssnCacheSize == 0 || ssnCacheTtl == 0 || gen != ssnCacheGen {
+		return
+	}
+
+	k := ssnKey{tntId, ssnTk}
+
+	if e, ok := ssnCache[k]; ok {
+		se := e.Value.(*ssnEntry)
+
+		if se.aurId == user.AurId && time.Now().Before(se.expTs) {
+			se.users[routes.Key(hrmNm, eppPt)] = user
+			ssnCacheLru.MoveToFront(e)
+			return
+		}
+
+		remove(e)
+	}
+
+	for ssnCacheLru.Len() >= ssnCacheSize {
+		remove(ssnCacheLru.Back())
+	}
+
+	ssnCache[k] = ssnCacheLru.PushFront(&ssnEntry{
+		key   : k,
+		aurId : user.AurId,
+		expTs : time.Now().Add(ssnCacheTtl),
+		users : map[string]AuthSessionUser{routes.Key(hrmNm, eppPt) : user},
+	})
+}
+
+func Revoke(ctx *context.Context, logger *slog.Logger, conn *pgxpool.Conn, rvk Revocation) error {
+	evict(rvk)
+
+	payload, mErr := json.Marshal(rvk)
+	if mErr != nil {
+		return fmt.Errorf("marshal revocation: %w", mErr)
+	}
+
+	if len(payload) > rvkPayloadMax {
+		logger.LogAttrs(*ctx, slog.LevelDebug, "revocation too large to send, revoke tenant",
+			slog.Int("tntId"       , rvk.TntId),
+			slog.Int("len(payload)", len(payload)),
+		)
+
+		payload, _ = json.Marshal(Revocation{TntId: rvk.TntId})
+	}
+
+	return db.Notify(ctx, logger, conn, rvkChannel, string(payload))
+}
+
+func evict(rvk Revocation) {
+	ssnCacheMu.Lock()
+	defer ssnCacheMu.Unlock()
+
+	ssnCacheGen++
+
+	if len(rvk.SsnTk) == 0 && len(rvk.AurId) == 0 {
+		for k, e := range ssnCache {
+			if k.tntId == rvk.TntId {
+				remove(e)
+			}
+		}
+		return
+	}
+
+	for _, v := range rvk.SsnTk {
+		if e, ok := ssnCache[ssnKey{rvk.TntId, v}]; ok {
+			remove(e)
+		}
+	}
+
+	if len(rvk.AurId) > 0 {
+		aurId := make(map[int]bool, len(rvk.AurId))
+		for _, v := range rvk.AurId {
+			aurId[v] = true
+		}
+
+		for k, e := range ssnCache {
+			if k.tntId == rvk.TntId && aurId[e.Value.(*ssnEntry).aurId] {
+				remove(e)
+			}
+		}
+	}
+}
+
+func flush() {
+	ssnCacheMu.Lock()
+	defer ssnCacheMu.Unlock()
+
+	ssnCacheGen++
+
+	clear(ssnCache)
+	ssnCacheLru.Init()
+}
+
+func remove(e *list.Element) {
+	delete(ssnCache, e.Value.(*ssnEntry).key)
+	ssnCacheLru.Remove(e)
+}
+
+func receive(payload string) {
+	var rvk Revocation
+
+	if uErr := json.Unmarshal([]byte(payload), &rvk); uErr != nil {
+		slog.LogAttrs(context.Background(), slog.LevelError, "unmarshal revocation",
+			slog.String("error"   , uErr.Error()),
+			slog.String("payload" , payload),
+		)
+
+		flush()
+
+		return
+	}
+
+	evict(rvk)
+}
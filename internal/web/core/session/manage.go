@@ -63,7 +63,7 @@ func Begin(ctx *context.Context, logger *slog.Logger, conn *pgxpool.Conn, rw htt
 	return nil
 }
 
-func End(ctx *context.Context, logger *slog.Logger, conn *pgxpool.Conn, rw http.ResponseWriter, ssnTkn *http.Cookie) error {
+func End(ctx *context.Context, logger *slog.Logger, conn *pgxpool.Conn, rw http.ResponseWriter, tntId int, ssnTkn *http.Cookie) error {
 	expiry := time.Now()
 
 	http.SetCookie(rw, &http.Cookie{
@@ -98,5 +98,5 @@ func End(ctx *context.Context, logger *slog.Logger, conn *pgxpool.Conn, rw http.
 		return err
 	}
 
-	return nil
+	return Revoke(ctx, logger, conn, Revocation{TntId: tntId, SsnTk: []string{ssnTkn.Value}})
 }
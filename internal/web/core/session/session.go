@@ -24,6 +24,7 @@ type AuthSessionUser struct {
 	LvlNb     int
 	EppPt     string
 	HrmNm     string
+	GrpIds  []int
 }
 
 func AuthSessionUserInfo(ctx *context.Context, logger *slog.Logger, conn *pgxpool.Conn, tntId int, wauhsSsnTk string, eppPt string, hrmNm string) ([]AuthSessionUser, error){
@@ -0,0 +1,219 @@
+package asset
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io/fs"
+	"log/slog"
+	"mime"
+	"net/http"
+	"path"
+	"strings"
+	"sync"
+	"time"
+)
+
+import (
+	"github.com/andrewah64/base-app-client/internal/common/core/mw"
+	"github.com/andrewah64/base-app-client/ui"
+)
+
+const (
+	root = "static"
+
+	cacheImmutable  = "public, max-age=31536000, immutable"
+	cacheRevalidate = "no-cache"
+)
+
+// Extensions of the files worth compressing.
+var compressible = []string{".css", ".js", ".svg", "._hs", ".json", ".txt", ".html"}
+
+// asset is a static file with its fingerprint and gzipped variant.
+type asset struct {
+	Name  string
+	Hash  string
+	Type  string
+	Plain []byte
+	Gzip  []byte
+}
+
+var (
+	cacheMu sync.RWMutex
+	byPath  = make(map[string]*asset)
+	byUrl   = make(map[string]*asset)
+)
+
+// InitCache reads the static files, fingerprints them with a hash of their
+// content and gzips the text ones.
+func InitCache(ctx context.Context) error {
+	slog.LogAttrs(ctx, slog.LevelInfo, "start")
+
+	p := make(map[string]*asset)
+	u := make(map[string]*asset)
+
+	wdErr := fs.WalkDir(ui.FS, root, func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() || path.Ext(name) == ".gz" {
+			return nil
+		}
+
+		plain, readErr := fs.ReadFile(ui.FS, name)
+		if readErr != nil {
+			return readErr
+		}
+
+		sum := sha256.Sum256(plain)
+
+		a := &asset{
+			Name  : name,
+			Hash  : hex.EncodeToString(sum[:])[:12],
+			Type  : contentType(name),
+			Plain : plain,
+		}
+
+		if compress(name) {
+			var buf bytes.Buffer
+
+			zw, _ := gzip.NewWriterLevel(&buf, gzip.BestCompression)
+			zw.Write(plain)
+			zw.Close()
+
+			if buf.Len() < len(plain) {
+				a.Gzip = buf.Bytes()
+			}
+		}
+
+		p["/" + name] = a
+		u[fingerprint("/" + name, a.Hash)] = a
+
+		slog.LogAttrs(ctx, slog.LevelDebug, "load asset",
+			slog.String("name" , name),
+			slog.String("hash" , a.Hash),
+			slog.Int   ("plain", len(a.Plain)),
+			slog.Int   ("gzip" , len(a.Gzip)),
+		)
+
+		return nil
+	})
+
+	if wdErr != nil {
+		slog.LogAttrs(ctx, slog.LevelError, "load assets",
+			slog.String("error" , wdErr.Error()),
+		)
+
+		return wdErr
+	}
+
+	cacheMu.Lock()
+	byPath, byUrl = p, u
+	cacheMu.Unlock()
+
+	slog.LogAttrs(ctx, slog.LevelInfo, "end",
+		slog.Int("len(byPath)", len(p)),
+	)
+
+	return nil
+}
+
+// URL returns the fingerprinted URL of the static file at urlPath, e.g.
+// "/static/js/htmx.min.js" becomes "/static/js/htmx.min.<hash>.js". Unknown
+// files, and every file in dev mode, keep their path.
+func URL(urlPath string) string {
+	if ui.Dev {
+		return urlPath
+	}
+
+	cacheMu.RLock()
+	a, ok := byPath[urlPath]
+	cacheMu.RUnlock()
+
+	if ! ok {
+		return urlPath
+	}
+
+	return fingerprint(urlPath, a.Hash)
+}
+
+// Handler serves the static files. Fingerprinted URLs are cached for a year,
+// plain ones are revalidated against their ETag. The gzipped variant is
+// negotiated with Accept-Encoding.
+func Handler() http.Handler {
+	if ui.Dev {
+		return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request){
+			rw.Header().Set("Cache-Control", cacheRevalidate)
+
+			http.FileServerFS(ui.FS).ServeHTTP(rw, r)
+		})
+	}
+
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request){
+		cc := cacheImmutable
+
+		cacheMu.RLock()
+		a, ok := byUrl[r.URL.Path]
+		if ! ok {
+			a, ok = byPath[r.URL.Path]
+			cc    = cacheRevalidate
+		}
+		cacheMu.RUnlock()
+
+		if ! ok {
+			http.NotFound(rw, r)
+			return
+		}
+
+		body     := a.Plain
+		encoding := ""
+
+		if a.Gzip != nil && mware.Accepts(r.Header.Get("Accept-Encoding"), "gzip") {
+			body, encoding = a.Gzip, "gzip"
+		}
+
+		h := rw.Header()
+
+		h.Set("Cache-Control", cc)
+		h.Set("Content-Type" , a.Type)
+		h.Set("ETag"         , `"` + a.Hash + `"`)
+
+		if a.Gzip != nil {
+			h.Set("Vary", "Accept-Encoding")
+		}
+
+		if encoding != "" {
+			h.Set("Content-Encoding", encoding)
+			h.Set("ETag"            , `"` + a.Hash + "-" + encoding + `"`)
+		}
+
+		http.ServeContent(rw, r, a.Name, time.Time{}, bytes.NewReader(body))
+	})
+}
+
+func fingerprint(urlPath string, hash string) string {
+	ext := path.Ext(urlPath)
+
+	return strings.TrimSuffix(urlPath, ext) + "." + hash + ext
+}
+
+func compress(name string) bool {
+	for _, ext := range compressible {
+		if strings.HasSuffix(name, ext) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func contentType(name string) string {
+	if t := mime.TypeByExtension(path.Ext(name)); t != "" {
+		return t
+	}
+
+	return "text/plain; charset=utf-8"
+}
@@ -3,11 +3,14 @@ package page
 import (
 	"context"
 	"log/slog"
+	"net/url"
 	"slices"
 	"time"
 )
 
 import (
+	"github.com/andrewah64/base-app-client/internal/web/core/brand"
+	"github.com/andrewah64/base-app-client/internal/web/core/feature"
 	"github.com/andrewah64/base-app-client/internal/web/core/session"
 )
 
@@ -16,12 +19,16 @@ import (
 )
 
 type Data struct {
+	Brand            *brand.Brand
 	CSRFToken        string
+	CSPNonce         string
+	Flags            *feature.Set
 	User             *session.AuthSessionUser
 	FormOpts         *map[string]any
 	NotificationData *map[string]any
 	ResultSet        *map[string]any
 	Localiser        *i18n.Localizer
+	Query            url.Values
 }
 
 type key int
@@ -54,6 +61,21 @@ func (D Data) HasRole(role string) bool {
 	return slices.Contains(D.User.Roles, role)
 }
 
+// Flag reports whether the feature flag is on for the user viewing the page.
+func (D Data) Flag(name string) bool {
+	if D.Flags == nil {
+		return false
+	}
+
+	return D.Flags.Enabled(name)
+}
+
+// Param returns the value of the request's query parameter, so that a
+// bookmarked search shows the filters it was made with.
+func (D Data) Param(name string) string {
+	return D.Query.Get(name)
+}
+
 func (D Data) T(id string, params ...string) string{
 	td := make(map[string]interface{})
 
@@ -67,6 +89,10 @@ func (D Data) T(id string, params ...string) string{
 		}
 	}
 
+	if _, ok := td["appNm"]; ! ok && D.Brand != nil {
+		td["appNm"] = D.Brand.AppNm
+	}
+
 	return D.Localiser.MustLocalize(
 		&i18n.LocalizeConfig{
 			MessageID   : id,
@@ -0,0 +1,112 @@
+package html
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Location is the page that an htmx request is sent to with HX-Location.
+type Location struct {
+	Path   string            `json:"path"`
+	Target string            `json:"target,omitempty"`
+	Select string            `json:"select,omitempty"`
+	Swap   string            `json:"swap,omitempty"`
+	Values map[string]string `json:"values,omitempty"`
+}
+
+// Htmx reports whether r was made by htmx to swap part of a page. Full page
+// loads, refreshes, bookmarks, boosted links and history restores are not.
+func Htmx(r *http.Request) bool {
+	return r.Header.Get("HX-Request") == "true" && r.Header.Get("HX-Boosted") != "true" && r.Header.Get("HX-History-Restore-Request") != "true"
+}
+
+// Trigger returns the id of the element that triggered r when it was made by
+// htmx. Any other request with a query string, such as a bookmarked search,
+// is taken as a submit of form, and one without as a page load, "". Pages
+// with no search to bookmark pass "" as form.
+func Trigger(r *http.Request, form string) string {
+	switch {
+		case Htmx(r):
+			return r.Header.Get("HX-Trigger")
+		case r.URL.RawQuery != "":
+			return form
+		default:
+			return ""
+	}
+}
+
+// CurrentURL returns the URL of the page that r was made from, which is
+// where it is sent back to after an error such as a stale save. It falls
+// back to the Referer, or the home page, when r wasn't made by htmx.
+func CurrentURL(r *http.Request) string {
+	if u := r.Header.Get("HX-Current-URL"); Htmx(r) && u != "" {
+		return u
+	}
+
+	if u := r.Referer(); u != "" {
+		return u
+	}
+
+	return "/"
+}
+
+// Partial renders cacheKey, a fragment or a page's template, for htmx
+// requests. Any other request is given the full page at pageKey, which embeds
+// the same template, so the URL of a partial can be linked to or bookmarked.
+func Partial(ctx context.Context, logger *slog.Logger, rw http.ResponseWriter, r *http.Request, pageKey string, cacheKey string, status int, data any) {
+	logger.LogAttrs(ctx, slog.LevelDebug, "get partial",
+		slog.String("pageKey"  , pageKey),
+		slog.String("cacheKey" , cacheKey),
+		slog.Bool  ("htmx"     , Htmx(r)),
+	)
+
+	switch {
+		case ! Htmx(r):
+			Tmpl(ctx, logger, rw, r, pageKey, status, data)
+		case strings.Contains(cacheKey, "/template/"):
+			Tmpl(ctx, logger, rw, r, cacheKey, status, data)
+		default:
+			Fragment(ctx, logger, rw, r, cacheKey, status, data)
+	}
+}
+
+// Redirect sends htmx requests to path with HX-Redirect and any other request
+// with a 303.
+func Redirect(rw http.ResponseWriter, r *http.Request, path string) {
+	if Htmx(r) {
+		rw.Header().Set("HX-Redirect", path)
+		return
+	}
+
+	http.Redirect(rw, r, path, http.StatusSeeOther)
+}
+
+// Locate sends htmx requests to loc with HX-Location. Any other request is
+// redirected to loc's path, with its values in the query string.
+func Locate(rw http.ResponseWriter, r *http.Request, loc Location) {
+	if Htmx(r) {
+		l, _ := json.Marshal(loc)
+
+		rw.Header().Set("HX-Location", string(l))
+
+		return
+	}
+
+	path := loc.Path
+
+	if len(loc.Values) > 0 {
+		v := url.Values{}
+
+		for key, val := range loc.Values {
+			v.Set(key, val)
+		}
+
+		path = path + "?" + v.Encode()
+	}
+
+	http.Redirect(rw, r, path, http.StatusSeeOther)
+}
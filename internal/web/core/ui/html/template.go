@@ -11,16 +11,25 @@ import (
 	"path/filepath"
 	"slices"
 	"strings"
+	"sync"
 	"time"
 )
 
 import (
-	e "github.com/andrewah64/base-app-client/internal/web/core/error"
-	  "github.com/andrewah64/base-app-client/ui"
+	"github.com/andrewah64/base-app-client/internal/common/core/i18n"
+	"github.com/andrewah64/base-app-client/internal/web/core/ui/asset"
+	"github.com/andrewah64/base-app-client/ui"
 )
 
 var (
-	cache map[string]*template.Template = make(map[string]*template.Template)
+	cache   map[string]*template.Template = make(map[string]*template.Template)
+	cacheMu sync.RWMutex
+	loaded  time.Time
+	loadErr error
+
+	funcs = template.FuncMap{
+		"asset" : asset.URL,
+	}
 )
 
 func HiddenUtsFragment(rw http.ResponseWriter, cid string, id string, name string, uts time.Time, format string) {
@@ -30,23 +39,63 @@ func HiddenUtsFragment(rw http.ResponseWriter, cid string, id string, name strin
 	rw.Write([]byte(frag))
 }
 
+// Fragment writes the fragment with the given status. http.StatusOK is left
+// to the first write, so a fragment appended to a response that already has a
+// status, such as a notification, does not try to set it again.
 func Fragment(ctx context.Context, logger *slog.Logger, rw http.ResponseWriter, r *http.Request, cacheKey string, status int, data any) {
 	logger.LogAttrs(ctx, slog.LevelDebug, "get fragment",
 		slog.String("cacheKey", cacheKey),
+		slog.Int   ("status"  , status),
 		slog.String("data"    , fmt.Sprintf("%v", data)),
 	)
 
-	if tmpl, ok := cache[cacheKey]; ok {
-		err := template.Must(tmpl, nil).Execute(rw, data)
+	tmpl, ok, devErr := lookup(ctx, cacheKey)
+	if devErr != nil {
+		showDevErr(ctx, rw, devErr)
+		return
+	}
+
+	if ok {
+		buf := new(bytes.Buffer)
+
+		err := template.Must(tmpl, nil).Execute(buf, data)
 		if err != nil {
-			e.IntSrv(ctx, rw, err)
+			execErr(ctx, rw, err)
 			return
 		}
+
+		if status != http.StatusOK {
+			rw.WriteHeader(status)
+		}
+
+		buf.WriteTo(rw)
 	} else {
 		panic(fmt.Sprintf("fragment '%v' not found", cacheKey))
 	}
 }
 
+// Render returns the fragment instead of writing it, for output that is not a
+// response, such as the body of an email.
+func Render(ctx context.Context, cacheKey string, data any) ([]byte, error) {
+	tmpl, ok, devErr := lookup(ctx, cacheKey)
+	if devErr != nil {
+		return nil, devErr
+	}
+
+	if ! ok {
+		return nil, fmt.Errorf("fragment '%v' not found", cacheKey)
+	}
+
+	buf := new(bytes.Buffer)
+
+	err := tmpl.Execute(buf, data)
+	if err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
 func Tmpl(ctx context.Context, logger *slog.Logger, rw http.ResponseWriter, r *http.Request, cacheKey string, status int, data any) {
 	cacheKeySegments := strings.Split(cacheKey, "/")
 	tmplType         := strings.Split(cacheKey, "/")[1]
@@ -60,12 +109,18 @@ func Tmpl(ctx context.Context, logger *slog.Logger, rw http.ResponseWriter, r *h
 		slog.String("tmplType", tmplType),
 	)
 
-	if tmpl, ok := cache[cacheKey]; ok {
+	tmpl, ok, devErr := lookup(ctx, cacheKey)
+	if devErr != nil {
+		showDevErr(ctx, rw, devErr)
+		return
+	}
+
+	if ok {
 		buf := new(bytes.Buffer)
 
 		err := template.Must(tmpl, nil).ExecuteTemplate(buf, tmplType, data)
 		if err != nil {
-			e.IntSrv(ctx, rw, err)
+			execErr(ctx, rw, err)
 			return
 		}
 
@@ -77,29 +132,128 @@ func Tmpl(ctx context.Context, logger *slog.Logger, rw http.ResponseWriter, r *h
 	}
 }
 
+// execErr answers with a plain 500, as the error pages are templates too. In
+// dev mode the error itself is shown.
+func execErr(ctx context.Context, rw http.ResponseWriter, err error) {
+	if ui.Dev {
+		showDevErr(ctx, rw, err)
+		return
+	}
+
+	slog.LogAttrs(ctx, slog.LevelError, "execute template",
+		slog.String("error" , err.Error()),
+	)
+
+	http.Error(rw, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+}
+
 func InitCache(ctx context.Context){
+	c, err := parse(ctx)
+	if err != nil {
+		slog.LogAttrs(ctx, slog.LevelError, "load templates",
+			slog.String("error" , err.Error()),
+		)
+
+		if ! ui.Dev {
+			panic(fmt.Sprintf("failed to load templates %v", err.Error()))
+		}
+	}
+
+	cacheMu.Lock()
+	cache, loaded, loadErr = c, time.Now(), err
+	cacheMu.Unlock()
+}
+
+// lookup returns the template cached under cacheKey. In dev mode the
+// templates are parsed again first if any of them have changed, and a parse
+// error, of the templates or the translations, is returned.
+func lookup(ctx context.Context, cacheKey string) (*template.Template, bool, error) {
+	if ui.Dev {
+		cacheMu.RLock()
+		stale := ui.ModTime("html").After(loaded)
+		cacheMu.RUnlock()
+
+		if stale {
+			slog.LogAttrs(ctx, slog.LevelInfo, "reload templates")
+
+			c, err := parse(ctx)
+
+			cacheMu.Lock()
+			if err == nil {
+				cache = c
+			}
+			loaded, loadErr = time.Now(), err
+			cacheMu.Unlock()
+		}
+
+		if err := i18n.Err(); err != nil {
+			return nil, false, err
+		}
+	}
+
+	cacheMu.RLock()
+	defer cacheMu.RUnlock()
+
+	if loadErr != nil {
+		return nil, false, loadErr
+	}
+
+	tmpl, ok := cache[cacheKey]
+
+	return tmpl, ok, nil
+}
+
+// showDevErr shows a dev mode parse error in the browser.
+func showDevErr(ctx context.Context, rw http.ResponseWriter, err error) {
+	slog.LogAttrs(ctx, slog.LevelError, "parse ui",
+		slog.String("error" , err.Error()),
+	)
+
+	rw.Header().Set("Content-Type", "text/html; charset=utf-8")
+	rw.WriteHeader(http.StatusInternalServerError)
+
+	fmt.Fprintf(rw, `<pre class="p-4 text-sm text-red-700 whitespace-pre-wrap">%v</pre>`, template.HTMLEscapeString(err.Error()))
+}
+
+func parse(ctx context.Context) (map[string]*template.Template, error) {
+	c     := make(map[string]*template.Template)
 	tmpls := make(map[string][]string)
 
 	const (
 		tmplRoot     = "html/category"
 		tmplTypePath = "html/template/*"
+		tmplShared   = "shared"
 	)
 
-	tmplTypes, err := fs.Glob(ui.Files, tmplTypePath)
+	tmplTypes, err := fs.Glob(ui.FS, tmplTypePath)
+	if err != nil {
+		return nil, err
+	}
+
+	// Templates in the shared directory are given to every page, signed in or not.
+	shared, err := fs.Glob(ui.FS, fmt.Sprintf("%v/%v/*.html", filepath.Dir(tmplTypePath), tmplShared))
 	if err != nil {
-		panic(fmt.Sprintf("%v", err.Error))
+		return nil, err
 	}
 
 	for _, tmplType := range tmplTypes {
-		files, err := fs.Glob(ui.Files, fmt.Sprintf("%v/*.html", tmplType))
+		if filepath.Base(tmplType) == tmplShared {
+			continue
+		}
+
+		files, err := fs.Glob(ui.FS, fmt.Sprintf("%v/*.html", tmplType))
 		if err != nil {
-			panic(fmt.Sprintf("%v", err.Error))
+			return nil, err
 		}
 
-		tmpls[filepath.Base(tmplType)] = files
+		tmpls[filepath.Base(tmplType)] = append(files, shared...)
 	}
 
-	wdErr := fs.WalkDir(ui.Files, tmplRoot, func(path string, d fs.DirEntry, err error) error {
+	wdErr := fs.WalkDir(ui.FS, tmplRoot, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
 		if d.IsDir() {
 			switch d.Name() {
 				case "content":
@@ -109,39 +263,39 @@ func InitCache(ctx context.Context){
 
 					files := append([]string{fmt.Sprintf("%v/index.html", path)}, tmpls[tmplType]...)
 
-					tmpls, tmplsErr := fs.Glob(ui.Files, fmt.Sprintf("%v/template/*.html", filepath.Dir(path)))
+					tmpls, tmplsErr := fs.Glob(ui.FS, fmt.Sprintf("%v/template/*.html", filepath.Dir(path)))
 					if tmplsErr != nil {
-						panic(fmt.Sprintf("%v", err.Error))
+						return tmplsErr
 					}
 
 					if tmpls != nil {
 						files = append(tmpls, files...)
 					}
 
-					tmpl, tmplErr := template.ParseFS(ui.Files, files...)
+					tmpl, tmplErr := template.New(filepath.Base(files[0])).Funcs(funcs).ParseFS(ui.FS, files...)
 					if tmplErr != nil {
-						panic(fmt.Sprintf("%v", tmplErr.Error()))
+						return tmplErr
 					}
 
-					cache[pageKey] = tmpl
+					c[pageKey] = tmpl
 				case "fragment", "template":
 					pageKey, _ := filepath.Rel(tmplRoot, path)
 
-					tmpls, tmplsErr := fs.Glob(ui.Files, fmt.Sprintf("%v/*.html", path))
+					tmpls, tmplsErr := fs.Glob(ui.FS, fmt.Sprintf("%v/*.html", path))
 					if tmplsErr != nil {
-						panic(fmt.Sprintf("%v", err.Error))
+						return tmplsErr
 					}
 
 					if tmpls != nil {
 						for _, t := range tmpls {
-							tmpl, tmplErr := template.ParseFS(ui.Files, t)
+							tmpl, tmplErr := template.New(filepath.Base(t)).Funcs(funcs).ParseFS(ui.FS, t)
 							if tmplErr != nil {
-								panic(fmt.Sprintf("%v", tmplErr.Error()))
+								return tmplErr
 							}
 
 							tmplKey := fmt.Sprintf("%v/%v", pageKey, strings.TrimSuffix(filepath.Base(t), ".html"))
 
-							cache[tmplKey] = tmpl
+							c[tmplKey] = tmpl
 						}
 					}
 			}
@@ -151,13 +305,12 @@ func InitCache(ctx context.Context){
 	})
 
 	if wdErr != nil {
-		slog.LogAttrs(ctx, slog.LevelError, "load templates",
-			slog.String("error" , wdErr.Error()),
-		)
-		panic(fmt.Sprintf("failed to load templates %v", wdErr.Error()))
+		return nil, wdErr
 	}
 
-	if len(cache) == 0 {
-		panic("The template cache is empty")
+	if len(c) == 0 {
+		return nil, fmt.Errorf("the template cache is empty")
 	}
+
+	return c, nil
 }
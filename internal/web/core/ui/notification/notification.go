@@ -12,13 +12,23 @@ import (
 )
 
 func Toast(ctx context.Context, logger *slog.Logger, rw http.ResponseWriter, r *http.Request, ntfType string, msg *map[string]string, data *page.Data){
+	ToastStatus(ctx, logger, rw, r, ntfType, msg, http.StatusOK, data)
+}
+
+// ToastStatus shows a toast with an error status. Nothing but the toast is
+// swapped into the page.
+func ToastStatus(ctx context.Context, logger *slog.Logger, rw http.ResponseWriter, r *http.Request, ntfType string, msg *map[string]string, status int, data *page.Data){
 	data.NotificationData = &map[string]any{"Type": ntfType, "Messages" : msg}
 
-	html.Fragment(ctx, logger, rw, r, "core/all/ntf/fragment/ntf", http.StatusCreated, data)
+	if status != http.StatusOK {
+		rw.Header().Set("HX-Reswap", "none")
+	}
+
+	html.Fragment(ctx, logger, rw, r, "core/all/ntf/fragment/ntf", status, data)
 }
 
 func Vrl (ctx context.Context, logger *slog.Logger, rw http.ResponseWriter, r *http.Request, title string, eq1ErrMg string, gt1ErrMsg string, msgs *[]string, data *page.Data) {
 	data.NotificationData = &map[string]any{"Title": title, "Singular": eq1ErrMg, "Plural": gt1ErrMsg, "Msgs": msgs}
 
-	html.Fragment(ctx, logger, rw, r, "core/all/ntf/fragment/vrl", http.StatusCreated, data)
+	html.Fragment(ctx, logger, rw, r, "core/all/ntf/fragment/vrl", http.StatusOK, data)
 }
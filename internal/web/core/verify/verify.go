@@ -0,0 +1,63 @@
+package verify
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+import (
+	"github.com/andrewah64/base-app-client/internal/common/core/mail"
+	"github.com/andrewah64/base-app-client/internal/common/core/tenant"
+	"github.com/andrewah64/base-app-client/internal/common/core/token"
+	"github.com/andrewah64/base-app-client/internal/web/core/ui/data/page"
+	"github.com/andrewah64/base-app-client/internal/web/core/ui/html"
+)
+
+const (
+	// Mins is how long a verification link can be used for.
+	Mins = 24 * 60
+	// Gap is how long a user has to wait before another link is sent.
+	Gap  = 5 * time.Minute
+)
+
+// Token returns a new verification token, which goes in the link, and its
+// hash, which is all the database keeps.
+func Token() (string, string, error) {
+	evrId, evrIdErr := token.Token(32)
+	if evrIdErr != nil {
+		return "", "", evrIdErr
+	}
+
+	return evrId, token.Hash(evrId), nil
+}
+
+// Message returns the email that sends the link to verify aurEa, in the
+// language of the page data.
+func Message(ctx context.Context, r *http.Request, data *page.Data, aurNm string, aurEa string, evrId string) (mail.Message, error) {
+	var (
+		link = fmt.Sprintf("%v/web/core/unauth/evr/aur/%v", tenant.Origin(r), evrId)
+		hrs  = strconv.Itoa(Mins / 60)
+		d    = *data
+	)
+
+	d.ResultSet = &map[string]any{
+		"AurNm" : aurNm,
+		"Link"  : link,
+		"Hrs"   : hrs,
+	}
+
+	htm, htmErr := html.Render(ctx, "core/unauth/evr/aur/fragment/mail", &d)
+	if htmErr != nil {
+		return mail.Message{}, htmErr
+	}
+
+	return mail.Message{
+		To      : aurEa,
+		Subject : data.T("web-core-unauth-evr-aur-mail.subject"),
+		Text    : data.T("web-core-unauth-evr-aur-mail.text", "aurNm", aurNm, "link", link, "hrs", hrs),
+		Html    : string(htm),
+	}, nil
+}
@@ -2,7 +2,44 @@ package ui
 
 import (
 	"embed"
+	"io/fs"
+	"os"
+	"time"
 )
 
 //go:embed "html" "static" "i18n"
 var Files embed.FS
+
+var (
+	// FS is where the UI is served from: Files, or the directory given to
+	// UseDir in dev mode.
+	FS  fs.FS = Files
+	Dev bool
+)
+
+// UseDir serves the UI from dir on disk so that changes to templates,
+// translations and static files are picked up without a rebuild.
+func UseDir(dir string) {
+	FS  = os.DirFS(dir)
+	Dev = true
+}
+
+// ModTime returns the time the most recently modified file or directory under
+// root was changed. Embedded files have no modification time.
+func ModTime(root string) time.Time {
+	var t time.Time
+
+	fs.WalkDir(FS, root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+
+		if info, infoErr := d.Info(); infoErr == nil && info.ModTime().After(t) {
+			t = info.ModTime()
+		}
+
+		return nil
+	})
+
+	return t
+}